@@ -1,19 +1,36 @@
 package main
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/installer/pkg/gather/cluster"
 	"github.com/openshift/installer/pkg/gather/service"
+	"github.com/openshift/installer/pkg/terraform"
 )
 
 var (
 	analyzeOpts struct {
 		gatherBundle string
+		output       string
 	}
+
+	analyzeTerraformOpts struct {
+		rules    string
+		platform string
+	}
+
+	// logMessageRegexp matches the message of a logrus text formatted line.
+	logMessageRegexp = regexp.MustCompile(`\bmsg=("(?:[^"\\]|\\.)*"|\S*)`)
 )
 
 func newAnalyzeCmd() *cobra.Command {
@@ -25,37 +42,159 @@ func newAnalyzeCmd() *cobra.Command {
 This command helps users to analyze the reasons for an installation that failed while bootstrapping.`,
 		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
-			gatherBundle := analyzeOpts.gatherBundle
-			if gatherBundle == "" {
-				var err error
-				gatherBundle, err = getGatherBundleFromAssetsDirectory()
-				if err != nil {
-					logrus.Fatal(err)
-				}
+			gatherBundle, err := getGatherBundle("log-bundle-*.tar.gz", "bootstrap gather")
+			if err != nil {
+				logrus.Fatal(err)
 			}
-			if !filepath.IsAbs(gatherBundle) {
-				gatherBundle = filepath.Join(rootOpts.dir, gatherBundle)
+			bundle, err := service.LoadGatherBundle(gatherBundle)
+			if err != nil {
+				logrus.Fatal(err)
 			}
-			if err := service.AnalyzeGatherBundle(gatherBundle); err != nil {
+			report := service.Analyze(bundle, service.DefaultChecks)
+			if err := writeAnalyzeReport(report, analyzeOpts.output); err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
 	cmd.PersistentFlags().StringVar(&analyzeOpts.gatherBundle, "file", "", "Filename of the bootstrap gather bundle; either absolute or relative to the assets directory")
+	cmd.Flags().StringVarP(&analyzeOpts.output, "output", "o", "text", "Output format of the report (e.g. \"text | json\")")
+	cmd.AddCommand(newAnalyzeClusterCmd())
+	cmd.AddCommand(newAnalyzeTerraformCmd())
 	return cmd
 }
 
-func getGatherBundleFromAssetsDirectory() (string, error) {
-	matches, err := filepath.Glob(filepath.Join(rootOpts.dir, "log-bundle-*.tar.gz"))
+func newAnalyzeClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Analyze a cluster bundle for degraded cluster operators",
+		Long: `Analyze a cluster bundle for degraded cluster operators.
+
+The bundle is the one written by "gather cluster"; --file selects it when the
+assets directory has more than one.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			clusterBundle, err := getGatherBundle("cluster-bundle-*.tar.gz", "cluster")
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			bundle, err := cluster.LoadBundle(clusterBundle)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			report := cluster.Analyze(bundle, cluster.DefaultChecks)
+			if err := writeAnalyzeReport(report, analyzeOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&analyzeOpts.output, "output", "o", "text", "Output format of the report (e.g. \"text | json\")")
+	return cmd
+}
+
+func newAnalyzeTerraformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform <log>",
+		Short: "Diagnose the terraform errors in an installer or terraform log",
+		Long: `Diagnose the terraform errors in an installer or terraform log.
+
+The log is matched against the builtin diagnostics rules and any rules loaded
+with --rules or $` + terraform.DiagnosticsRulesEnvVar + `, and every diagnosis is
+printed with its remediation.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if analyzeTerraformOpts.rules != "" {
+				if err := terraform.LoadRules(analyzeTerraformOpts.rules); err != nil {
+					logrus.Fatal(err)
+				}
+			}
+			message, err := readTerraformLog(args[0])
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			diagnoses := terraform.DiagnoseAll(analyzeTerraformOpts.platform, message)
+			if len(diagnoses) == 0 {
+				logrus.Info("No known terraform errors were found")
+				return
+			}
+			for i, diagnosis := range diagnoses {
+				if i > 0 {
+					os.Stdout.WriteString("\n")
+				}
+				diagnosis.Print(os.Stdout)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&analyzeTerraformOpts.rules, "rules", "", "YAML file with additional diagnostics rules")
+	cmd.Flags().StringVar(&analyzeTerraformOpts.platform, "platform", "", "only match the rules for this platform (e.g. \"aws\"); rules for all platforms are matched by default")
+	return cmd
+}
+
+// readTerraformLog returns the text of the log at path. The messages of
+// logrus formatted lines, like those of .openshift_install.log, are
+// unquoted so that multi-line terraform errors can be matched.
+func readTerraformLog(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read terraform log")
+	}
+
+	var messages []string
+	for _, line := range strings.Split(string(data), "\n") {
+		match := logMessageRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		message := match[1]
+		if strings.HasPrefix(message, `"`) {
+			if unquoted, err := strconv.Unquote(message); err == nil {
+				message = unquoted
+			}
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return string(data), nil
+	}
+	return strings.Join(messages, "\n"), nil
+}
+
+func writeAnalyzeReport(report *service.Report, output string) error {
+	switch output {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\" or \"json\"", output)
+	}
+}
+
+// getGatherBundle returns the path of the bundle given with --file, or of the
+// only bundle in the assets directory that matches pattern.
+func getGatherBundle(pattern, kind string) (string, error) {
+	gatherBundle := analyzeOpts.gatherBundle
+	if gatherBundle == "" {
+		return getGatherBundleFromAssetsDirectory(pattern, kind)
+	}
+	if !filepath.IsAbs(gatherBundle) {
+		gatherBundle = filepath.Join(rootOpts.dir, gatherBundle)
+	}
+	return gatherBundle, nil
+}
+
+func getGatherBundleFromAssetsDirectory(pattern, kind string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(rootOpts.dir, pattern))
 	if err != nil {
-		return "", errors.Wrap(err, "could not find gather bundles in assets directory")
+		return "", errors.Wrapf(err, "could not find %s bundles in assets directory", kind)
 	}
 	switch len(matches) {
 	case 0:
-		return "", errors.New("no bootstrap gather bundles found in assets directory")
+		return "", errors.Errorf("no %s bundles found in assets directory", kind)
 	case 1:
 		return matches[0], nil
 	default:
-		return "", errors.New("multiple bootstrap gather bundles found in assets directory; select specific gather bundle by using the --file flag")
+		return "", errors.Errorf("multiple %s bundles found in assets directory; select specific bundle by using the --file flag", kind)
 	}
 }
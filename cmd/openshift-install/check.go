@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/machines"
+	quotaasset "github.com/openshift/installer/pkg/asset/quota"
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types/ibmcloud"
+)
+
+var (
+	checkQuotaOpts struct {
+		output string
+	}
+)
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the cloud account of an installation before creating anything",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCheckQuotaCmd())
+	return cmd
+}
+
+func newCheckQuotaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Report whether the quotas of the cloud account are enough for the cluster",
+		Long: `Report whether the quotas of the cloud account are enough for the cluster.
+
+The machines of the cluster are generated from the install config in the
+assets directory like they are for the manifests, and the resources they
+need are checked against the quotas of the account, like the installer does
+before it creates the cluster. The install config is left in the assets
+directory.
+
+The command exits with a non-zero status when a quota is not enough for the
+cluster, or could not be found. The quotas of IBM Cloud cannot be read from
+its API, so they are checked against the default quotas of an account and
+only reported as warnings.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			reports, platform, err := runCheckQuotaCmd(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if reports == nil {
+				logrus.Infof("Quotas are not checked for platform %s", platform)
+				return
+			}
+			if err := writeQuotaReports(os.Stdout, reports, checkQuotaOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+			if quotaasset.Failing(reports) {
+				if platform == ibmcloud.Name {
+					logrus.Warn("The default quotas of IBM Cloud accounts are not enough for the cluster; ignore this if the quotas of your account were increased")
+					return
+				}
+				logrus.Fatal("The quotas of the account are not enough for the cluster")
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&checkQuotaOpts.output, "output", "o", "text", "Output format of the report (e.g. \"text | json\")")
+	return cmd
+}
+
+func runCheckQuotaCmd(directory string) ([]quota.ConstraintReport, string, error) {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create asset store")
+	}
+
+	ic := &installconfig.InstallConfig{}
+	mastersAsset := &machines.Master{}
+	workersAsset := &machines.Worker{}
+	// The install config and machines are preserved for the installation.
+	if err := assetStore.Fetch(ic); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to fetch %s", ic.Name())
+	}
+	if err := assetStore.Fetch(mastersAsset, ic); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to fetch %s", mastersAsset.Name())
+	}
+	if err := assetStore.Fetch(workersAsset, ic, mastersAsset); err != nil {
+		return nil, "", errors.Wrapf(err, "failed to fetch %s", workersAsset.Name())
+	}
+
+	masters, err := mastersAsset.Machines()
+	if err != nil {
+		return nil, "", err
+	}
+	workers, err := workersAsset.MachineSets()
+	if err != nil {
+		return nil, "", err
+	}
+
+	platform := ic.Config.Platform.Name()
+	reports, err := quotaasset.Reports(context.Background(), ic, masters, workers)
+	return reports, platform, err
+}
+
+func writeQuotaReports(out io.Writer, reports []quota.ConstraintReport, output string) error {
+	switch output {
+	case "text":
+		w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tREGION\tREQUIRED\tRESULT\tMESSAGE")
+		for _, report := range reports {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", report.For.Name, report.For.Region, report.For.Count, report.Result, report.Message)
+		}
+		return w.Flush()
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\" or \"json\"", output)
+	}
+}
@@ -27,11 +27,13 @@ import (
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/logging"
-	assetstore "github.com/openshift/installer/pkg/asset/store"
 	targetassets "github.com/openshift/installer/pkg/asset/targets"
 	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/events"
+	gathercluster "github.com/openshift/installer/pkg/gather/cluster"
 	"github.com/openshift/installer/pkg/gather/service"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/openshift/installer/pkg/types/baremetal"
@@ -99,6 +101,9 @@ var (
 			// FIXME: add longer descriptions for our commands with examples for better UX.
 			// Long:  "",
 			PostRun: func(_ *cobra.Command, _ []string) {
+				if createOpts.plan != "" {
+					return
+				}
 				ctx := context.Background()
 
 				cleanup := setupFileHook(rootOpts.dir)
@@ -113,6 +118,7 @@ var (
 
 				timer.StartTimer("Bootstrap Complete")
 				if err := waitForBootstrapComplete(ctx, config); err != nil {
+					recordMetricsFailure(err)
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
 					}
@@ -128,6 +134,7 @@ var (
 					logrus.Fatal("Bootstrap failed to complete")
 				}
 				timer.StopTimer("Bootstrap Complete")
+				events.Emit(events.Event{Type: events.BootstrapComplete})
 				timer.StartTimer("Bootstrap Destroy")
 
 				if oi, ok := os.LookupEnv("OPENSHIFT_INSTALL_PRESERVE_BOOTSTRAP"); ok && oi != "" {
@@ -137,21 +144,31 @@ var (
 					logrus.Info("Destroying the bootstrap resources...")
 					err = destroybootstrap.Destroy(rootOpts.dir)
 					if err != nil {
+						recordMetricsFailure(err)
 						logrus.Fatal(err)
 					}
+					events.Emit(events.Event{Type: events.BootstrapDestroyed})
 				}
 				timer.StopTimer("Bootstrap Destroy")
 
 				err = waitForInstallComplete(ctx, config, rootOpts.dir)
 				if err != nil {
+					recordMetricsFailure(err)
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
 					}
+					bundlePath, err2 := runGatherClusterCmd(ctx, config, rootOpts.dir)
+					if err2 != nil {
+						logrus.Error("Attempted to gather debug data after installation failure: ", err2)
+					} else if err2 := gathercluster.AnalyzeBundle(bundlePath); err2 != nil {
+						logrus.Error("Attempted to analyze the debug data after installation failure: ", err2)
+					}
 					logTroubleshootingLink()
 					logrus.Fatal(err)
 				}
 				timer.StopTimer(timer.TotalTimeElapsed)
 				timer.LogSummary()
+				finishMetricsReport()
 			},
 		},
 		assets: targetassets.Cluster,
@@ -218,21 +235,28 @@ func newCreateCmd() *cobra.Command {
 		},
 	}
 
+	addMetricsFlags(cmd)
+
 	for _, t := range targets {
 		t.command.Args = cobra.ExactArgs(0)
 		t.command.Run = runTargetCmd(t.assets...)
+		t.command.Flags().StringVar(&createOpts.plan, "plan", "", "report which assets would be generated, loaded or regenerated without creating anything (e.g. \"text | json\")")
+		t.command.Flags().Lookup("plan").NoOptDefVal = "text"
 		cmd.AddCommand(t.command)
 	}
+	cmd.AddCommand(newCreateIAMPolicyCmd())
+	clusterTarget.command.Flags().StringVar(&createOpts.fromStage, "from-stage", "", "name of the terraform stage to resume from, re-applying it and all later stages; by default the first stage that a previous attempt did not complete")
 
 	return cmd
 }
 
 func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
 	runner := func(directory string) error {
-		assetStore, err := assetstore.NewStore(directory)
+		assetStore, err := newAssetStore(directory)
 		if err != nil {
 			return errors.Wrap(err, "failed to create asset store")
 		}
+		defer recordMetricsAssets(assetStore)
 
 		for _, a := range targets {
 			err := assetStore.Fetch(a, targets...)
@@ -257,17 +281,33 @@ func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args
 	}
 
 	return func(cmd *cobra.Command, args []string) {
+		if createOpts.plan != "" {
+			if err := runPlan(rootOpts.dir, createOpts.plan, targets); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		}
+
 		timer.StartTimer(timer.TotalTimeElapsed)
 
+		for _, a := range targets {
+			if c, ok := a.(*cluster.Cluster); ok {
+				c.FromStage = createOpts.fromStage
+			}
+		}
+
 		cleanup := setupFileHook(rootOpts.dir)
 		defer cleanup()
 
+		startMetricsReport("create " + cmd.Name())
 		err := runner(rootOpts.dir)
 		if err != nil {
+			recordMetricsFailure(err)
 			logrus.Fatal(err)
 		}
 		if cmd.Name() != "cluster" {
 			logrus.Infof(logging.LogCreatedFiles(cmd.Name(), rootOpts.dir, targets))
+			finishMetricsReport()
 		}
 
 	}
@@ -420,7 +460,7 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	timeout := 40 * time.Minute
 
 	// Wait longer for baremetal, due to length of time it takes to boot
-	if assetStore, err := assetstore.NewStore(rootOpts.dir); err == nil {
+	if assetStore, err := newAssetStore(rootOpts.dir); err == nil {
 		if installConfig, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && installConfig != nil {
 			if installConfig.(*installconfig.InstallConfig).Config.Platform.Name() == baremetal.Name {
 				timeout = 60 * time.Minute
@@ -436,6 +476,10 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	clusterVersionContext, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if events.Enabled() {
+		go emitClusterOperatorConditionChanges(clusterVersionContext, cc)
+	}
+
 	failing := configv1.ClusterStatusConditionType("Failing")
 	timer.StartTimer("Cluster Operators")
 	var lastError string
@@ -485,6 +529,50 @@ func waitForInitializedCluster(ctx context.Context, config *rest.Config) error {
 	return errors.Wrap(err, "failed to initialize the cluster")
 }
 
+// emitClusterOperatorConditionChanges watches the ClusterOperators and emits
+// an event for every condition whose status changed, until ctx is done.
+func emitClusterOperatorConditionChanges(ctx context.Context, cc configclient.Interface) {
+	seen := map[string]configv1.ClusterOperatorStatusCondition{}
+	_, err := clientwatch.UntilWithSync(
+		ctx,
+		cache.NewListWatchFromClient(cc.ConfigV1().RESTClient(), "clusteroperators", "", fields.Everything()),
+		&configv1.ClusterOperator{},
+		nil,
+		func(event watch.Event) (bool, error) {
+			switch event.Type {
+			case watch.Added, watch.Modified:
+			default:
+				return false, nil
+			}
+			co, ok := event.Object.(*configv1.ClusterOperator)
+			if !ok {
+				return false, nil
+			}
+			for _, condition := range co.Status.Conditions {
+				key := co.Name + "/" + string(condition.Type)
+				if previous, ok := seen[key]; ok && previous.Status == condition.Status && previous.Reason == condition.Reason {
+					continue
+				}
+				seen[key] = condition
+				events.Emit(events.Event{
+					Type:     events.ClusterOperatorConditionChanged,
+					Operator: co.Name,
+					Condition: &events.Condition{
+						Type:    string(condition.Type),
+						Status:  string(condition.Status),
+						Reason:  condition.Reason,
+						Message: condition.Message,
+					},
+				})
+			}
+			return false, nil
+		},
+	)
+	if err != nil && err != wait.ErrWaitTimeout && ctx.Err() == nil {
+		logrus.Debugf("Stopped watching ClusterOperators: %v", err)
+	}
+}
+
 // waitForConsole returns the console URL from the route 'console' in namespace openshift-console
 func waitForConsole(ctx context.Context, config *rest.Config) (string, error) {
 	url := ""
@@ -549,6 +637,7 @@ func logComplete(directory, consoleURL string) error {
 	if err != nil {
 		return err
 	}
+	events.Emit(events.Event{Type: events.InstallComplete, ConsoleURL: consoleURL})
 	logrus.Info("Install complete!")
 	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
 	logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
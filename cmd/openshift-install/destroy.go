@@ -1,14 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/destroy"
 	_ "github.com/openshift/installer/pkg/destroy/aws"
 	_ "github.com/openshift/installer/pkg/destroy/azure"
@@ -20,7 +24,9 @@ import (
 	_ "github.com/openshift/installer/pkg/destroy/libvirt"
 	_ "github.com/openshift/installer/pkg/destroy/openstack"
 	_ "github.com/openshift/installer/pkg/destroy/ovirt"
+	"github.com/openshift/installer/pkg/destroy/providers"
 	_ "github.com/openshift/installer/pkg/destroy/vsphere"
+	"github.com/openshift/installer/pkg/events"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 )
 
@@ -33,26 +39,199 @@ func newDestroyCmd() *cobra.Command {
 			return cmd.Help()
 		},
 	}
+	addMetricsFlags(cmd)
 	cmd.AddCommand(newDestroyBootstrapCmd())
 	cmd.AddCommand(newDestroyClusterCmd())
+	cmd.AddCommand(newDestroyListOrphansCmd())
 	return cmd
 }
 
+var (
+	destroyOpts struct {
+		identity    destroy.ClusterIdentity
+		dryRun      bool
+		output      string
+		excludeDirs []string
+	}
+)
+
+// addClusterIdentityFlags adds the flags that identify a cluster, or the place
+// to look for clusters, without metadata.json.
+func addClusterIdentityFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&destroyOpts.identity.Platform, "platform", "", fmt.Sprintf("platform of the cluster (e.g. \"%s\")", strings.Join(destroy.OfflinePlatforms, " | ")))
+	flags.StringVar(&destroyOpts.identity.Region, "region", "", "region of the cluster")
+	flags.StringVar(&destroyOpts.identity.ProjectID, "project-id", "", "GCP project of the cluster")
+	flags.StringVar(&destroyOpts.identity.CloudName, "cloud-name", "", "Azure cloud environment of the cluster; defaults to \"AzurePublicCloud\"")
+	flags.StringVar(&destroyOpts.identity.ARMEndpoint, "arm-endpoint", "", "Azure Resource Manager endpoint of an Azure Stack Hub cluster")
+}
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
-		Args:  cobra.ExactArgs(0),
+		Long: `Destroy an OpenShift cluster.
+
+The cluster is identified by the metadata.json in the assets directory. A
+cluster whose assets directory was lost can be destroyed with --infra-id and
+--platform, together with the location of the cluster, e.g. --region for AWS
+and IBM Cloud or --project-id for GCP.
+
+With --dry-run, the resources that would be deleted are listed instead. This
+is supported on AWS, GCP and IBM Cloud.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if destroyOpts.dryRun {
+				if err := runDestroyPreviewCmd(rootOpts.dir, destroyOpts.identity, destroyOpts.output); err != nil {
+					logrus.Fatal(err)
+				}
+				return
+			}
+
+			startMetricsReport("destroy cluster")
+			var err error
+			if destroyOpts.identity.InfraID != "" {
+				err = runOfflineDestroyCmd(destroyOpts.identity)
+			} else {
+				err = runDestroyCmd(rootOpts.dir)
+			}
+			if err != nil {
+				recordMetricsFailure(err)
+				logrus.Fatal(err)
+			}
+			finishMetricsReport()
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&destroyOpts.identity.InfraID, "infra-id", "", "infrastructure ID of a cluster to destroy without its metadata.json")
+	addClusterIdentityFlags(cmd)
+	flags.StringVar(&destroyOpts.identity.ClusterName, "cluster-name", "", "name of the cluster; defaults to the infrastructure ID without its random suffix, and is required with --base-domain or --base-domain-resource-group when the infrastructure ID was truncated")
+	flags.StringVar(&destroyOpts.identity.BaseDomain, "base-domain", "", "base domain of the cluster, to remove its DNS records")
+	flags.StringVar(&destroyOpts.identity.ResourceGroup, "resource-group", "", "Azure or IBM Cloud resource group of the cluster; defaults to the one created by the installer")
+	flags.StringVar(&destroyOpts.identity.BaseDomainResourceGroup, "base-domain-resource-group", "", "Azure resource group of the base domain")
+	flags.BoolVar(&destroyOpts.dryRun, "dry-run", false, "list the resources that would be deleted without deleting them")
+	flags.StringVarP(&destroyOpts.output, "output", "o", "text", "output format of --dry-run (e.g. \"text | json\")")
+	return cmd
+}
+
+func newDestroyListOrphansCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-orphans",
+		Short: "List the clusters that have resources on a platform",
+		Long: `List the clusters that have resources on a platform.
+
+The clusters are found through the tags and labels that the installer puts on
+the resources it creates, and are listed by infrastructure ID. Clusters whose
+assets directory was lost can then be destroyed with "destroy cluster
+--infra-id".
+
+The clusters recorded in the metadata.json of the assets directory (--dir) or
+of any --exclude-dir still have their assets and are not listed. Other live
+clusters cannot be told apart from orphaned ones, so check that a listed
+cluster is no longer in use before destroying it.`,
+		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			err := runDestroyCmd(rootOpts.dir)
+			assetDirs := append([]string{rootOpts.dir}, destroyOpts.excludeDirs...)
+			orphans, err := destroy.ListOrphans(logrus.StandardLogger(), destroyOpts.identity, assetDirs)
 			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "failed to list clusters"))
+			}
+			if err := writeOrphans(orphans, destroyOpts.output); err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
+	addClusterIdentityFlags(cmd)
+	cmd.Flags().StringVarP(&destroyOpts.output, "output", "o", "text", "output format (e.g. \"text | json\")")
+	cmd.Flags().StringSliceVar(&destroyOpts.excludeDirs, "exclude-dir", nil, "assets directories of other clusters that are not orphaned")
+	return cmd
+}
+
+func writeOrphans(orphans []providers.OrphanedCluster, output string) error {
+	switch output {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INFRA ID\tRESOURCES")
+		for _, orphan := range orphans {
+			fmt.Fprintf(w, "%s\t%d\n", orphan.InfraID, orphan.Resources)
+		}
+		return w.Flush()
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(orphans)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\" or \"json\"", output)
+	}
+}
+
+func runDestroyPreviewCmd(directory string, identity destroy.ClusterIdentity, output string) error {
+	var destroyer providers.Destroyer
+	var err error
+	if identity.InfraID != "" {
+		destroyer, err = destroy.NewOffline(logrus.StandardLogger(), identity)
+	} else {
+		destroyer, err = destroy.New(logrus.StandardLogger(), directory)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+	}
+	previewer, ok := destroyer.(providers.Previewer)
+	if !ok {
+		return errors.New("--dry-run is not supported on the platform of the cluster")
+	}
+	resources, err := previewer.Preview()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the resources of the cluster")
+	}
+	return writeResources(resources, output)
+}
+
+func writeResources(resources []providers.Resource, output string) error {
+	switch output {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "ACTION\tKIND\tID\tREGION\tMATCHED TAG")
+		for _, resource := range resources {
+			action := resource.Action
+			if action == "" {
+				action = providers.ActionDelete
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", action, resource.Kind, resource.ID, resource.Region, resource.MatchedTag)
+		}
+		return w.Flush()
+	case "json":
+		if resources == nil {
+			resources = []providers.Resource{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(resources)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\" or \"json\"", output)
+	}
+}
+
+func runOfflineDestroyCmd(identity destroy.ClusterIdentity) error {
+	timer.StartTimer(timer.TotalTimeElapsed)
+	destroyer, err := destroy.NewOffline(logrus.StandardLogger(), identity)
+	if err != nil {
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+	}
+	if err := destroyer.Run(); err != nil {
+		return errors.Wrap(err, "Failed to destroy cluster")
+	}
+
+	events.Emit(events.Event{Type: events.ClusterDestroyed})
+	timer.StopTimer(timer.TotalTimeElapsed)
+	timer.LogSummary()
+	return nil
 }
 
 func runDestroyCmd(directory string) error {
@@ -65,7 +244,7 @@ func runDestroyCmd(directory string) error {
 		return errors.Wrap(err, "Failed to destroy cluster")
 	}
 
-	store, err := assetstore.NewStore(directory)
+	store, err := newAssetStore(directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create asset store")
 	}
@@ -95,7 +274,11 @@ func runDestroyCmd(directory string) error {
 			return errors.Wrapf(err, "failed to remove terraform file %q", f)
 		}
 	}
+	if err := os.Remove(filepath.Join(directory, cluster.CheckpointFileName)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove terraform checkpoint")
+	}
 
+	events.Emit(events.Event{Type: events.ClusterDestroyed})
 	timer.StopTimer(timer.TotalTimeElapsed)
 	timer.LogSummary()
 
@@ -112,12 +295,16 @@ func newDestroyBootstrapCmd() *cobra.Command {
 			defer cleanup()
 
 			timer.StartTimer(timer.TotalTimeElapsed)
+			startMetricsReport("destroy bootstrap")
 			err := bootstrap.Destroy(rootOpts.dir)
 			if err != nil {
+				recordMetricsFailure(err)
 				logrus.Fatal(err)
 			}
+			events.Emit(events.Event{Type: events.BootstrapDestroyed})
 			timer.StopTimer(timer.TotalTimeElapsed)
 			timer.LogSummary()
+			finishMetricsReport()
 		},
 	}
 }
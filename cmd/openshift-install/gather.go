@@ -18,11 +18,16 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
-	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/events"
+	"github.com/openshift/installer/pkg/gather/cluster"
+	"github.com/openshift/installer/pkg/gather/gatewayd"
 	"github.com/openshift/installer/pkg/gather/service"
 	"github.com/openshift/installer/pkg/gather/ssh"
 	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
@@ -42,6 +47,7 @@ to debug the installation failures`,
 		},
 	}
 	cmd.AddCommand(newGatherBootstrapCmd())
+	cmd.AddCommand(newGatherClusterCmd())
 	return cmd
 }
 
@@ -52,6 +58,10 @@ var (
 		sshKeys      []string
 		skipAnalysis bool
 	}
+
+	gatherClusterOpts struct {
+		skipAnalysis bool
+	}
 )
 
 func newGatherBootstrapCmd() *cobra.Command {
@@ -80,8 +90,57 @@ func newGatherBootstrapCmd() *cobra.Command {
 	return cmd
 }
 
+func newGatherClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Gather debugging data for a cluster that failed to complete the installation",
+		Long: `Gather debugging data for a cluster that failed to complete the installation.
+
+The ClusterVersion, the ClusterOperators, the nodes, the certificate signing
+requests, the MachineConfigPools, the events and the logs of the failing pods
+are collected with the admin kubeconfig into a cluster bundle. This is useful
+once the bootstrap resources have been destroyed.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+			config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+			if err != nil {
+				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
+			}
+			bundlePath, err := runGatherClusterCmd(context.Background(), config, rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if !gatherClusterOpts.skipAnalysis {
+				if err := cluster.AnalyzeBundle(bundlePath); err != nil {
+					logrus.Fatal(err)
+				}
+			}
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&gatherClusterOpts.skipAnalysis, "skipAnalysis", false, "Skip analysis of the gathered data")
+	return cmd
+}
+
+func runGatherClusterCmd(ctx context.Context, config *rest.Config, directory string) (string, error) {
+	logrus.Info("Pulling debug data from the cluster")
+	gatherID := time.Now().Format("20060102150405")
+	file, err := cluster.Gather(ctx, config, gatherID, directory)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat cluster bundle")
+	}
+	logrus.Infof("Cluster gather data captured here %q", path)
+	events.Emit(events.Event{Type: events.GatherComplete, Path: path})
+	return path, nil
+}
+
 func runGatherBootstrapCmd(directory string) (string, error) {
-	assetStore, err := assetstore.NewStore(directory)
+	assetStore, err := newAssetStore(directory)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create asset store")
 	}
@@ -135,11 +194,32 @@ func runGatherBootstrapCmd(directory string) (string, error) {
 		return "", errors.New("must provide both bootstrap host address and at least one control plane host address when providing one")
 	}
 
-	return logGatherBootstrap(bootstrap, port, masters, directory)
+	return logGatherBootstrap(bootstrap, port, masters, directory, assetStore)
 }
 
-func logGatherBootstrap(bootstrap string, port int, masters []string, directory string) (string, error) {
+func logGatherBootstrap(bootstrap string, port int, masters []string, directory string, assetStore asset.Store) (string, error) {
 	logrus.Info("Pulling debug logs from the bootstrap machine")
+	gatherID := time.Now().Format("20060102150405")
+	file, err := gatherBootstrapSSH(bootstrap, port, masters, directory, gatherID)
+	if err != nil {
+		logrus.Warnf("Failed to gather logs from the bootstrap machine over SSH: %v", err)
+		logrus.Info("Pulling the bootstrap journals through journal-gatewayd instead; the control plane machines will not be gathered")
+		var gatewaydErr error
+		file, gatewaydErr = gatherBootstrapGatewayd(bootstrap, directory, gatherID, assetStore)
+		if gatewaydErr != nil {
+			return "", utilerrors.NewAggregate([]error{err, gatewaydErr})
+		}
+	}
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat log file")
+	}
+	logrus.Infof("Bootstrap gather logs captured here %q", path)
+	events.Emit(events.Event{Type: events.GatherComplete, Path: path})
+	return path, nil
+}
+
+func gatherBootstrapSSH(bootstrap string, port int, masters []string, directory, gatherID string) (string, error) {
 	client, err := ssh.NewClient("core", net.JoinHostPort(bootstrap, strconv.Itoa(port)), gatherBootstrapOpts.sshKeys)
 	if err != nil {
 		if errors.Is(err, syscall.ECONNREFUSED) {
@@ -148,7 +228,6 @@ func logGatherBootstrap(bootstrap string, port int, masters []string, directory
 		return "", errors.Wrap(err, "failed to create SSH client")
 	}
 
-	gatherID := time.Now().Format("20060102150405")
 	if err := ssh.Run(client, fmt.Sprintf("/usr/local/bin/installer-gather.sh --id %s %s", gatherID, strings.Join(masters, " "))); err != nil {
 		return "", errors.Wrap(err, "failed to run remote command")
 	}
@@ -156,12 +235,30 @@ func logGatherBootstrap(bootstrap string, port int, masters []string, directory
 	if err := ssh.PullFileTo(client, fmt.Sprintf("/home/core/log-bundle-%s.tar.gz", gatherID), file); err != nil {
 		return "", errors.Wrap(err, "failed to pull log file from remote")
 	}
-	path, err := filepath.Abs(file)
+	return file, nil
+}
+
+func gatherBootstrapGatewayd(bootstrap, directory, gatherID string, assetStore asset.Store) (string, error) {
+	// The credentials are only loaded from the assets directory, so that gathering over SSH does not depend
+	// on them, nor on the install config and the CAs they would be generated from.
+	journalCertKey, err := assetStore.Load(&tls.JournalCertKey{})
 	if err != nil {
-		return "", errors.Wrap(err, "failed to stat log file")
+		return "", errors.Wrap(err, "failed to load the journal-gatewayd client certificate")
 	}
-	logrus.Infof("Bootstrap gather logs captured here %q", path)
-	return path, nil
+	rootCA, err := assetStore.Load(&tls.RootCA{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load the root CA")
+	}
+	if journalCertKey == nil || rootCA == nil {
+		return "", errors.New("the journal-gatewayd credentials are not in the assets directory")
+	}
+	certKey := journalCertKey.(*tls.JournalCertKey)
+	client, err := gatewayd.NewClient(net.JoinHostPort(bootstrap, strconv.Itoa(gatewayd.Port)), certKey.Cert(), certKey.Key(), rootCA.(*tls.RootCA).Cert())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create journal-gatewayd client")
+	}
+	file, err := gatewayd.Gather(context.TODO(), client, gatherID, directory)
+	return file, errors.Wrap(err, "failed to gather logs through journal-gatewayd")
 }
 
 func logClusterOperatorConditions(ctx context.Context, config *rest.Config) error {
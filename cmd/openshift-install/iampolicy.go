@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+var (
+	createIAMPolicyOpts struct {
+		split bool
+	}
+)
+
+func newCreateIAMPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam-policy",
+		Short: "Generates the IAM policy the installer needs for the install config",
+		Long: `Generates the IAM policy the installer needs for the install config.
+
+The policy grants the permissions that the installer checks before it
+creates the cluster, for the features of the install config in the assets
+directory, like creating the VPC or using an existing one. It is written to
+iam-policy.json in the assets directory:
+
+- on AWS, as an IAM policy document,
+- on Azure, as the definition of a custom role in the subscription of the
+  credentials, for "az role definition create",
+- on GCP, as the list of the predefined roles of the service account.
+
+With --split, the permissions to create the cluster are written to
+iam-policy-create.json and those to delete it to iam-policy-delete.json.
+
+The install config is left in the assets directory.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			files, err := runCreateIAMPolicyCmd(rootOpts.dir, createIAMPolicyOpts.split)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			for _, f := range files {
+				logrus.Infof("Created %s", filepath.Join(rootOpts.dir, f.Filename))
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&createIAMPolicyOpts.split, "split", false, "write the permissions to create and to delete the cluster to separate policies")
+	return cmd
+}
+
+func runCreateIAMPolicyCmd(directory string, split bool) ([]*asset.File, error) {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create asset store")
+	}
+
+	ic := &installconfig.InstallConfig{}
+	if err := assetStore.Fetch(ic); err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", ic.Name())
+	}
+	if err := asset.PersistToFile(ic, directory); err != nil {
+		return nil, errors.Wrapf(err, "failed to write asset (%s) to disk", ic.Name())
+	}
+
+	files, err := installconfig.IAMPolicyFiles(ic, split)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, f.Filename), f.Data, 0640); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", f.Filename)
+		}
+	}
+	return files, nil
+}
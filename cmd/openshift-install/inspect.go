@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+var (
+	inspectCertsOpts struct {
+		output string
+	}
+)
+
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect the assets of an installation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newInspectCertsCmd())
+	return cmd
+}
+
+func newInspectCertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Report the certificates generated for the installation and their expiry",
+		Long: `Report the certificates generated for the installation and their expiry.
+
+The certificates are loaded from the state file of the assets directory and
+printed as a tree from each CA to the certificates it signed. Expired
+certificates, and short-lived ones that are only valid for a day, like the
+kubelet bootstrap certificate, are flagged: ignition configs that embed them
+cannot be reused once they expire.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			inventory, err := runInspectCertsCmd(rootOpts.dir)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := writeCertInventory(inventory, inspectCertsOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+			for _, cert := range inventory.Flagged() {
+				if cert.Expired {
+					logrus.Warnf("Certificate %s expired at %s", cert.Asset, cert.NotAfter.UTC().Format(time.RFC3339))
+				} else {
+					logrus.Warnf("Certificate %s is short-lived and expires at %s", cert.Asset, cert.NotAfter.UTC().Format(time.RFC3339))
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&inspectCertsOpts.output, "output", "o", "text", "Output format of the report (e.g. \"text | json\")")
+	return cmd
+}
+
+func runInspectCertsCmd(directory string) (*tls.Inventory, error) {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create asset store")
+	}
+
+	var certs []tls.CertKeyAsset
+	for _, cert := range tls.CertKeyAssets() {
+		loaded, err := assetStore.Load(cert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load %s", cert.Name())
+		}
+		if loaded == nil {
+			logrus.Debugf("%s has not been generated", cert.Name())
+			continue
+		}
+		certs = append(certs, loaded.(tls.CertKeyAsset))
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found in the assets directory")
+	}
+	return tls.NewInventory(certs, time.Now())
+}
+
+func writeCertInventory(inventory *tls.Inventory, output string) error {
+	switch output {
+	case "text":
+		return inventory.WriteText(os.Stdout)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inventory)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\" or \"json\"", output)
+	}
+}
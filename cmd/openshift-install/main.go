@@ -14,14 +14,25 @@ import (
 	"k8s.io/klog"
 	klogv2 "k8s.io/klog/v2"
 
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/events"
+	"github.com/openshift/installer/pkg/terraform"
 	"github.com/openshift/installer/pkg/terraform/exec/plugins"
 )
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir          string
+		logLevel     string
+		stateBackend string
+		eventStream  string
 	}
+
+	// closeEventStream flushes and closes the event stream opened with
+	// --event-stream.
+	closeEventStream = func() {}
 )
 
 func main() {
@@ -59,16 +70,23 @@ func installerMain() {
 		newWaitForCmd(),
 		newGatherCmd(),
 		newAnalyzeCmd(),
+		newInspectCmd(),
 		newVersionCmd(),
 		newGraphCmd(),
 		newCoreOSCmd(),
 		newCompletionCmd(),
 		newMigrateCmd(),
 		newExplainCmd(),
+		newValidateCmd(),
+		newCheckCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
 	}
 
+	// Commands that fail exit through logrus.Fatal, which does not run
+	// deferred functions, so the event stream is also closed by an exit
+	// handler.
+	defer func() { closeEventStream() }()
 	if err := rootCmd.Execute(); err != nil {
 		logrus.Fatalf("Error executing openshift-install: %v", err)
 	}
@@ -85,6 +103,8 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
 	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.stateBackend, "state-backend", os.Getenv(assetstore.StateBackendEnvVar), "where to keep the installer state (e.g. \"file:// | s3://<bucket>/<key> | secret://<namespace>/<name>\"); defaults to $"+assetstore.StateBackendEnvVar+" or the assets directory")
+	cmd.PersistentFlags().StringVar(&rootOpts.eventStream, "event-stream", "", "file or file descriptor (e.g. \"fd://3\") to write install progress events to as JSON lines")
 	return cmd
 }
 
@@ -112,4 +132,31 @@ func runRootCmd(cmd *cobra.Command, args []string) {
 	if err != nil {
 		logrus.Fatal(errors.Wrap(err, "invalid log-level"))
 	}
+
+	if rootOpts.eventStream != "" {
+		closeEvents, err := events.Open(rootOpts.eventStream)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		closeEventStream = closeEvents
+		logrus.RegisterExitHandler(closeEvents)
+	}
+
+	tls.RegisterAssetsDirectory(rootOpts.dir)
+
+	if rules := os.Getenv(terraform.DiagnosticsRulesEnvVar); rules != "" {
+		if err := terraform.LoadRules(rules); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
+// newAssetStore returns an asset store for the given directory that keeps
+// its state in the backend selected with --state-backend.
+func newAssetStore(directory string) (asset.Store, error) {
+	backend, err := assetstore.NewBackend(directory, rootOpts.stateBackend)
+	if err != nil {
+		return nil, err
+	}
+	return assetstore.NewStoreWithBackend(directory, backend)
 }
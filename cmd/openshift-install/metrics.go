@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/metrics/pushclient"
+	"github.com/openshift/installer/pkg/metrics/report"
+	timer "github.com/openshift/installer/pkg/metrics/timer"
+)
+
+var (
+	metricsOpts struct {
+		pushgatewayURL string
+		jobName        string
+	}
+
+	// metricsReport is the report of the running command. It is nil when
+	// metrics reporting is disabled.
+	metricsReport *report.Report
+
+	// errFatal is reported for commands that exit through logrus.Fatal
+	// without recording their error.
+	errFatal = errors.New("command failed")
+)
+
+// addMetricsFlags adds the flags that opt in to Pushgateway reporting.
+func addMetricsFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&metricsOpts.pushgatewayURL, "metrics-pushgateway-url", os.Getenv("OPENSHIFT_INSTALL_METRICS_PUSHGATEWAY_URL"), "Prometheus Pushgateway to report the outcome of the command to; reporting is disabled when empty")
+	flags.StringVar(&metricsOpts.jobName, "metrics-job-name", "openshift-install", "Pushgateway job name for the reported metrics")
+}
+
+// startMetricsReport begins reporting metrics for the named command, if
+// enabled. A failure report is pushed when the command exits through
+// logrus.Fatal; call recordMetricsFailure beforehand to attach the error.
+// The platform is taken from metadata.json, when the cluster has one;
+// commands that fetch assets fill in the rest with recordMetricsAssets.
+func startMetricsReport(command string) {
+	if metricsOpts.pushgatewayURL == "" {
+		return
+	}
+	metricsReport = &report.Report{Command: command}
+	if metadata, err := cluster.LoadMetadata(rootOpts.dir); err == nil {
+		metricsReport.Platform = metadata.Platform()
+	}
+	logrus.RegisterExitHandler(func() {
+		if metricsReport.Err == nil {
+			metricsReport.Err = errFatal
+		}
+		pushMetricsReport()
+	})
+}
+
+// recordMetricsAssets fills in the platform and release image of the metrics
+// report from the assets that the command loaded into assetStore. Loading
+// them again from the same store does not read the state backend.
+func recordMetricsAssets(assetStore asset.Store) {
+	if metricsReport == nil {
+		return
+	}
+	if config, err := assetStore.Load(&installconfig.InstallConfig{}); err == nil && config != nil {
+		metricsReport.Platform = config.(*installconfig.InstallConfig).Config.Platform.Name()
+	}
+	if image, err := assetStore.Load(&releaseimage.Image{}); err == nil && image != nil {
+		metricsReport.ReleaseImage = image.(*releaseimage.Image).PullSpec
+	}
+}
+
+// recordMetricsFailure attaches the error the command is about to fail with
+// to the metrics report.
+func recordMetricsFailure(err error) {
+	if metricsReport != nil {
+		metricsReport.Err = err
+	}
+}
+
+// finishMetricsReport pushes a success report for the command.
+func finishMetricsReport() {
+	if metricsReport == nil {
+		return
+	}
+	pushMetricsReport()
+}
+
+func pushMetricsReport() {
+	metricsReport.StageTimes = timer.StageTimes()
+	metricsReport.Finished = time.Now()
+	client := &pushclient.PushClient{
+		URL:     metricsOpts.pushgatewayURL,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		JobName: metricsOpts.jobName,
+	}
+	if err := report.Push(client, metricsReport); err != nil {
+		logrus.Warnf("Failed to report metrics to %s: %v", metricsOpts.pushgatewayURL, err)
+		return
+	}
+	logrus.Debugf("Reported metrics to %s", metricsOpts.pushgatewayURL)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	azure "github.com/openshift/installer/cmd/openshift-install/migrate/azure"
+	"github.com/openshift/installer/cmd/openshift-install/migrate/installconfig"
 )
 
 func newMigrateCmd() *cobra.Command {
@@ -18,6 +19,7 @@ func newMigrateCmd() *cobra.Command {
 
 	migrateCmd.AddCommand(azure.NewMigrateAzurePrivateDNSEligibleCmd())
 	migrateCmd.AddCommand(azure.NewMigrateAzurePrivateDNSMigrateCmd())
+	migrateCmd.AddCommand(installconfig.NewMigrateInstallConfigCmd())
 
 	return migrateCmd
 }
@@ -0,0 +1,106 @@
+package installconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/lint"
+)
+
+var (
+	installConfigMigrateOpts struct {
+		check bool
+	}
+)
+
+func runMigrateInstallConfigCmd(cmd *cobra.Command, args []string) error {
+	outdated := 0
+	for _, filename := range args {
+		migrated, err := migrateInstallConfig(filename)
+		if err != nil {
+			return errors.Wrap(err, filename)
+		}
+		if migrated {
+			outdated++
+		}
+	}
+	if installConfigMigrateOpts.check && outdated > 0 {
+		return errors.Errorf("%d of %d install config(s) need to be migrated", outdated, len(args))
+	}
+	return nil
+}
+
+// migrateInstallConfig migrates the install config in filename and prints
+// the diff of the changes. It returns whether the install config needed to
+// be migrated.
+func migrateInstallConfig(filename string) (bool, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+	migrated, changes, err := lint.MigrateInstallConfig(data)
+	if err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		logrus.Infof("%s is up to date", filename)
+		return false, nil
+	}
+	for _, change := range changes {
+		logrus.Infof("%s: %s: %s", filename, change.Field, change.Message)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(data)),
+		B:        difflib.SplitLines(string(migrated)),
+		FromFile: filename,
+		ToFile:   filename,
+		Context:  3,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to diff the migrated install config")
+	}
+	fmt.Print(diff)
+
+	if installConfigMigrateOpts.check {
+		return true, nil
+	}
+	if err := ioutil.WriteFile(filename, migrated, info.Mode()); err != nil {
+		return false, errors.Wrap(err, "failed to write the migrated install config")
+	}
+	return true, nil
+}
+
+// NewMigrateInstallConfigCmd adds the install-config migrate command to openshift-install
+func NewMigrateInstallConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-config FILE...",
+		Short: "Rewrite the deprecated fields of install configs",
+		Long: `Rewrite the deprecated fields of install configs to the fields that replace them.
+
+The deprecated fields are upconverted the same way the installer does when it
+loads an install config, like networking.machineCIDR to
+networking.machineNetwork. Only the lines of the deprecated fields are
+rewritten, so comments and the order of the fields are kept. The diff of the
+changes of each file is printed.
+
+With --check, the files are not written, and the command exits with a
+non-zero status when any of them needs to be migrated.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runMigrateInstallConfigCmd,
+	}
+
+	cmd.PersistentFlags().BoolVar(&installConfigMigrateOpts.check, "check", false, "Only report the install configs that need to be migrated, without writing them")
+
+	return cmd
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+var (
+	createOpts struct {
+		plan      string
+		fromStage string
+	}
+)
+
+// runPlan reports how the given targets would be fetched without generating
+// any assets or creating any infrastructure.
+func runPlan(directory, format string, targets []asset.WritableAsset) error {
+	assetStore, err := newAssetStore(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+
+	assets := make([]asset.Asset, 0, len(targets))
+	for _, a := range targets {
+		assets = append(assets, a)
+	}
+	plan, err := assetStore.Plan(assets...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		return printPlanJSON(os.Stdout, plan)
+	case "text":
+		return printPlanText(os.Stdout, plan)
+	default:
+		return errors.Errorf("invalid plan format %q; must be \"text\" or \"json\"", format)
+	}
+}
+
+func printPlanJSON(out io.Writer, plan []asset.PlannedAsset) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan)
+}
+
+func printPlanText(out io.Writer, plan []asset.PlannedAsset) error {
+	counts := map[asset.PlanAction]int{}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tASSET\tDETAILS")
+	for _, p := range plan {
+		counts[p.Action]++
+		var details []string
+		if p.OnDisk {
+			details = append(details, "present in target directory")
+		}
+		if len(p.DirtyParents) > 0 {
+			details = append(details, fmt.Sprintf("dirty parents: %s", strings.Join(p.DirtyParents, ", ")))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Action, p.Name, strings.Join(details, "; "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\nPlan: %d to generate, %d to regenerate, %d from target directory, %d from state file.\n",
+		counts[asset.PlanGenerate], counts[asset.PlanRegenerate], counts[asset.PlanLoadFromDisk], counts[asset.PlanLoadFromState])
+	return err
+}
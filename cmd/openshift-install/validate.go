@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/lint"
+)
+
+var (
+	validateInstallConfigOpts struct {
+		output string
+	}
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the inputs of an installation without creating anything",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newValidateInstallConfigCmd())
+	return cmd
+}
+
+func newValidateInstallConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-config [FILE]",
+		Short: "Check an install config offline and report all of its problems",
+		Long: `Check an install config offline and report all of its problems.
+
+The install config is read from FILE, or from install-config.yaml in the
+assets directory, and is converted, defaulted and validated like the
+installer does, without contacting the cloud. The offline validations that
+the installer only runs when it provisions the infrastructure are reported
+as warnings, as are unknown and deprecated fields. Each problem is reported
+with the path of its field, its position in the file and, when there is one,
+a likely fix.
+
+The command exits with a non-zero status when there are errors, so it can
+be used in pre-merge checks.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			filename := filepath.Join(rootOpts.dir, "install-config.yaml")
+			if len(args) > 0 {
+				filename = args[0]
+			}
+			report, err := runValidateInstallConfigCmd(filename)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			if err := writeLintReport(report, validateInstallConfigOpts.output); err != nil {
+				logrus.Fatal(err)
+			}
+			if errs := report.Errors(); errs > 0 {
+				logrus.Fatalf("%s has %d error(s)", filename, errs)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&validateInstallConfigOpts.output, "output", "o", "text", "Output format of the report (e.g. \"text | json | sarif\")")
+	return cmd
+}
+
+func runValidateInstallConfigCmd(filename string) (*lint.Report, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the install config")
+	}
+	return lint.InstallConfig(filename, data)
+}
+
+func writeLintReport(report *lint.Report, output string) error {
+	switch output {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	case "sarif":
+		return report.WriteSARIF(os.Stdout)
+	default:
+		return errors.Errorf("invalid output format %q; must be \"text\", \"json\" or \"sarif\"", output)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 
+	"github.com/openshift/installer/pkg/events"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -24,6 +25,7 @@ them directly.`,
 			return cmd.Help()
 		},
 	}
+	addMetricsFlags(cmd)
 	cmd.AddCommand(newWaitForBootstrapCompleteCmd())
 	cmd.AddCommand(newWaitForInstallCompleteCmd())
 	return cmd
@@ -45,8 +47,10 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 			if err != nil {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
+			startMetricsReport("wait-for bootstrap-complete")
 			timer.StartTimer("Bootstrap Complete")
 			if err := waitForBootstrapComplete(ctx, config); err != nil {
+				recordMetricsFailure(err)
 				if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 					logrus.Error("Attempted to gather ClusterOperator status after wait failure: ", err2)
 				}
@@ -60,8 +64,10 @@ func newWaitForBootstrapCompleteCmd() *cobra.Command {
 
 			logrus.Info("It is now safe to remove the bootstrap resources")
 			timer.StopTimer("Bootstrap Complete")
+			events.Emit(events.Event{Type: events.BootstrapComplete})
 			timer.StopTimer(timer.TotalTimeElapsed)
 			timer.LogSummary()
+			finishMetricsReport()
 		},
 	}
 }
@@ -83,8 +89,10 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 				logrus.Fatal(errors.Wrap(err, "loading kubeconfig"))
 			}
 
+			startMetricsReport("wait-for install-complete")
 			err = waitForInstallComplete(ctx, config, rootOpts.dir)
 			if err != nil {
+				recordMetricsFailure(err)
 				if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 					logrus.Error("Attempted to gather ClusterOperator status after wait failure: ", err2)
 				}
@@ -93,6 +101,7 @@ func newWaitForInstallCompleteCmd() *cobra.Command {
 			}
 			timer.StopTimer(timer.TotalTimeElapsed)
 			timer.LogSummary()
+			finishMetricsReport()
 		},
 	}
 }
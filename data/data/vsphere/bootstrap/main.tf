@@ -31,11 +31,15 @@ resource "vsphere_virtual_machine" "vm" {
     template_uuid = var.template
   }
 
-  extra_config = {
-    "guestinfo.ignition.config.data"          = base64encode(var.ignition)
-    "guestinfo.ignition.config.data.encoding" = "base64"
-    "guestinfo.hostname"                      = "${var.cluster_id}-bootstrap"
-  }
+  extra_config = merge(
+    {
+      "guestinfo.ignition.config.data"          = base64encode(var.ignition)
+      "guestinfo.ignition.config.data.encoding" = "base64"
+      "guestinfo.hostname"                      = "${var.cluster_id}-bootstrap"
+    },
+    // Afterburn passes the static network configuration, if any, to the initramfs.
+    { for kargs in compact([var.network_kargs]) : "guestinfo.afterburn.initrd.network-kargs" => kargs }
+  )
   tags = var.tags
 }
 
@@ -45,4 +45,8 @@ variable "thin_disk" {
 
 variable "scrub_disk" {
   type = bool
+}
+
+variable "network_kargs" {
+  type = string
 }
\ No newline at end of file
@@ -1,6 +1,13 @@
 locals {
-  folder      = var.vsphere_preexisting_folder ? var.vsphere_folder : vsphere_folder.folder[0].path
   description = "Created By OpenShift Installer"
+
+  // The failure domain of the bootstrap machine.
+  bootstrap = var.vsphere_control_plane_failure_domains[0]
+
+  // The folders created by the installer, grouped by datacenter and path.
+  folders = {
+    for name, fd in var.vsphere_failure_domains : "${fd.datacenter}/${fd.folder}" => fd... if !fd.preexisting_folder
+  }
 }
 
 provider "vsphere" {
@@ -18,38 +25,52 @@ provider "vsphereprivate" {
 }
 
 data "vsphere_datacenter" "datacenter" {
-  name = var.vsphere_datacenter
+  for_each = toset([for fd in var.vsphere_failure_domains : fd.datacenter])
+
+  name = each.key
 }
 
 data "vsphere_compute_cluster" "cluster" {
-  name          = var.vsphere_cluster
-  datacenter_id = data.vsphere_datacenter.datacenter.id
+  for_each = var.vsphere_failure_domains
+
+  name          = each.value.cluster
+  datacenter_id = data.vsphere_datacenter.datacenter[each.value.datacenter].id
 }
 
 data "vsphere_datastore" "datastore" {
-  name          = var.vsphere_datastore
-  datacenter_id = data.vsphere_datacenter.datacenter.id
+  for_each = var.vsphere_failure_domains
+
+  name          = each.value.datastore
+  datacenter_id = data.vsphere_datacenter.datacenter[each.value.datacenter].id
 }
 
 data "vsphere_network" "network" {
-  name          = var.vsphere_network
-  datacenter_id = data.vsphere_datacenter.datacenter.id
+  for_each = var.vsphere_failure_domains
+
+  name          = each.value.network
+  datacenter_id = data.vsphere_datacenter.datacenter[each.value.datacenter].id
 }
 
 data "vsphere_virtual_machine" "template" {
-  name          = vsphereprivate_import_ova.import.name
-  datacenter_id = data.vsphere_datacenter.datacenter.id
+  for_each = var.vsphere_failure_domains
+
+  name          = vsphereprivate_import_ova.import[each.key].name
+  datacenter_id = data.vsphere_datacenter.datacenter[each.value.datacenter].id
 }
 
 resource "vsphereprivate_import_ova" "import" {
-  name       = var.vsphere_template
+  for_each = var.vsphere_failure_domains
+
+  name       = each.key
   filename   = var.vsphere_ova_filepath
-  cluster    = var.vsphere_cluster
-  datacenter = var.vsphere_datacenter
-  datastore  = var.vsphere_datastore
-  network    = var.vsphere_network
-  folder     = local.folder
+  cluster    = each.value.cluster
+  datacenter = each.value.datacenter
+  datastore  = each.value.datastore
+  network    = each.value.network
+  folder     = each.value.folder
   tag        = vsphere_tag.tag.id
+
+  depends_on = [vsphere_folder.folder]
 }
 
 resource "vsphere_tag_category" "category" {
@@ -73,11 +94,11 @@ resource "vsphere_tag" "tag" {
 }
 
 resource "vsphere_folder" "folder" {
-  count = var.vsphere_preexisting_folder ? 0 : 1
+  for_each = local.folders
 
-  path          = var.vsphere_folder
+  path          = each.value[0].folder
   type          = "vm"
-  datacenter_id = data.vsphere_datacenter.datacenter.id
+  datacenter_id = data.vsphere_datacenter.datacenter[each.value[0].datacenter].id
   tags          = [vsphere_tag.tag.id]
 }
 
@@ -86,15 +107,16 @@ module "bootstrap" {
   source = "./bootstrap"
 
   ignition      = var.ignition_bootstrap
-  resource_pool = data.vsphere_compute_cluster.cluster.resource_pool_id
-  datastore     = data.vsphere_datastore.datastore.id
-  folder        = local.folder
-  network       = data.vsphere_network.network.id
-  datacenter    = data.vsphere_datacenter.datacenter.id
-  template      = data.vsphere_virtual_machine.template.id
-  guest_id      = data.vsphere_virtual_machine.template.guest_id
-  thin_disk     = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
-  scrub_disk    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
+  resource_pool = data.vsphere_compute_cluster.cluster[local.bootstrap].resource_pool_id
+  datastore     = data.vsphere_datastore.datastore[local.bootstrap].id
+  folder        = var.vsphere_failure_domains[local.bootstrap].folder
+  network       = data.vsphere_network.network[local.bootstrap].id
+  datacenter    = data.vsphere_datacenter.datacenter[var.vsphere_failure_domains[local.bootstrap].datacenter].id
+  template      = data.vsphere_virtual_machine.template[local.bootstrap].id
+  guest_id      = data.vsphere_virtual_machine.template[local.bootstrap].guest_id
+  thin_disk     = data.vsphere_virtual_machine.template[local.bootstrap].disks.0.thin_provisioned
+  scrub_disk    = data.vsphere_virtual_machine.template[local.bootstrap].disks.0.eagerly_scrub
+  network_kargs = var.vsphere_bootstrap_network_kargs
 
   cluster_id = var.cluster_id
   tags       = [vsphere_tag.tag.id]
@@ -108,16 +130,16 @@ module "master" {
   instance_count = var.master_count
   ignition       = var.ignition_master
 
-  resource_pool = data.vsphere_compute_cluster.cluster.resource_pool_id
-  datastore     = data.vsphere_datastore.datastore.id
-  folder        = local.folder
-  network       = data.vsphere_network.network.id
-  datacenter    = data.vsphere_datacenter.datacenter.id
-  template      = data.vsphere_virtual_machine.template.id
-  guest_id      = data.vsphere_virtual_machine.template.guest_id
-  thin_disk     = data.vsphere_virtual_machine.template.disks.0.thin_provisioned
-  scrub_disk    = data.vsphere_virtual_machine.template.disks.0.eagerly_scrub
-  tags          = [vsphere_tag.tag.id]
+  resource_pools = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_compute_cluster.cluster[fd].resource_pool_id]
+  datastores     = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_datastore.datastore[fd].id]
+  folders        = [for fd in var.vsphere_control_plane_failure_domains : var.vsphere_failure_domains[fd].folder]
+  networks       = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_network.network[fd].id]
+  templates      = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_virtual_machine.template[fd].id]
+  guest_ids      = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_virtual_machine.template[fd].guest_id]
+  thin_disks     = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_virtual_machine.template[fd].disks.0.thin_provisioned]
+  scrub_disks    = [for fd in var.vsphere_control_plane_failure_domains : data.vsphere_virtual_machine.template[fd].disks.0.eagerly_scrub]
+  network_kargs  = var.vsphere_control_plane_network_kargs
+  tags           = [vsphere_tag.tag.id]
 
   cluster_domain   = var.cluster_domain
   cluster_id       = var.cluster_id
@@ -126,4 +148,3 @@ module "master" {
   cores_per_socket = var.vsphere_control_plane_cores_per_socket
   disk_size        = var.vsphere_control_plane_disk_gib
 }
-
@@ -6,13 +6,13 @@ resource "vsphere_virtual_machine" "vm" {
   count = var.instance_count
 
   name                 = "${var.cluster_id}-${var.name}-${count.index}"
-  resource_pool_id     = var.resource_pool
-  datastore_id         = var.datastore
+  resource_pool_id     = var.resource_pools[count.index]
+  datastore_id         = var.datastores[count.index]
   num_cpus             = var.num_cpus
   num_cores_per_socket = var.cores_per_socket
   memory               = var.memory
-  guest_id             = var.guest_id
-  folder               = var.folder
+  guest_id             = var.guest_ids[count.index]
+  folder               = var.folders[count.index]
   enable_disk_uuid     = "true"
   annotation           = local.description
 
@@ -20,25 +20,29 @@ resource "vsphere_virtual_machine" "vm" {
   wait_for_guest_net_routable = "false"
 
   network_interface {
-    network_id = var.network
+    network_id = var.networks[count.index]
   }
 
   disk {
     label            = "disk0"
     size             = var.disk_size
-    eagerly_scrub    = var.scrub_disk
-    thin_provisioned = var.thin_disk
+    eagerly_scrub    = var.scrub_disks[count.index]
+    thin_provisioned = var.thin_disks[count.index]
   }
 
   clone {
-    template_uuid = var.template
+    template_uuid = var.templates[count.index]
   }
 
-  extra_config = {
-    "guestinfo.ignition.config.data"          = base64encode(var.ignition)
-    "guestinfo.ignition.config.data.encoding" = "base64"
-    "guestinfo.hostname"                      = "${var.cluster_id}-${var.name}-${count.index}"
-  }
+  extra_config = merge(
+    {
+      "guestinfo.ignition.config.data"          = base64encode(var.ignition)
+      "guestinfo.ignition.config.data.encoding" = "base64"
+      "guestinfo.hostname"                      = "${var.cluster_id}-${var.name}-${count.index}"
+    },
+    // Afterburn passes the static network configuration, if any, to the initramfs.
+    { for kargs in compact([var.network_kargs[count.index]]) : "guestinfo.afterburn.initrd.network-kargs" => kargs }
+  )
 
   tags = var.tags
 }
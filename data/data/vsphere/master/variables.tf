@@ -11,36 +11,32 @@ variable "ignition" {
   default = ""
 }
 
-variable "resource_pool" {
-  type = string
+variable "resource_pools" {
+  type = list(string)
 }
 
-variable "folder" {
-  type = string
+variable "folders" {
+  type = list(string)
 }
 
-variable "datastore" {
-  type = string
+variable "datastores" {
+  type = list(string)
 }
 
-variable "network" {
-  type = string
+variable "networks" {
+  type = list(string)
 }
 
 variable "cluster_domain" {
   type = string
 }
 
-variable "datacenter" {
-  type = string
+variable "templates" {
+  type = list(string)
 }
 
-variable "template" {
-  type = string
-}
-
-variable "guest_id" {
-  type = string
+variable "guest_ids" {
+  type = list(string)
 }
 
 variable "memory" {
@@ -67,10 +63,14 @@ variable "cluster_id" {
   type = string
 }
 
-variable "thin_disk" {
-  type = bool
+variable "thin_disks" {
+  type = list(bool)
+}
+
+variable "scrub_disks" {
+  type = list(bool)
 }
 
-variable "scrub_disk" {
-  type = bool
+variable "network_kargs" {
+  type = list(string)
 }
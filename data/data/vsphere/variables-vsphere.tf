@@ -17,44 +17,42 @@ variable "vsphere_password" {
   description = "vSphere server password"
 }
 
-variable "vsphere_cluster" {
-  type        = string
-  description = "This is the name of the vSphere cluster."
-}
-
-variable "vsphere_datacenter" {
-  type        = string
-  description = "This is the name of the vSphere data center."
-}
-
-variable "vsphere_datastore" {
-  type        = string
-  description = "This is the name of the vSphere data store."
-}
-
 variable "vsphere_ova_filepath" {
   type        = string
   description = "This is the filepath to the ova file that will be imported into vSphere."
 }
 
-variable "vsphere_template" {
-  type        = string
-  description = "This is the name of the VM template to clone."
+variable "vsphere_failure_domains" {
+  type = map(object({
+    datacenter         = string
+    cluster            = string
+    datastore          = string
+    network            = string
+    folder             = string
+    preexisting_folder = bool
+  }))
+  description = <<EOF
+The failure domains of the control-plane machines, keyed by the name of their VM template.
+The folder is the relative path to the folder which should be used or created for VMs.
+If preexisting_folder is false, the folder is created.
+EOF
 }
 
-variable "vsphere_network" {
-  type        = string
-  description = "This is the name of the publicly accessible network for cluster ingress and access."
+variable "vsphere_control_plane_failure_domains" {
+  type        = list(string)
+  description = "The failure domain of each control-plane machine."
 }
 
-variable "vsphere_folder" {
+variable "vsphere_bootstrap_network_kargs" {
   type        = string
-  description = "The relative path to the folder which should be used or created for VMs."
+  default     = ""
+  description = "The kernel arguments of the static network configuration of the bootstrap machine. When empty, it uses DHCP."
 }
 
-variable "vsphere_preexisting_folder" {
-  type        = bool
-  description = "If false, creates a top-level folder with the name from vsphere_folder_rel_path."
+variable "vsphere_control_plane_network_kargs" {
+  type        = list(string)
+  default     = []
+  description = "The kernel arguments of the static network configuration of each control-plane machine. When empty, they use DHCP."
 }
 
 ///////////
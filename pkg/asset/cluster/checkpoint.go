@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/terraform"
+)
+
+const (
+	// CheckpointFileName is the name of the file that records which
+	// terraform stages have been applied.
+	CheckpointFileName = "terraform.checkpoint.json"
+)
+
+// StageCheckpoint is the recorded status of a single terraform stage.
+type StageCheckpoint struct {
+	// Name is the name of the stage.
+	Name string `json:"name"`
+	// StateFile is the name of the terraform state file of the stage.
+	StateFile string `json:"stateFile"`
+	// OutputsFile is the name of the outputs file of the stage.
+	OutputsFile string `json:"outputsFile"`
+	// Complete is true once the stage was applied successfully.
+	Complete bool `json:"complete"`
+	// Error is the error the last apply of the stage failed with.
+	Error string `json:"error,omitempty"`
+}
+
+// TerraformCheckpoint records the progress of the terraform stages of a
+// previous, failed attempt to create the cluster, together with the state
+// and outputs files of those stages, so that the attempt can be resumed.
+type TerraformCheckpoint struct {
+	Stages   []StageCheckpoint
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*TerraformCheckpoint)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (c *TerraformCheckpoint) Name() string {
+	return "Terraform Checkpoint"
+}
+
+// Dependencies returns no dependencies.
+func (c *TerraformCheckpoint) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates an empty checkpoint; nothing has been applied yet.
+func (c *TerraformCheckpoint) Generate(asset.Parents) error {
+	c.Stages = nil
+	c.FileList = nil
+	return nil
+}
+
+// Files returns the checkpoint file and the files of the recorded stages.
+func (c *TerraformCheckpoint) Files() []*asset.File {
+	return c.FileList
+}
+
+// Load reads the checkpoint file and the state and outputs files of the
+// stages it records.
+func (c *TerraformCheckpoint) Load(f asset.FileFetcher) (found bool, err error) {
+	file, err := f.FetchByName(CheckpointFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var stages []StageCheckpoint
+	if err := json.Unmarshal(file.Data, &stages); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", CheckpointFileName)
+	}
+
+	fileList := []*asset.File{file}
+	for i, stage := range stages {
+		for _, name := range []string{stage.StateFile, stage.OutputsFile} {
+			stageFile, err := f.FetchByName(name)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					return false, errors.Wrapf(err, "failed to load %s of terraform stage %q", name, stage.Name)
+				}
+				// A stage cannot be skipped without its outputs, so it
+				// has to be applied again.
+				if stage.Complete && name == stage.OutputsFile {
+					logrus.Debugf("The outputs of terraform stage %q are missing; it will be applied again", stage.Name)
+					stages[i].Complete = false
+				}
+				continue
+			}
+			fileList = append(fileList, stageFile)
+		}
+	}
+
+	c.Stages = stages
+	c.FileList = fileList
+	return true, nil
+}
+
+// Stage returns the recorded status of the given stage, if any.
+func (c *TerraformCheckpoint) Stage(stage terraform.Stage) *StageCheckpoint {
+	for i := range c.Stages {
+		if c.Stages[i].StateFile == stage.StateFilename() {
+			return &c.Stages[i]
+		}
+	}
+	return nil
+}
+
+// File returns the recorded file with the given name, if any.
+func (c *TerraformCheckpoint) File(name string) *asset.File {
+	for _, file := range c.FileList {
+		if file.Filename == name {
+			return file
+		}
+	}
+	return nil
+}
+
+// checkpointFile renders the checkpoint file for the given stages.
+func checkpointFile(stages []StageCheckpoint) (*asset.File, error) {
+	data, err := json.MarshalIndent(stages, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal terraform checkpoint")
+	}
+	return &asset.File{Filename: CheckpointFileName, Data: data}, nil
+}
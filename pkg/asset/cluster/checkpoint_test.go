@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/mock"
+	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/terraform/stages"
+)
+
+var testStages = []terraform.Stage{
+	stages.NewStage("aws", "cluster"),
+	stages.NewStage("aws", "bootstrap", stages.WithNormalDestroy()),
+}
+
+func TestTerraformCheckpointLoad(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+	fileFetcher.EXPECT().FetchByName(CheckpointFileName).Return(&asset.File{
+		Filename: CheckpointFileName,
+		Data: []byte(`[
+  {"name": "cluster", "stateFile": "terraform.cluster.tfstate", "outputsFile": "cluster.tfvars.json", "complete": true},
+  {"name": "bootstrap", "stateFile": "terraform.bootstrap.tfstate", "outputsFile": "bootstrap.tfvars.json", "error": "boom"}
+]`),
+	}, nil)
+	fileFetcher.EXPECT().FetchByName("terraform.cluster.tfstate").Return(&asset.File{Filename: "terraform.cluster.tfstate"}, nil)
+	fileFetcher.EXPECT().FetchByName("cluster.tfvars.json").Return(&asset.File{Filename: "cluster.tfvars.json"}, nil)
+	fileFetcher.EXPECT().FetchByName("terraform.bootstrap.tfstate").Return(&asset.File{Filename: "terraform.bootstrap.tfstate"}, nil)
+	fileFetcher.EXPECT().FetchByName("bootstrap.tfvars.json").Return(nil, &os.PathError{Err: os.ErrNotExist})
+
+	checkpoint := &TerraformCheckpoint{}
+	found, err := checkpoint.Load(fileFetcher)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, checkpoint.Files(), 4)
+	assert.Equal(t, "boom", checkpoint.Stage(testStages[1]).Error)
+
+	resumeFrom, err := resumeStage(testStages, checkpoint, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resumeFrom)
+}
+
+func TestTerraformCheckpointLoadMissingOutputs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+	fileFetcher.EXPECT().FetchByName(CheckpointFileName).Return(&asset.File{
+		Filename: CheckpointFileName,
+		Data: []byte(`[
+  {"name": "cluster", "stateFile": "terraform.cluster.tfstate", "outputsFile": "cluster.tfvars.json", "complete": true},
+  {"name": "bootstrap", "stateFile": "terraform.bootstrap.tfstate", "outputsFile": "bootstrap.tfvars.json", "complete": true}
+]`),
+	}, nil)
+	fileFetcher.EXPECT().FetchByName("terraform.cluster.tfstate").Return(&asset.File{Filename: "terraform.cluster.tfstate"}, nil)
+	fileFetcher.EXPECT().FetchByName("cluster.tfvars.json").Return(nil, &os.PathError{Err: os.ErrNotExist})
+	fileFetcher.EXPECT().FetchByName("terraform.bootstrap.tfstate").Return(&asset.File{Filename: "terraform.bootstrap.tfstate"}, nil)
+	fileFetcher.EXPECT().FetchByName("bootstrap.tfvars.json").Return(&asset.File{Filename: "bootstrap.tfvars.json"}, nil)
+
+	checkpoint := &TerraformCheckpoint{}
+	found, err := checkpoint.Load(fileFetcher)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.False(t, checkpoint.Stage(testStages[0]).Complete)
+
+	resumeFrom, err := resumeStage(testStages, checkpoint, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resumeFrom)
+}
+
+func TestResumeStage(t *testing.T) {
+	complete := &TerraformCheckpoint{
+		Stages: []StageCheckpoint{
+			{Name: "cluster", StateFile: "terraform.cluster.tfstate", OutputsFile: "cluster.tfvars.json", Complete: true},
+			{Name: "bootstrap", StateFile: "terraform.bootstrap.tfstate", OutputsFile: "bootstrap.tfvars.json", Complete: true},
+		},
+		FileList: []*asset.File{
+			{Filename: "cluster.tfvars.json"},
+			{Filename: "bootstrap.tfvars.json"},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		checkpoint    *TerraformCheckpoint
+		fromStage     string
+		expected      int
+		expectedError string
+	}{
+		{
+			name:       "no checkpoint",
+			checkpoint: &TerraformCheckpoint{},
+			expected:   0,
+		},
+		{
+			name:       "all stages complete",
+			checkpoint: complete,
+			expected:   2,
+		},
+		{
+			name: "outputs missing",
+			checkpoint: &TerraformCheckpoint{
+				Stages: complete.Stages,
+			},
+			expected: 0,
+		},
+		{
+			name:       "from stage",
+			checkpoint: complete,
+			fromStage:  "bootstrap",
+			expected:   1,
+		},
+		{
+			name:          "from stage without previous outputs",
+			checkpoint:    &TerraformCheckpoint{},
+			fromStage:     "bootstrap",
+			expectedError: `^cannot resume from terraform stage "bootstrap": the outputs of stage "cluster" are missing$`,
+		},
+		{
+			name:          "unknown stage",
+			checkpoint:    complete,
+			fromStage:     "network",
+			expectedError: `^unknown terraform stage "network"; must be one of \[cluster bootstrap\]$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resumeStage(testStages, tc.checkpoint, tc.fromStage)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRecordCheckpoint(t *testing.T) {
+	progress := []StageCheckpoint{
+		{Name: "cluster", StateFile: "terraform.cluster.tfstate", OutputsFile: "cluster.tfvars.json", Complete: true},
+		{Name: "bootstrap", StateFile: "terraform.bootstrap.tfstate", OutputsFile: "bootstrap.tfvars.json", Error: "boom"},
+	}
+
+	t.Run("failed install", func(t *testing.T) {
+		c := &Cluster{}
+		err := c.recordCheckpoint(progress, errors.New("boom"))
+		assert.EqualError(t, err, "boom")
+		if assert.Len(t, c.Files(), 1) {
+			assert.Equal(t, CheckpointFileName, c.Files()[0].Filename)
+		}
+	})
+
+	t.Run("successful install", func(t *testing.T) {
+		c := &Cluster{}
+		err := c.recordCheckpoint(progress, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, c.Files())
+	})
+}
@@ -13,21 +13,30 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/cluster/aws"
 	"github.com/openshift/installer/pkg/asset/cluster/azure"
+	"github.com/openshift/installer/pkg/asset/cluster/vsphere"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/quota"
+	"github.com/openshift/installer/pkg/events"
 	"github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/openshift/installer/pkg/terraform"
 	"github.com/openshift/installer/pkg/terraform/exec"
 	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
 // Cluster uses the terraform executable to launch a cluster
 // with the given terraform tfvar and generated templates.
 type Cluster struct {
 	FileList []*asset.File
+
+	// FromStage, when set, is the name of the terraform stage to resume
+	// the cluster creation from. That stage and all later stages are
+	// applied again, regardless of what the checkpoint of a previous
+	// attempt records. It is not persisted.
+	FromStage string `json:"-"`
 }
 
 var _ asset.WritableAsset = (*Cluster)(nil)
@@ -43,16 +52,18 @@ func (c *Cluster) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&installconfig.ClusterID{},
 		&installconfig.InstallConfig{},
-		// PlatformCredsCheck, PlatformPermsCheck and PlatformProvisionCheck
-		// perform validations & check perms required to provision infrastructure.
-		// We do not actually use them in this asset directly, hence
+		// PlatformCredsCheck, PlatformPermsCheck, PlatformProvisionCheck and
+		// NetworkCheck perform validations & check perms required to provision
+		// infrastructure. We do not actually use them in this asset directly, hence
 		// they are put in the dependencies but not fetched in Generate.
 		&installconfig.PlatformCredsCheck{},
 		&installconfig.PlatformPermsCheck{},
 		&installconfig.PlatformProvisionCheck{},
+		&installconfig.NetworkCheck{},
 		&quota.PlatformQuotaCheck{},
 		&TerraformVariables{},
 		&password.KubeadminPassword{},
+		&TerraformCheckpoint{},
 	}
 }
 
@@ -61,7 +72,8 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	terraformVariables := &TerraformVariables{}
-	parents.Get(clusterID, installConfig, terraformVariables)
+	checkpoint := &TerraformCheckpoint{}
+	parents.Get(clusterID, installConfig, terraformVariables, checkpoint)
 
 	if installConfig.Config.Platform.None != nil {
 		return errors.New("cluster cannot be created with platform set to 'none'")
@@ -70,27 +82,65 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	platform := installConfig.Config.Platform.Name()
 	stages := platformstages.StagesForPlatform(platform)
 
-	logrus.Infof("Creating infrastructure resources...")
-	switch platform {
-	case typesaws.Name:
-		if err := aws.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
-			return err
-		}
-	case typesazure.Name:
-		if err := azure.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
-			return err
+	resumeFrom, err := resumeStage(stages, checkpoint, c.FromStage)
+	if err != nil {
+		return err
+	}
+
+	progress := make([]StageCheckpoint, len(stages))
+	for i, stage := range stages {
+		progress[i] = StageCheckpoint{
+			Name:        stage.Name(),
+			StateFile:   stage.StateFilename(),
+			OutputsFile: stage.OutputsFilename(),
 		}
-		if installConfig.Config.Platform.Azure.CloudName == typesazure.StackCloud {
-			platform = "azurestack"
+	}
+	// Record the progress if applying a stage fails, so that a later
+	// attempt can resume from the failed stage.
+	defer func() {
+		err = c.recordCheckpoint(progress, err)
+	}()
+
+	logrus.Infof("Creating infrastructure resources...")
+	// The pre-terraform steps were already run by the attempt that
+	// completed the skipped stages.
+	if resumeFrom == 0 {
+		switch platform {
+		case typesaws.Name:
+			if err := aws.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+				return err
+			}
+		case typesazure.Name:
+			if err := azure.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+				return err
+			}
+		case typesvsphere.Name:
+			if err := vsphere.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+				return err
+			}
 		}
 	}
+	if platform == typesazure.Name && installConfig.Config.Platform.Azure.CloudName == typesazure.StackCloud {
+		platform = "azurestack"
+	}
 
 	tfvarsFiles := make([]*asset.File, 0, len(terraformVariables.Files())+len(stages))
 	for _, file := range terraformVariables.Files() {
 		tfvarsFiles = append(tfvarsFiles, file)
 	}
 
-	for _, stage := range stages {
+	for i, stage := range stages {
+		if i < resumeFrom {
+			logrus.Infof("Skipping terraform stage %q, which was completed by a previous attempt", stage.Name())
+			if state := checkpoint.File(stage.StateFilename()); state != nil {
+				c.FileList = append(c.FileList, state)
+			}
+			outputs := checkpoint.File(stage.OutputsFilename())
+			c.FileList = append(c.FileList, outputs)
+			tfvarsFiles = append(tfvarsFiles, outputs)
+			progress[i].Complete = true
+			continue
+		}
 
 		// Copy the terraform.tfvars to a temp directory where the terraform
 		// will be invoked within.
@@ -108,10 +158,21 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 			extraArgs = append(extraArgs, fmt.Sprintf("-var-file=%s", filepath.Join(tmpDir, file.Filename)))
 		}
 
+		// Continue from the state of a previous attempt, so that terraform
+		// does not create the resources of the stage a second time.
+		if state := checkpoint.File(stage.StateFilename()); state != nil {
+			logrus.Infof("Resuming terraform stage %q from a previous attempt", stage.Name())
+			if err := ioutil.WriteFile(filepath.Join(tmpDir, state.Filename), state.Data, 0600); err != nil {
+				return err
+			}
+		}
+
 		outputs, err := c.applyTerraform(tmpDir, platform, stage, extraArgs)
 		if err != nil {
+			progress[i].Error = err.Error()
 			return err
 		}
+		progress[i].Complete = true
 
 		tfvarsFiles = append(tfvarsFiles, outputs)
 	}
@@ -119,6 +180,56 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	return nil
 }
 
+// recordCheckpoint adds the checkpoint file for the given progress to the
+// files of the cluster when creating the cluster failed with err. A
+// successful install leaves no checkpoint behind, so that a later attempt in
+// the same directory does not resume a finished install. It returns err, or
+// the error rendering the checkpoint failed with.
+func (c *Cluster) recordCheckpoint(progress []StageCheckpoint, err error) error {
+	if err == nil {
+		return nil
+	}
+	file, err2 := checkpointFile(progress)
+	if err2 != nil {
+		logrus.Error(errors.Wrap(err2, "failed to record terraform checkpoint"))
+		return err
+	}
+	c.FileList = append(c.FileList, file)
+	return err
+}
+
+// resumeStage returns the index of the first stage that has to be applied,
+// given the checkpoint of a previous attempt and the name of the stage to
+// resume from, if any.
+func resumeStage(stages []terraform.Stage, checkpoint *TerraformCheckpoint, fromStage string) (int, error) {
+	if fromStage != "" {
+		for i, stage := range stages {
+			if stage.Name() != fromStage {
+				continue
+			}
+			for _, previous := range stages[:i] {
+				if checkpoint.File(previous.OutputsFilename()) == nil {
+					return 0, errors.Errorf("cannot resume from terraform stage %q: the outputs of stage %q are missing", fromStage, previous.Name())
+				}
+			}
+			return i, nil
+		}
+		names := make([]string, len(stages))
+		for i, stage := range stages {
+			names[i] = stage.Name()
+		}
+		return 0, errors.Errorf("unknown terraform stage %q; must be one of %v", fromStage, names)
+	}
+
+	for i, stage := range stages {
+		recorded := checkpoint.Stage(stage)
+		if recorded == nil || !recorded.Complete || checkpoint.File(stage.OutputsFilename()) == nil {
+			return i, nil
+		}
+	}
+	return len(stages), nil
+}
+
 // Files returns the FileList generated by the asset.
 func (c *Cluster) Files() []*asset.File {
 	return c.FileList
@@ -142,7 +253,13 @@ func (c *Cluster) applyTerraform(tmpDir string, platform string, stage terraform
 	timer.StartTimer(stage.Name())
 	defer timer.StopTimer(stage.Name())
 
+	events.Emit(events.Event{Type: events.TerraformStageStarted, Stage: stage.Name(), Action: "apply"})
 	stateFile, err := terraform.Apply(tmpDir, platform, stage, extraArgs...)
+	finished := events.Event{Type: events.TerraformStageFinished, Stage: stage.Name(), Action: "apply"}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	events.Emit(finished)
 	if err != nil {
 		err = errors.Wrap(err, "failed to create cluster")
 		if stateFile == "" {
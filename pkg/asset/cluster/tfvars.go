@@ -449,6 +449,7 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 				CISInstanceCRN:    crn,
 				PublishStrategy:   installConfig.Config.Publish,
 				ResourceGroupName: installConfig.Config.Platform.IBMCloud.ResourceGroupName,
+				UserTags:          installConfig.Config.Platform.IBMCloud.UserTags,
 
 				// TODO: IBM: Fetch config from masterConfig instead
 				Region:                  installConfig.Config.Platform.IBMCloud.Region,
@@ -643,17 +644,25 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 			controlPlaneConfigs[i] = c.Spec.ProviderSpec.Value.Object.(*vsphereprovider.VSphereMachineProviderSpec)
 		}
 
-		// Set this flag to use an existing folder specified in the install-config. Otherwise, create one.
-		preexistingFolder := installConfig.Config.Platform.VSphere.Folder != ""
+		// Use the existing folders specified in the install-config. Otherwise, create them.
+		var preexistingFolders []string
+		if folder := installConfig.Config.Platform.VSphere.Folder; folder != "" {
+			preexistingFolders = append(preexistingFolders, folder)
+		}
+		for _, fd := range installConfig.Config.Platform.VSphere.FailureDomains {
+			if fd.Topology.Folder != "" {
+				preexistingFolders = append(preexistingFolders, fd.Topology.Folder)
+			}
+		}
 
 		data, err = vspheretfvars.TFVars(
 			vspheretfvars.TFVarsSources{
 				ControlPlaneConfigs: controlPlaneConfigs,
 				Username:            installConfig.Config.VSphere.Username,
 				Password:            installConfig.Config.VSphere.Password,
-				Cluster:             installConfig.Config.VSphere.Cluster,
 				ImageURL:            string(*rhcosImage),
-				PreexistingFolder:   preexistingFolder,
+				PreexistingFolders:  preexistingFolders,
+				Hosts:               installConfig.Config.VSphere.Hosts,
 			},
 		)
 		if err != nil {
@@ -1,6 +1,14 @@
 package vsphere
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/vapi/tags"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/vsphere"
 )
@@ -11,5 +19,67 @@ func Metadata(config *types.InstallConfig) *vsphere.Metadata {
 		VCenter:  config.VSphere.VCenter,
 		Username: config.VSphere.Username,
 		Password: config.VSphere.Password,
+		VCenters: config.VSphere.VCenters,
+	}
+}
+
+// PreTerraform performs any infrastructure initialization which must
+// happen before Terraform creates the remaining infrastructure.
+//
+// Terraform creates the tag category and the tag of the cluster in the
+// vCenter of the platform only. The machine API tags the machines of the
+// failure domains of the other vCenters, so they are created there first.
+func PreTerraform(ctx context.Context, clusterID string, installConfig *installconfig.InstallConfig) error {
+	p := installConfig.Config.VSphere
+	for _, vcenter := range p.VCenters {
+		used := false
+		for _, fd := range p.FailureDomains {
+			if fd.Server == vcenter.Server {
+				used = true
+			}
+		}
+		if !used {
+			continue
+		}
+		if err := createTag(ctx, clusterID, vcenter); err != nil {
+			return errors.Wrapf(err, "failed to create the tag of the cluster in vCenter %s", vcenter.Server)
+		}
+	}
+	return nil
+}
+
+func createTag(ctx context.Context, clusterID string, vcenter vsphere.VCenter) error {
+	_, restClient, err := vsphere.CreateVSphereClients(ctx, vcenter.Server, vcenter.Username, vcenter.Password)
+	if err != nil {
+		return err
+	}
+	tagManager := tags.NewManager(restClient)
+
+	categoryName := fmt.Sprintf("openshift-%s", clusterID)
+	categoryID, err := tagManager.CreateCategory(ctx, &tags.Category{
+		Name:        categoryName,
+		Description: "Added by openshift-install do not remove",
+		Cardinality: "SINGLE",
+		AssociableTypes: []string{
+			"VirtualMachine",
+			"ResourcePool",
+			"Folder",
+			"Datastore",
+			"StoragePod",
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create tag category %s", categoryName)
+	}
+	logrus.Debugf("Created tag category %s in vCenter %s", categoryName, vcenter.Server)
+
+	if _, err := tagManager.CreateTag(ctx, &tags.Tag{
+		Name:        clusterID,
+		Description: "Added by openshift-install do not remove",
+		CategoryID:  categoryID,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create tag %s", clusterID)
 	}
+	logrus.Debugf("Created tag %s in vCenter %s", clusterID, vcenter.Server)
+	return nil
 }
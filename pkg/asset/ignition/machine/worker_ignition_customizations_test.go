@@ -48,12 +48,13 @@ func TestWorkerIgnitionCustomizationsGenerate(t *testing.T) {
 				},
 			}
 
+			parents := asset.Parents{}
+			parents.Add(installConfig, &tls.UserCA{})
+
 			rootCA := &tls.RootCA{}
-			err := rootCA.Generate(nil)
+			err := rootCA.Generate(parents)
 			assert.NoError(t, err, "unexpected error generating root CA")
-
-			parents := asset.Parents{}
-			parents.Add(installConfig, rootCA)
+			parents.Add(rootCA)
 
 			worker := &Worker{}
 			err = worker.Generate(parents)
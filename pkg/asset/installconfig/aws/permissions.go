@@ -6,8 +6,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	ccaws "github.com/openshift/cloud-credential-operator/pkg/aws"
+	"github.com/openshift/installer/pkg/types"
+	typesaws "github.com/openshift/installer/pkg/types/aws"
 )
 
 // PermissionGroup is the group of permissions needed by cluster creation, operation, or teardown.
@@ -234,19 +237,71 @@ var permissions = map[PermissionGroup][]string{
 	},
 }
 
+// RequiredPermissionGroups returns the permission groups required to create and delete
+// the cluster of the install config.
+func RequiredPermissionGroups(ic *types.InstallConfig) []PermissionGroup {
+	permissionGroups := []PermissionGroup{PermissionCreateBase}
+	usingExistingVPC := len(ic.AWS.Subnets) != 0
+
+	if !usingExistingVPC {
+		permissionGroups = append(permissionGroups, PermissionCreateNetworking)
+	}
+
+	// Add delete permissions for non-C2S installs.
+	if !typesaws.C2SRegions.Has(ic.AWS.Region) {
+		permissionGroups = append(permissionGroups, PermissionDeleteBase)
+		if usingExistingVPC {
+			permissionGroups = append(permissionGroups, PermissionDeleteSharedNetworking)
+		} else {
+			permissionGroups = append(permissionGroups, PermissionDeleteNetworking)
+		}
+		if includesUserSuppliedInstanceRole(ic) {
+			permissionGroups = append(permissionGroups, PermissionDeleteSharedInstanceRole)
+		}
+	}
+	return permissionGroups
+}
+
+func includesUserSuppliedInstanceRole(installConfig *types.InstallConfig) bool {
+	mp := &typesaws.MachinePool{}
+	mp.Set(installConfig.Platform.AWS.DefaultMachinePlatform)
+	mp.Set(installConfig.ControlPlane.Platform.AWS)
+	if mp.IAMRole != "" {
+		return true
+	}
+	for _, c := range installConfig.Compute {
+		mp := &typesaws.MachinePool{}
+		mp.Set(installConfig.Platform.AWS.DefaultMachinePlatform)
+		mp.Set(c.Platform.AWS)
+		if mp.IAMRole != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the sorted actions of the permission groups.
+func Permissions(groups []PermissionGroup) ([]string, error) {
+	actions := sets.NewString()
+	for _, group := range groups {
+		groupPerms, ok := permissions[group]
+		if !ok {
+			return nil, errors.Errorf("unable to access permissions group %s", group)
+		}
+		actions.Insert(groupPerms...)
+	}
+	return actions.List(), nil
+}
+
 // ValidateCreds will try to create an AWS session, and also verify that the current credentials
 // are sufficient to perform an installation, and that they can be used for cluster runtime
 // as either capable of creating new credentials for components that interact with the cloud or
 // being able to be passed through as-is to the components that need cloud credentials
 func ValidateCreds(ssn *session.Session, groups []PermissionGroup, region string) error {
 	// Compile a list of permissions based on the permission groups provided
-	requiredPermissions := []string{}
-	for _, group := range groups {
-		groupPerms, ok := permissions[group]
-		if !ok {
-			return errors.Errorf("unable to access permissions group %s", group)
-		}
-		requiredPermissions = append(requiredPermissions, groupPerms...)
+	requiredPermissions, err := Permissions(groups)
+	if err != nil {
+		return err
 	}
 
 	client, err := ccaws.NewClientFromIAMClient(iam.New(ssn))
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"strings"
+)
+
+// PolicyDocument is an IAM policy document.
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a statement of an IAM policy document.
+type PolicyStatement struct {
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource string   `json:"Resource"`
+}
+
+// NewPolicyDocument returns an IAM policy document that allows the actions
+// of the permission groups on all resources, like ValidateCreds simulates.
+func NewPolicyDocument(groups []PermissionGroup) (*PolicyDocument, error) {
+	actions, err := Permissions(groups)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyDocument{
+		Version: "2012-10-17",
+		Statement: []PolicyStatement{{
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: "*",
+		}},
+	}, nil
+}
+
+// SplitPermissionGroups splits the permission groups into those required to
+// create the cluster and those required to delete it.
+func SplitPermissionGroups(groups []PermissionGroup) (create []PermissionGroup, delete []PermissionGroup) {
+	for _, group := range groups {
+		if strings.HasPrefix(string(group), "delete-") {
+			delete = append(delete, group)
+		} else {
+			create = append(create, group)
+		}
+	}
+	return create, delete
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestRequiredPermissionGroups(t *testing.T) {
+	cases := []struct {
+		name     string
+		edit     func(*types.InstallConfig)
+		expected []PermissionGroup
+	}{{
+		name:     "new VPC",
+		expected: []PermissionGroup{PermissionCreateBase, PermissionCreateNetworking, PermissionDeleteBase, PermissionDeleteNetworking},
+	}, {
+		name: "existing subnets",
+		edit: func(ic *types.InstallConfig) {
+			ic.AWS.Subnets = []string{"subnet-1"}
+		},
+		expected: []PermissionGroup{PermissionCreateBase, PermissionDeleteBase, PermissionDeleteSharedNetworking},
+	}, {
+		name: "C2S region",
+		edit: func(ic *types.InstallConfig) {
+			ic.AWS.Region = "us-iso-east-1"
+		},
+		expected: []PermissionGroup{PermissionCreateBase, PermissionCreateNetworking},
+	}, {
+		name: "existing instance role",
+		edit: func(ic *types.InstallConfig) {
+			ic.Compute[0].Platform.AWS = &aws.MachinePool{IAMRole: "worker-role"}
+		},
+		expected: []PermissionGroup{PermissionCreateBase, PermissionCreateNetworking, PermissionDeleteBase, PermissionDeleteNetworking, PermissionDeleteSharedInstanceRole},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				Platform:     types.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+				ControlPlane: &types.MachinePool{Name: "master"},
+				Compute:      []types.MachinePool{{Name: "worker"}},
+			}
+			if test.edit != nil {
+				test.edit(ic)
+			}
+			assert.Equal(t, test.expected, RequiredPermissionGroups(ic))
+		})
+	}
+}
+
+func TestSplitPermissionGroups(t *testing.T) {
+	create, delete := SplitPermissionGroups([]PermissionGroup{PermissionCreateBase, PermissionDeleteBase, PermissionDeleteSharedNetworking})
+	assert.Equal(t, []PermissionGroup{PermissionCreateBase}, create)
+	assert.Equal(t, []PermissionGroup{PermissionDeleteBase, PermissionDeleteSharedNetworking}, delete)
+}
+
+func TestNewPolicyDocument(t *testing.T) {
+	doc, err := NewPolicyDocument([]PermissionGroup{PermissionDeleteNetworking, PermissionDeleteSharedNetworking})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "2012-10-17", doc.Version)
+	if assert.Len(t, doc.Statement, 1) {
+		statement := doc.Statement[0]
+		assert.Equal(t, "Allow", statement.Effect)
+		assert.Equal(t, "*", statement.Resource)
+		assert.IsIncreasing(t, statement.Action)
+		assert.Contains(t, statement.Action, "ec2:DeleteVpc")
+		assert.Contains(t, statement.Action, "tag:UnTagResources")
+	}
+
+	_, err = NewPolicyDocument([]PermissionGroup{"unknown"})
+	assert.EqualError(t, err, "unable to access permissions group unknown")
+}
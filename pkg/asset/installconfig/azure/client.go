@@ -9,6 +9,7 @@ import (
 	aznetwork "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/network/mgmt/network"
 	azres "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/resources/mgmt/resources"
 	azsubs "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/resources/mgmt/subscriptions"
+	azauth "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-09-01-preview/authorization"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 )
@@ -26,6 +27,7 @@ type API interface {
 	GetDiskSkus(ctx context.Context, region string) ([]azsku.ResourceSku, error)
 	GetGroup(ctx context.Context, groupName string) (*azres.Group, error)
 	ListResourceIDsByGroup(ctx context.Context, groupName string) ([]string, error)
+	ListPermissionsByGroup(ctx context.Context, groupName string) ([]azauth.Permission, error)
 }
 
 // Client makes calls to the Azure API.
@@ -213,6 +215,27 @@ func (c *Client) ListResourceIDsByGroup(ctx context.Context, groupName string) (
 	return res, nil
 }
 
+// ListPermissionsByGroup returns the permissions the credentials have in resource group groupName.
+func (c *Client) ListPermissionsByGroup(ctx context.Context, groupName string) ([]azauth.Permission, error) {
+	client := azauth.NewPermissionsClientWithBaseURI(c.ssn.Environment.ResourceManagerEndpoint, c.ssn.Credentials.SubscriptionID)
+	client.Authorizer = c.ssn.Authorizer
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	permPage, err := client.ListForResourceGroup(ctx, groupName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching permission pages")
+	}
+	var res []azauth.Permission
+	for ; permPage.NotDone(); err = permPage.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching permission pages")
+		}
+		res = append(res, permPage.Values()...)
+	}
+	return res, nil
+}
+
 // GetVirtualMachineSku retrieves the resource SKU of a specified virtual machine SKU in the specified region.
 func (c *Client) GetVirtualMachineSku(ctx context.Context, name, region string) (*azsku.ResourceSku, error) {
 	client := azsku.NewResourceSkusClientWithBaseURI(c.ssn.Environment.ResourceManagerEndpoint, c.ssn.Credentials.SubscriptionID)
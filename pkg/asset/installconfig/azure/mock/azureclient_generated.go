@@ -10,6 +10,7 @@ import (
 	network "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/network/mgmt/network"
 	resources "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/resources/mgmt/resources"
 	subscriptions "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/resources/mgmt/subscriptions"
+	authorization "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-09-01-preview/authorization"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -171,3 +172,18 @@ func (mr *MockAPIMockRecorder) ListResourceIDsByGroup(ctx, groupName interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceIDsByGroup", reflect.TypeOf((*MockAPI)(nil).ListResourceIDsByGroup), ctx, groupName)
 }
+
+// ListPermissionsByGroup mocks base method
+func (m *MockAPI) ListPermissionsByGroup(ctx context.Context, groupName string) ([]authorization.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissionsByGroup", ctx, groupName)
+	ret0, _ := ret[0].([]authorization.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPermissionsByGroup indicates an expected call of ListPermissionsByGroup
+func (mr *MockAPIMockRecorder) ListPermissionsByGroup(ctx, groupName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissionsByGroup", reflect.TypeOf((*MockAPI)(nil).ListPermissionsByGroup), ctx, groupName)
+}
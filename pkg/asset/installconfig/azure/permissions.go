@@ -0,0 +1,271 @@
+package azure
+
+import (
+	"context"
+	"strings"
+
+	azauth "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-09-01-preview/authorization"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// PermissionGroup is the group of permissions needed by cluster creation, operation, or teardown.
+type PermissionGroup string
+
+const (
+	// PermissionCreateBase is a base set of permissions required in all installs where the installer creates resources.
+	PermissionCreateBase PermissionGroup = "create-base"
+
+	// PermissionDeleteBase is a base set of permissions required in all installs where the installer deletes resources.
+	PermissionDeleteBase PermissionGroup = "delete-base"
+
+	// PermissionCreateNetworking is an additional set of permissions required when the installer creates networking resources.
+	PermissionCreateNetworking PermissionGroup = "create-networking"
+
+	// PermissionDeleteNetworking is a set of permissions required when the installer destroys networking resources.
+	PermissionDeleteNetworking PermissionGroup = "delete-networking"
+)
+
+var permissions = map[PermissionGroup][]string{
+	// Base set of permissions required for cluster creation
+	PermissionCreateBase: {
+		// Authorization related perms
+		"Microsoft.Authorization/roleAssignments/read",
+		"Microsoft.Authorization/roleAssignments/write",
+
+		// Compute related perms
+		"Microsoft.Compute/disks/read",
+		"Microsoft.Compute/disks/write",
+		"Microsoft.Compute/images/read",
+		"Microsoft.Compute/images/write",
+		"Microsoft.Compute/locations/usages/read",
+		"Microsoft.Compute/skus/read",
+		"Microsoft.Compute/virtualMachines/read",
+		"Microsoft.Compute/virtualMachines/write",
+
+		// Managed identity related perms
+		"Microsoft.ManagedIdentity/userAssignedIdentities/assign/action",
+		"Microsoft.ManagedIdentity/userAssignedIdentities/read",
+		"Microsoft.ManagedIdentity/userAssignedIdentities/write",
+
+		// Network related perms
+		"Microsoft.Network/dnsZones/CNAME/read",
+		"Microsoft.Network/dnsZones/CNAME/write",
+		"Microsoft.Network/dnsZones/read",
+		"Microsoft.Network/loadBalancers/backendAddressPools/join/action",
+		"Microsoft.Network/loadBalancers/read",
+		"Microsoft.Network/loadBalancers/write",
+		"Microsoft.Network/locations/usages/read",
+		"Microsoft.Network/networkInterfaces/join/action",
+		"Microsoft.Network/networkInterfaces/read",
+		"Microsoft.Network/networkInterfaces/write",
+		"Microsoft.Network/networkSecurityGroups/join/action",
+		"Microsoft.Network/networkSecurityGroups/read",
+		"Microsoft.Network/networkSecurityGroups/securityRules/read",
+		"Microsoft.Network/networkSecurityGroups/securityRules/write",
+		"Microsoft.Network/networkSecurityGroups/write",
+		"Microsoft.Network/privateDnsZones/A/read",
+		"Microsoft.Network/privateDnsZones/A/write",
+		"Microsoft.Network/privateDnsZones/AAAA/read",
+		"Microsoft.Network/privateDnsZones/AAAA/write",
+		"Microsoft.Network/privateDnsZones/read",
+		"Microsoft.Network/privateDnsZones/virtualNetworkLinks/read",
+		"Microsoft.Network/privateDnsZones/virtualNetworkLinks/write",
+		"Microsoft.Network/privateDnsZones/write",
+		"Microsoft.Network/publicIPAddresses/join/action",
+		"Microsoft.Network/publicIPAddresses/read",
+		"Microsoft.Network/publicIPAddresses/write",
+		"Microsoft.Network/virtualNetworks/join/action",
+		"Microsoft.Network/virtualNetworks/read",
+		"Microsoft.Network/virtualNetworks/subnets/join/action",
+		"Microsoft.Network/virtualNetworks/subnets/read",
+
+		// Resource related perms
+		"Microsoft.Resources/subscriptions/resourceGroups/read",
+		"Microsoft.Resources/subscriptions/resourceGroups/write",
+
+		// Storage related perms
+		"Microsoft.Storage/storageAccounts/blobServices/containers/read",
+		"Microsoft.Storage/storageAccounts/blobServices/containers/write",
+		"Microsoft.Storage/storageAccounts/listKeys/action",
+		"Microsoft.Storage/storageAccounts/read",
+		"Microsoft.Storage/storageAccounts/write",
+	},
+	// Permissions required for deleting base cluster resources
+	PermissionDeleteBase: {
+		"Microsoft.Authorization/roleAssignments/delete",
+		"Microsoft.Compute/disks/delete",
+		"Microsoft.Compute/images/delete",
+		"Microsoft.Compute/virtualMachines/delete",
+		"Microsoft.ManagedIdentity/userAssignedIdentities/delete",
+		"Microsoft.Network/dnsZones/CNAME/delete",
+		"Microsoft.Network/dnsZones/recordsets/read",
+		"Microsoft.Network/loadBalancers/delete",
+		"Microsoft.Network/networkInterfaces/delete",
+		"Microsoft.Network/networkSecurityGroups/delete",
+		"Microsoft.Network/networkSecurityGroups/securityRules/delete",
+		"Microsoft.Network/privateDnsZones/A/delete",
+		"Microsoft.Network/privateDnsZones/AAAA/delete",
+		"Microsoft.Network/privateDnsZones/delete",
+		"Microsoft.Network/privateDnsZones/virtualNetworkLinks/delete",
+		"Microsoft.Network/publicIPAddresses/delete",
+		"Microsoft.Resources/subscriptions/resourceGroups/delete",
+		"Microsoft.Storage/storageAccounts/blobServices/containers/delete",
+		"Microsoft.Storage/storageAccounts/delete",
+	},
+	// Permissions required for creating network resources
+	PermissionCreateNetworking: {
+		"Microsoft.Network/virtualNetworks/subnets/write",
+		"Microsoft.Network/virtualNetworks/write",
+	},
+	// Permissions required for deleting network resources
+	PermissionDeleteNetworking: {
+		"Microsoft.Network/virtualNetworks/delete",
+		"Microsoft.Network/virtualNetworks/subnets/delete",
+	},
+}
+
+// RequiredPermissionGroups returns the permission groups required to create and delete
+// the cluster of the install config.
+func RequiredPermissionGroups(ic *types.InstallConfig) []PermissionGroup {
+	permissionGroups := []PermissionGroup{PermissionCreateBase, PermissionDeleteBase}
+	if ic.Azure.VirtualNetwork == "" {
+		permissionGroups = append(permissionGroups, PermissionCreateNetworking, PermissionDeleteNetworking)
+	}
+	return permissionGroups
+}
+
+// Permissions returns the sorted actions of the permission groups.
+func Permissions(groups []PermissionGroup) ([]string, error) {
+	actions := sets.NewString()
+	for _, group := range groups {
+		groupPerms, ok := permissions[group]
+		if !ok {
+			return nil, errors.Errorf("unable to access permissions group %s", group)
+		}
+		actions.Insert(groupPerms...)
+	}
+	return actions.List(), nil
+}
+
+// SplitPermissionGroups splits the permission groups into those required to
+// create the cluster and those required to delete it.
+func SplitPermissionGroups(groups []PermissionGroup) (create []PermissionGroup, delete []PermissionGroup) {
+	for _, group := range groups {
+		if strings.HasPrefix(string(group), "delete-") {
+			delete = append(delete, group)
+		} else {
+			create = append(create, group)
+		}
+	}
+	return create, delete
+}
+
+// RoleDefinition is the definition of an Azure custom role, in the format of
+// `az role definition create --role-definition`.
+type RoleDefinition struct {
+	Name             string   `json:"Name"`
+	IsCustom         bool     `json:"IsCustom"`
+	Description      string   `json:"Description"`
+	Actions          []string `json:"Actions"`
+	NotActions       []string `json:"NotActions"`
+	AssignableScopes []string `json:"AssignableScopes"`
+}
+
+// NewRoleDefinition returns the definition of a custom role that allows the
+// actions of the permission groups in the subscription.
+func NewRoleDefinition(name, description, subscriptionID string, groups []PermissionGroup) (*RoleDefinition, error) {
+	actions, err := Permissions(groups)
+	if err != nil {
+		return nil, err
+	}
+	return &RoleDefinition{
+		Name:             name,
+		IsCustom:         true,
+		Description:      description,
+		Actions:          actions,
+		NotActions:       []string{},
+		AssignableScopes: []string{"/subscriptions/" + subscriptionID},
+	}, nil
+}
+
+// ValidatePermissions checks that the credentials have the actions of the
+// permission groups required by the install config. The actions are checked
+// in the resource group of the cluster when it already exists, and otherwise
+// in the resource group of the base domain, which has the permissions
+// assigned in the subscription.
+func ValidatePermissions(client API, ic *types.InstallConfig) error {
+	groupName := ic.Azure.ResourceGroupName
+	if groupName == "" {
+		groupName = ic.Azure.BaseDomainResourceGroupName
+	}
+	if groupName == "" {
+		return nil
+	}
+
+	required, err := Permissions(RequiredPermissionGroups(ic))
+	if err != nil {
+		return err
+	}
+
+	granted, err := client.ListPermissionsByGroup(context.TODO(), groupName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the permissions in resource group %s", groupName)
+	}
+
+	var missing []string
+	for _, action := range required {
+		if !allowed(granted, action) {
+			missing = append(missing, action)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the credentials are missing the following actions in resource group %s: %s", groupName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// allowed returns whether one of the permissions allows the action and does
+// not exclude it with its not actions.
+func allowed(permissions []azauth.Permission, action string) bool {
+	for _, p := range permissions {
+		if matchesAny(to.StringSlice(p.Actions), action) && !matchesAny(to.StringSlice(p.NotActions), action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if matchAction(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAction returns whether the action matches the pattern, where * matches
+// any sequence of characters. Actions are case-insensitive.
+func matchAction(pattern, action string) bool {
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	action = strings.ToLower(action)
+	if !strings.HasPrefix(action, parts[0]) {
+		return false
+	}
+	action = action[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			return strings.HasSuffix(action, part)
+		}
+		idx := strings.Index(action, part)
+		if idx < 0 {
+			return false
+		}
+		action = action[idx+len(part):]
+	}
+	return action == ""
+}
@@ -0,0 +1,129 @@
+package azure
+
+import (
+	"testing"
+
+	azauth "github.com/Azure/azure-sdk-for-go/services/preview/authorization/mgmt/2018-09-01-preview/authorization"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/azure/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+func TestRequiredPermissionGroups(t *testing.T) {
+	ic := &types.InstallConfig{Platform: types.Platform{Azure: &azure.Platform{}}}
+	assert.Equal(t, []PermissionGroup{PermissionCreateBase, PermissionDeleteBase, PermissionCreateNetworking, PermissionDeleteNetworking}, RequiredPermissionGroups(ic))
+
+	ic.Azure.VirtualNetwork = "vnet"
+	assert.Equal(t, []PermissionGroup{PermissionCreateBase, PermissionDeleteBase}, RequiredPermissionGroups(ic))
+}
+
+func TestNewRoleDefinition(t *testing.T) {
+	create, delete := SplitPermissionGroups([]PermissionGroup{PermissionCreateBase, PermissionDeleteBase, PermissionCreateNetworking, PermissionDeleteNetworking})
+	assert.Equal(t, []PermissionGroup{PermissionCreateBase, PermissionCreateNetworking}, create)
+	assert.Equal(t, []PermissionGroup{PermissionDeleteBase, PermissionDeleteNetworking}, delete)
+
+	role, err := NewRoleDefinition("destroyer", "Deletes the cluster", "00000000-0000-0000-0000-000000000000", delete)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, role.IsCustom)
+	assert.Equal(t, []string{"/subscriptions/00000000-0000-0000-0000-000000000000"}, role.AssignableScopes)
+	assert.IsIncreasing(t, role.Actions)
+	assert.Contains(t, role.Actions, "Microsoft.Network/virtualNetworks/delete")
+	assert.NotContains(t, role.Actions, "Microsoft.Network/virtualNetworks/write")
+}
+
+func TestValidatePermissions(t *testing.T) {
+	// The permissions of the built-in roles the documentation asks for.
+	contributor := azauth.Permission{
+		Actions: &[]string{"*"},
+		NotActions: &[]string{
+			"Microsoft.Authorization/*/Delete",
+			"Microsoft.Authorization/*/Write",
+			"Microsoft.Authorization/elevateAccess/Action",
+			"Microsoft.Blueprint/blueprintAssignments/write",
+			"Microsoft.Blueprint/blueprintAssignments/delete",
+			"Microsoft.Compute/galleries/share/action",
+		},
+	}
+	userAccessAdministrator := azauth.Permission{
+		Actions: &[]string{"*/read", "Microsoft.Authorization/*", "Microsoft.Support/*"},
+	}
+
+	cases := []struct {
+		name          string
+		resourceGroup string
+		permissions   []azauth.Permission
+		listErr       error
+		err           string
+	}{{
+		name:        "owner",
+		permissions: []azauth.Permission{{Actions: &[]string{"*"}}},
+	}, {
+		name:        "contributor and user access administrator",
+		permissions: []azauth.Permission{contributor, userAccessAdministrator},
+	}, {
+		name:        "contributor",
+		permissions: []azauth.Permission{contributor},
+		err:         "the credentials are missing the following actions in resource group base-domain-rg: Microsoft.Authorization/roleAssignments/delete, Microsoft.Authorization/roleAssignments/write",
+	}, {
+		name:          "existing resource group",
+		resourceGroup: "cluster-rg",
+		permissions:   []azauth.Permission{{Actions: &[]string{"*"}, NotActions: &[]string{"Microsoft.Network/virtualNetworks/delete"}}},
+		err:           "the credentials are missing the following actions in resource group cluster-rg: Microsoft.Network/virtualNetworks/delete",
+	}, {
+		name:    "list failure",
+		listErr: errors.New("forbidden"),
+		err:     "failed to list the permissions in resource group base-domain-rg: forbidden",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ic := &types.InstallConfig{Platform: types.Platform{Azure: &azure.Platform{
+				BaseDomainResourceGroupName: "base-domain-rg",
+				ResourceGroupName:           test.resourceGroup,
+			}}}
+			groupName := "base-domain-rg"
+			if test.resourceGroup != "" {
+				groupName = test.resourceGroup
+			}
+			azureClient := mock.NewMockAPI(mockCtrl)
+			azureClient.EXPECT().ListPermissionsByGroup(gomock.Any(), groupName).Return(test.permissions, test.listErr)
+
+			err := ValidatePermissions(azureClient, ic)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func Test_matchAction(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		action   string
+		expected bool
+	}{
+		{pattern: "*", action: "Microsoft.Compute/disks/read", expected: true},
+		{pattern: "Microsoft.Compute/*", action: "Microsoft.Compute/disks/read", expected: true},
+		{pattern: "Microsoft.Compute/*", action: "Microsoft.Network/loadBalancers/read"},
+		{pattern: "*/read", action: "Microsoft.Compute/disks/read", expected: true},
+		{pattern: "*/read", action: "Microsoft.Compute/disks/write"},
+		{pattern: "Microsoft.Authorization/*/Write", action: "Microsoft.Authorization/roleAssignments/write", expected: true},
+		{pattern: "microsoft.compute/disks/read", action: "Microsoft.Compute/disks/read", expected: true},
+		{pattern: "Microsoft.Compute/disks/read", action: "Microsoft.Compute/disks/readAll"},
+	}
+	for _, test := range cases {
+		t.Run(test.pattern+" "+test.action, func(t *testing.T) {
+			assert.Equal(t, test.expected, matchAction(test.pattern, test.action))
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package baremetal
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -81,8 +82,27 @@ func Platform() (*baremetal.Platform, error) {
 		return nil, err
 	}
 
+	var inventoryPath string
+	if err := survey.Ask([]*survey.Question{
+		{
+			Prompt: &survey.Input{
+				Message: "Host inventory",
+				Help:    "A CSV or YAML file listing the hosts and their BMCs. The boot MAC address (from the bootInterface of the host), boot mode and root disk of hosts with Redfish BMCs are discovered from the BMC. Leave empty to add the hosts one by one.",
+			},
+		},
+	}, &inventoryPath); err != nil {
+		return nil, err
+	}
+	if inventoryPath != "" {
+		var err error
+		hosts, err = importHosts(context.TODO(), inventoryPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Keep prompting for hosts
-	for {
+	for inventoryPath == "" {
 		var hostRole string
 		survey.AskOne(&survey.Select{
 			Message: "Add a Host:",
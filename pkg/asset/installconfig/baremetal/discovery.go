@@ -0,0 +1,332 @@
+package baremetal
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/metal3-io/baremetal-operator/pkg/bmc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+const (
+	// discoveryTimeout bounds the queries to a single BMC.
+	discoveryTimeout = 30 * time.Second
+
+	// MinRootDiskSizeGigabytes is the size of the smallest disk that
+	// discovery picks as the root device of a host.
+	MinRootDiskSizeGigabytes = 120
+)
+
+// errNotRedfish is returned for hosts whose BMC cannot be queried with Redfish.
+var errNotRedfish = errors.New("only Redfish BMCs can be discovered")
+
+// DiscoverHosts queries the BMC of each host and fills in the boot MAC
+// address, boot mode and root device hints that are not already set. Only
+// Redfish BMCs can be queried; hosts with other BMCs are skipped if they
+// already have a boot MAC address. The errors for all of the hosts whose BMC
+// could not be queried are returned together.
+func DiscoverHosts(ctx context.Context, hosts []*InventoryHost) error {
+	var errs []error
+	for _, host := range hosts {
+		err := DiscoverHost(ctx, host)
+		if errors.Cause(err) == errNotRedfish && host.BootMACAddress != "" {
+			logrus.Debugf("Skipping discovery of host %s: %v", host.Name, err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "host %s", host.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// DiscoverHost queries the Redfish BMC of the host and fills in the boot MAC
+// address, boot mode and root device hints that are not already set. The
+// boot MAC address is the MAC address of the boot interface of the host.
+func DiscoverHost(ctx context.Context, host *InventoryHost) error {
+	client, systemURL, err := newRedfishClient(host.BMC)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	var system redfishSystem
+	if err := client.get(ctx, systemURL, &system); err != nil {
+		return errors.Wrapf(err, "BMC %s is not reachable", host.BMC.Address)
+	}
+
+	if host.BootMACAddress == "" {
+		if host.BootInterface == "" {
+			return errors.New("a boot interface is required to discover the boot MAC address")
+		}
+		mac, err := client.bootMACAddress(ctx, system, host.BootInterface)
+		if err != nil {
+			return err
+		}
+		host.BootMACAddress = mac
+		logrus.Debugf("Discovered boot MAC address %s for host %s", mac, host.Name)
+	}
+	if host.BootMode == "" {
+		host.BootMode = system.bootMode()
+	}
+	if host.RootDeviceHints == nil {
+		hints, err := client.rootDeviceHints(ctx, system)
+		if err != nil {
+			return err
+		}
+		host.RootDeviceHints = hints
+	}
+	return nil
+}
+
+// redfishClient queries the Redfish API of a BMC.
+type redfishClient struct {
+	http     *http.Client
+	address  string
+	username string
+	password string
+}
+
+// newRedfishClient returns a client for the BMC and the URL of the computer
+// system of the host.
+func newRedfishClient(b baremetal.BMC) (*redfishClient, string, error) {
+	accessDetails, err := bmc.NewAccessDetails(b.Address, b.DisableCertificateVerification)
+	if err != nil {
+		return nil, "", err
+	}
+	driverInfo := accessDetails.DriverInfo(bmc.Credentials{Username: b.Username, Password: b.Password})
+	address, ok := driverInfo["redfish_address"].(string)
+	if !ok {
+		return nil, "", errors.Wrapf(errNotRedfish, "BMC %s does not support Redfish", b.Address)
+	}
+	systemID, _ := driverInfo["redfish_system_id"].(string)
+	if systemID == "" {
+		return nil, "", errors.Errorf("BMC address %s does not include the path of the system, e.g. /redfish/v1/Systems/1", b.Address)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if b.DisableCertificateVerification {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	client := &redfishClient{
+		http:     &http.Client{Transport: transport},
+		address:  address,
+		username: b.Username,
+		password: b.Password,
+	}
+	return client, address + systemID, nil
+}
+
+// get reads the Redfish resource at path, which is either an absolute URL or
+// an @odata.id relative to the BMC.
+func (c *redfishClient) get(ctx context.Context, path string, into interface{}) error {
+	url := path
+	if strings.HasPrefix(path, "/") {
+		url = c.address + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return errors.Wrapf(json.NewDecoder(resp.Body).Decode(into), "GET %s", url)
+}
+
+// redfishLink is a reference to another Redfish resource.
+type redfishLink struct {
+	ID string `json:"@odata.id"`
+}
+
+type redfishCollection struct {
+	Members []redfishLink `json:"Members"`
+}
+
+type redfishSystem struct {
+	Boot struct {
+		BootSourceOverrideMode        string   `json:"BootSourceOverrideMode"`
+		BootSourceOverrideModeAllowed []string `json:"BootSourceOverrideMode@Redfish.AllowableValues"`
+	} `json:"Boot"`
+	EthernetInterfaces *redfishLink `json:"EthernetInterfaces"`
+	Storage            *redfishLink `json:"Storage"`
+	SimpleStorage      *redfishLink `json:"SimpleStorage"`
+}
+
+type redfishEthernetInterface struct {
+	ID                  string `json:"Id"`
+	MACAddress          string `json:"MACAddress"`
+	PermanentMACAddress string `json:"PermanentMACAddress"`
+}
+
+type redfishStorage struct {
+	Drives []redfishLink `json:"Drives"`
+}
+
+type redfishDrive struct {
+	Name          string `json:"Name"`
+	Model         string `json:"Model"`
+	Manufacturer  string `json:"Manufacturer"`
+	SerialNumber  string `json:"SerialNumber"`
+	CapacityBytes int64  `json:"CapacityBytes"`
+	MediaType     string `json:"MediaType"`
+	Identifiers   []struct {
+		DurableName       string `json:"DurableName"`
+		DurableNameFormat string `json:"DurableNameFormat"`
+	} `json:"Identifiers"`
+}
+
+type redfishSimpleStorage struct {
+	Devices []redfishDrive `json:"Devices"`
+}
+
+// bootMode returns the boot mode that the system is capable of, preferring
+// UEFI.
+func (s redfishSystem) bootMode() baremetal.BootMode {
+	modes := s.Boot.BootSourceOverrideModeAllowed
+	if len(modes) == 0 && s.Boot.BootSourceOverrideMode != "" {
+		modes = []string{s.Boot.BootSourceOverrideMode}
+	}
+	for _, mode := range modes {
+		if mode == "UEFI" {
+			return baremetal.UEFI
+		}
+	}
+	for _, mode := range modes {
+		if mode == "Legacy" {
+			return baremetal.Legacy
+		}
+	}
+	return ""
+}
+
+// bootMACAddress returns the MAC address of the network interface of the
+// system with the given ID.
+func (c *redfishClient) bootMACAddress(ctx context.Context, system redfishSystem, id string) (string, error) {
+	if system.EthernetInterfaces == nil {
+		return "", errors.New("the BMC does not report the network interfaces of the system")
+	}
+	var collection redfishCollection
+	if err := c.get(ctx, system.EthernetInterfaces.ID, &collection); err != nil {
+		return "", errors.Wrap(err, "failed to list the network interfaces")
+	}
+
+	for _, member := range collection.Members {
+		var nic redfishEthernetInterface
+		if err := c.get(ctx, member.ID, &nic); err != nil {
+			return "", errors.Wrap(err, "failed to read a network interface")
+		}
+		if nic.ID != id {
+			continue
+		}
+		if nic.MACAddress == "" {
+			nic.MACAddress = nic.PermanentMACAddress
+		}
+		if nic.MACAddress == "" {
+			return "", errors.Errorf("the BMC does not report the MAC address of network interface %s", id)
+		}
+		return strings.ToLower(nic.MACAddress), nil
+	}
+	return "", errors.Errorf("the system has no network interface %s", id)
+}
+
+// rootDeviceHints returns hints for the smallest disk of the system that is
+// at least MinRootDiskSizeGigabytes, identified by its serial number or WWN.
+func (c *redfishClient) rootDeviceHints(ctx context.Context, system redfishSystem) (*baremetal.RootDeviceHints, error) {
+	drives, err := c.drives(ctx, system)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *redfishDrive
+	for i, drive := range drives {
+		if drive.CapacityBytes < MinRootDiskSizeGigabytes*1000*1000*1000 {
+			continue
+		}
+		if root == nil || drive.CapacityBytes < root.CapacityBytes {
+			root = &drives[i]
+		}
+	}
+	if root == nil {
+		return nil, errors.Errorf("the system has no disk of at least %d GB", MinRootDiskSizeGigabytes)
+	}
+
+	hints := &baremetal.RootDeviceHints{}
+	for _, id := range root.Identifiers {
+		if id.DurableNameFormat == "NAA" && id.DurableName != "" {
+			hints.WWN = "0x" + strings.ToLower(strings.TrimPrefix(id.DurableName, "0x"))
+		}
+	}
+	switch {
+	case root.SerialNumber != "":
+		hints.SerialNumber = root.SerialNumber
+	case hints.WWN != "":
+	default:
+		hints.Model = root.Model
+		hints.Vendor = root.Manufacturer
+		hints.MinSizeGigabytes = int(root.CapacityBytes / (1000 * 1000 * 1000))
+	}
+	if root.MediaType != "" {
+		rotational := root.MediaType == "HDD"
+		hints.Rotational = &rotational
+	}
+	return hints, nil
+}
+
+// drives returns the disks of the system from the Storage resources or, for
+// older BMCs, from SimpleStorage.
+func (c *redfishClient) drives(ctx context.Context, system redfishSystem) ([]redfishDrive, error) {
+	var drives []redfishDrive
+	switch {
+	case system.Storage != nil:
+		var collection redfishCollection
+		if err := c.get(ctx, system.Storage.ID, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to list the storage of the system")
+		}
+		for _, member := range collection.Members {
+			var storage redfishStorage
+			if err := c.get(ctx, member.ID, &storage); err != nil {
+				return nil, errors.Wrap(err, "failed to read the storage of the system")
+			}
+			for _, link := range storage.Drives {
+				var drive redfishDrive
+				if err := c.get(ctx, link.ID, &drive); err != nil {
+					return nil, errors.Wrap(err, "failed to read a disk")
+				}
+				drives = append(drives, drive)
+			}
+		}
+	case system.SimpleStorage != nil:
+		var collection redfishCollection
+		if err := c.get(ctx, system.SimpleStorage.ID, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to list the storage of the system")
+		}
+		for _, member := range collection.Members {
+			var storage redfishSimpleStorage
+			if err := c.get(ctx, member.ID, &storage); err != nil {
+				return nil, errors.Wrap(err, "failed to read the storage of the system")
+			}
+			drives = append(drives, storage.Devices...)
+		}
+	default:
+		return nil, errors.New("the BMC does not report the storage of the system")
+	}
+	return drives, nil
+}
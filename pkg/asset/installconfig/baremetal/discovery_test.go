@@ -0,0 +1,213 @@
+package baremetal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+// redfishMock serves Redfish resources from a map of paths to resources.
+func redfishMock(t *testing.T, resources map[string]interface{}) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resource, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resource); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func link(id string) map[string]string {
+	return map[string]string{"@odata.id": id}
+}
+
+func collection(ids ...string) map[string]interface{} {
+	members := []map[string]string{}
+	for _, id := range ids {
+		members = append(members, link(id))
+	}
+	return map[string]interface{}{"Members": members}
+}
+
+var redfishSystemResources = map[string]interface{}{
+	"/redfish/v1/Systems/1": map[string]interface{}{
+		"Boot": map[string]interface{}{
+			"BootSourceOverrideMode":                         "Legacy",
+			"BootSourceOverrideMode@Redfish.AllowableValues": []string{"Legacy", "UEFI"},
+		},
+		"EthernetInterfaces": link("/redfish/v1/Systems/1/EthernetInterfaces"),
+		"Storage":            link("/redfish/v1/Systems/1/Storage"),
+	},
+	"/redfish/v1/Systems/1/EthernetInterfaces": collection(
+		"/redfish/v1/Systems/1/EthernetInterfaces/NIC.1",
+		"/redfish/v1/Systems/1/EthernetInterfaces/NIC.2",
+		"/redfish/v1/Systems/1/EthernetInterfaces/NIC.3",
+	),
+	"/redfish/v1/Systems/1/EthernetInterfaces/NIC.1": map[string]interface{}{
+		"Id": "NIC.1", "MACAddress": "52:54:00:00:00:01", "LinkStatus": "LinkDown",
+	},
+	"/redfish/v1/Systems/1/EthernetInterfaces/NIC.2": map[string]interface{}{
+		"Id": "NIC.2", "MACAddress": "52:54:00:00:00:02", "LinkStatus": "LinkUp",
+		"Status": map[string]string{"State": "Disabled"},
+	},
+	"/redfish/v1/Systems/1/EthernetInterfaces/NIC.3": map[string]interface{}{
+		"Id": "NIC.3", "MACAddress": "52:54:00:00:00:0A", "LinkStatus": "LinkUp",
+	},
+	"/redfish/v1/Systems/1/Storage": collection("/redfish/v1/Systems/1/Storage/RAID.1"),
+	"/redfish/v1/Systems/1/Storage/RAID.1": map[string]interface{}{
+		"Drives": []map[string]string{
+			link("/redfish/v1/Systems/1/Storage/RAID.1/Drives/0"),
+			link("/redfish/v1/Systems/1/Storage/RAID.1/Drives/1"),
+			link("/redfish/v1/Systems/1/Storage/RAID.1/Drives/2"),
+		},
+	},
+	"/redfish/v1/Systems/1/Storage/RAID.1/Drives/0": map[string]interface{}{
+		"Model": "SATADOM", "SerialNumber": "DOM0", "CapacityBytes": 64000000000, "MediaType": "SSD",
+	},
+	"/redfish/v1/Systems/1/Storage/RAID.1/Drives/1": map[string]interface{}{
+		"Model": "PM883", "SerialNumber": "S1", "CapacityBytes": 960000000000, "MediaType": "SSD",
+	},
+	"/redfish/v1/Systems/1/Storage/RAID.1/Drives/2": map[string]interface{}{
+		"Model": "ST4000", "SerialNumber": "S2", "CapacityBytes": 480000000000, "MediaType": "HDD",
+		"Identifiers": []map[string]string{{"DurableName": "5000C500A1B2C3D4", "DurableNameFormat": "NAA"}},
+	},
+}
+
+func redfishHost(server *httptest.Server) *InventoryHost {
+	return &InventoryHost{
+		Host: baremetal.Host{
+			Name: "worker-0",
+			BMC: baremetal.BMC{
+				Address:                        "redfish+" + server.URL + "/redfish/v1/Systems/1",
+				Username:                       "admin",
+				Password:                       "password",
+				DisableCertificateVerification: true,
+			},
+		},
+		BootInterface: "NIC.3",
+	}
+}
+
+func TestDiscoverHost(t *testing.T) {
+	server := redfishMock(t, redfishSystemResources)
+	defer server.Close()
+
+	host := redfishHost(server)
+	err := DiscoverHost(context.Background(), host)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rotational := true
+	assert.Equal(t, "52:54:00:00:00:0a", host.BootMACAddress)
+	assert.Equal(t, baremetal.UEFI, host.BootMode)
+	assert.Equal(t, &baremetal.RootDeviceHints{
+		SerialNumber: "S2",
+		WWN:          "0x5000c500a1b2c3d4",
+		Rotational:   &rotational,
+	}, host.RootDeviceHints)
+}
+
+func TestDiscoverHostKeepsSetFields(t *testing.T) {
+	server := redfishMock(t, redfishSystemResources)
+	defer server.Close()
+
+	host := redfishHost(server)
+	host.BootMACAddress = "52:54:00:00:00:01"
+	host.BootMode = baremetal.Legacy
+	host.RootDeviceHints = &baremetal.RootDeviceHints{DeviceName: "/dev/sda"}
+	err := DiscoverHost(context.Background(), host)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "52:54:00:00:00:01", host.BootMACAddress)
+	assert.Equal(t, baremetal.Legacy, host.BootMode)
+	assert.Equal(t, &baremetal.RootDeviceHints{DeviceName: "/dev/sda"}, host.RootDeviceHints)
+}
+
+func TestDiscoverHostErrors(t *testing.T) {
+	server := redfishMock(t, redfishSystemResources)
+	defer server.Close()
+	closed := redfishMock(t, redfishSystemResources)
+	closed.Close()
+
+	cases := []struct {
+		name     string
+		modify   func(*InventoryHost)
+		expected string
+	}{
+		{
+			name:     "unreachable BMC",
+			modify:   func(h *InventoryHost) { h.BMC.Address = "redfish+" + closed.URL + "/redfish/v1/Systems/1" },
+			expected: "is not reachable",
+		},
+		{
+			name:     "wrong credentials",
+			modify:   func(h *InventoryHost) { h.BMC.Password = "wrong" },
+			expected: "401 Unauthorized",
+		},
+		{
+			name:     "unknown system",
+			modify:   func(h *InventoryHost) { h.BMC.Address = strings.Replace(h.BMC.Address, "Systems/1", "Systems/2", 1) },
+			expected: "404 Not Found",
+		},
+		{
+			name:     "unknown boot interface",
+			modify:   func(h *InventoryHost) { h.BootInterface = "NIC.4" },
+			expected: "the system has no network interface NIC.4",
+		},
+		{
+			name:     "no boot interface",
+			modify:   func(h *InventoryHost) { h.BootInterface = "" },
+			expected: "a boot interface is required to discover the boot MAC address",
+		},
+		{
+			name:     "IPMI BMC",
+			modify:   func(h *InventoryHost) { h.BMC.Address = "ipmi://192.168.111.1" },
+			expected: "only Redfish BMCs can be discovered",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			host := redfishHost(server)
+			tc.modify(host)
+			err := DiscoverHost(context.Background(), host)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestDiscoverHostsSkipsNonRedfishHostsWithMAC(t *testing.T) {
+	server := redfishMock(t, redfishSystemResources)
+	defer server.Close()
+
+	ipmi := &InventoryHost{
+		Host: baremetal.Host{
+			Name:           "master-0",
+			BMC:            baremetal.BMC{Address: "ipmi://192.168.111.1", Username: "admin", Password: "password"},
+			BootMACAddress: "52:54:00:00:00:10",
+		},
+	}
+	hosts := []*InventoryHost{ipmi, redfishHost(server)}
+	assert.NoError(t, DiscoverHosts(context.Background(), hosts))
+	assert.Nil(t, ipmi.RootDeviceHints)
+	assert.Equal(t, "52:54:00:00:00:0a", hosts[1].BootMACAddress)
+
+	ipmi.BootMACAddress = ""
+	err := DiscoverHosts(context.Background(), hosts)
+	assert.EqualError(t, err, "host master-0: BMC ipmi://192.168.111.1 does not support Redfish: only Redfish BMCs can be discovered")
+}
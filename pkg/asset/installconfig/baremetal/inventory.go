@@ -0,0 +1,210 @@
+package baremetal
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// InventoryHost is a host of an inventory, in the format of the install
+// config.
+type InventoryHost struct {
+	baremetal.Host `json:",inline"`
+
+	// BootInterface is the ID of the Redfish network interface that the
+	// host boots from, e.g. NIC.Integrated.1-1-1. The boot MAC address of
+	// hosts without one is discovered from this interface.
+	BootInterface string `json:"bootInterface,omitempty"`
+}
+
+// inventoryColumns are the columns of a CSV inventory and the host fields
+// that they set.
+var inventoryColumns = map[string]func(*InventoryHost, string) error{
+	"name":            func(h *InventoryHost, v string) error { h.Name = v; return nil },
+	"role":            func(h *InventoryHost, v string) error { h.Role = v; return nil },
+	"bmcAddress":      func(h *InventoryHost, v string) error { h.BMC.Address = v; return nil },
+	"bmcUsername":     func(h *InventoryHost, v string) error { h.BMC.Username = v; return nil },
+	"bmcPassword":     func(h *InventoryHost, v string) error { h.BMC.Password = v; return nil },
+	"bootMACAddress":  func(h *InventoryHost, v string) error { h.BootMACAddress = v; return nil },
+	"bootInterface":   func(h *InventoryHost, v string) error { h.BootInterface = v; return nil },
+	"bootMode":        func(h *InventoryHost, v string) error { h.BootMode = baremetal.BootMode(v); return nil },
+	"hardwareProfile": func(h *InventoryHost, v string) error { h.HardwareProfile = v; return nil },
+	"disableCertificateVerification": func(h *InventoryHost, v string) error {
+		if v == "" {
+			return nil
+		}
+		disable, err := strconv.ParseBool(v)
+		h.BMC.DisableCertificateVerification = disable
+		return err
+	},
+	"rootDeviceName": func(h *InventoryHost, v string) error {
+		if v != "" {
+			h.RootDeviceHints = &baremetal.RootDeviceHints{DeviceName: v}
+		}
+		return nil
+	},
+}
+
+// requiredInventoryColumns are the columns that every CSV inventory must have.
+var requiredInventoryColumns = []string{"name", "bmcAddress", "bmcUsername", "bmcPassword"}
+
+// ImportInventory adds the hosts of the inventory of the platform to its
+// hosts, discovering the fields that the inventory leaves out from their
+// BMCs. The inventory is then cleared, so that the platform lists the
+// imported hosts.
+func ImportInventory(ctx context.Context, p *baremetal.Platform) error {
+	if p.HostInventory == "" {
+		return nil
+	}
+	hosts, err := importHosts(ctx, p.HostInventory)
+	if err != nil {
+		return err
+	}
+	p.Hosts = append(p.Hosts, hosts...)
+	p.HostInventory = ""
+	return nil
+}
+
+// importHosts loads the hosts of the inventory file and discovers the fields
+// that the inventory leaves out from their BMCs.
+func importHosts(ctx context.Context, path string) ([]*baremetal.Host, error) {
+	inventory, err := LoadInventory(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := DiscoverHosts(ctx, inventory); err != nil {
+		return nil, errors.Wrap(err, "failed to discover the hosts")
+	}
+	hosts := make([]*baremetal.Host, 0, len(inventory))
+	for _, host := range inventory {
+		h := host.Host
+		hosts = append(hosts, &h)
+	}
+	return hosts, nil
+}
+
+// LoadInventory reads the hosts from an inventory file. Files with a .csv
+// extension are read as CSV with a header row naming the columns, e.g.
+// name,role,bmcAddress,bmcUsername,bmcPassword,bootMACAddress. Other files are
+// read as YAML (or JSON), either as a list of hosts in the format of the
+// install config or as an object with a hosts list.
+func LoadInventory(path string) ([]*InventoryHost, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the host inventory")
+	}
+	var hosts []*InventoryHost
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		hosts, err = parseCSVInventory(bytes.NewReader(data))
+	} else {
+		hosts, err = parseYAMLInventory(data)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the host inventory %s", path)
+	}
+	if err := validateInventory(hosts); err != nil {
+		return nil, errors.Wrapf(err, "invalid host inventory %s", path)
+	}
+	return hosts, nil
+}
+
+func parseCSVInventory(r io.Reader) ([]*InventoryHost, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the header row")
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+		if _, ok := inventoryColumns[header[i]]; !ok {
+			return nil, errors.Errorf("unknown column %q", header[i])
+		}
+	}
+	for _, required := range requiredInventoryColumns {
+		found := false
+		for _, column := range header {
+			found = found || column == required
+		}
+		if !found {
+			return nil, errors.Errorf("missing column %q", required)
+		}
+	}
+
+	var hosts []*InventoryHost
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		host := &InventoryHost{}
+		for i, value := range record {
+			if err := inventoryColumns[header[i]](host, strings.TrimSpace(value)); err != nil {
+				return nil, errors.Wrapf(err, "host %d: invalid %s", len(hosts), header[i])
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+func parseYAMLInventory(data []byte) ([]*InventoryHost, error) {
+	var hosts []*InventoryHost
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		var inventory struct {
+			Hosts []*InventoryHost `json:"hosts"`
+		}
+		if yaml.Unmarshal(data, &inventory) != nil {
+			return nil, err
+		}
+		hosts = inventory.Hosts
+	}
+	return hosts, nil
+}
+
+// validateInventory checks the fields of the hosts that discovery relies on.
+func validateInventory(hosts []*InventoryHost) error {
+	if len(hosts) == 0 {
+		return errors.New("no hosts")
+	}
+	var errs []error
+	names := map[string]bool{}
+	for i, host := range hosts {
+		if host.Name == "" {
+			errs = append(errs, errors.Errorf("host %d: name is required", i))
+			continue
+		}
+		if names[host.Name] {
+			errs = append(errs, errors.Errorf("host %s: duplicate name", host.Name))
+		}
+		names[host.Name] = true
+		if err := validate.URI(host.BMC.Address); err != nil {
+			errs = append(errs, errors.Wrapf(err, "host %s: invalid BMC address", host.Name))
+		}
+		switch {
+		case host.BootMACAddress != "":
+			if err := validate.MAC(host.BootMACAddress); err != nil {
+				errs = append(errs, errors.Wrapf(err, "host %s: invalid boot MAC address", host.Name))
+			}
+		case host.BootInterface == "":
+			errs = append(errs, errors.Errorf("host %s: a bootMACAddress or the bootInterface to discover it from is required", host.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,196 @@
+package baremetal
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types/baremetal"
+)
+
+func TestLoadInventory(t *testing.T) {
+	expected := []*InventoryHost{
+		{
+			Host: baremetal.Host{
+				Name:           "master-0",
+				Role:           "master",
+				BMC:            baremetal.BMC{Address: "redfish-virtualmedia://192.168.111.1/redfish/v1/Systems/1", Username: "admin", Password: "password", DisableCertificateVerification: true},
+				BootMACAddress: "52:54:00:00:00:01",
+			},
+		},
+		{
+			Host: baremetal.Host{
+				Name:            "worker-0",
+				Role:            "worker",
+				BMC:             baremetal.BMC{Address: "ipmi://192.168.111.2", Username: "admin", Password: "password"},
+				BootMACAddress:  "52:54:00:00:00:02",
+				RootDeviceHints: &baremetal.RootDeviceHints{DeviceName: "/dev/sdb"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		content  string
+		expected []*InventoryHost
+		err      string
+	}{
+		{
+			name: "csv",
+			file: "hosts.csv",
+			content: `# rack 1
+name,role,bmcAddress,bmcUsername,bmcPassword,bootMACAddress,disableCertificateVerification,rootDeviceName
+master-0,master,redfish-virtualmedia://192.168.111.1/redfish/v1/Systems/1,admin,password,52:54:00:00:00:01,true,
+worker-0, worker, ipmi://192.168.111.2, admin, password, 52:54:00:00:00:02,,/dev/sdb
+`,
+			expected: expected,
+		},
+		{
+			name: "yaml list",
+			file: "hosts.yaml",
+			content: `- name: master-0
+  role: master
+  bmc:
+    address: redfish-virtualmedia://192.168.111.1/redfish/v1/Systems/1
+    username: admin
+    password: password
+    disableCertificateVerification: true
+  bootMACAddress: 52:54:00:00:00:01
+- name: worker-0
+  role: worker
+  bmc:
+    address: ipmi://192.168.111.2
+    username: admin
+    password: password
+  bootMACAddress: 52:54:00:00:00:02
+  rootDeviceHints:
+    deviceName: /dev/sdb
+`,
+			expected: expected,
+		},
+		{
+			name: "yaml hosts",
+			file: "hosts.yml",
+			content: `hosts:
+- name: master-0
+  bmc:
+    address: redfish://192.168.111.1/redfish/v1/Systems/1
+    username: admin
+    password: password
+  bootInterface: NIC.1
+`,
+			expected: []*InventoryHost{{
+				Host: baremetal.Host{
+					Name: "master-0",
+					BMC:  baremetal.BMC{Address: "redfish://192.168.111.1/redfish/v1/Systems/1", Username: "admin", Password: "password"},
+				},
+				BootInterface: "NIC.1",
+			}},
+		},
+		{
+			name:    "csv boot interface",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername,bmcPassword,bootInterface\nmaster-0,redfish://192.168.111.1/redfish/v1/Systems/1,admin,password,NIC.1\n",
+			expected: []*InventoryHost{{
+				Host: baremetal.Host{
+					Name: "master-0",
+					BMC:  baremetal.BMC{Address: "redfish://192.168.111.1/redfish/v1/Systems/1", Username: "admin", Password: "password"},
+				},
+				BootInterface: "NIC.1",
+			}},
+		},
+		{
+			name:    "no boot MAC address or interface",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername,bmcPassword\nmaster-0,redfish://192.168.111.1/redfish/v1/Systems/1,admin,password\n",
+			err:     "host master-0: a bootMACAddress or the bootInterface to discover it from is required",
+		},
+		{
+			name:    "unknown column",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername,bmcPassword,rack\n",
+			err:     `unknown column "rack"`,
+		},
+		{
+			name:    "missing column",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername\n",
+			err:     `missing column "bmcPassword"`,
+		},
+		{
+			name:    "invalid boolean",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername,bmcPassword,disableCertificateVerification\nmaster-0,ipmi://192.168.111.1,admin,password,maybe\n",
+			err:     "host 0: invalid disableCertificateVerification",
+		},
+		{
+			name:    "invalid hosts",
+			file:    "hosts.csv",
+			content: "name,bmcAddress,bmcUsername,bmcPassword,bootMACAddress\nmaster-0,192.168.111.1,admin,password,\nmaster-0,ipmi://192.168.111.2,admin,password,52:54:00\n",
+			err:     "host master-0: invalid BMC address",
+		},
+		{
+			name:    "no hosts",
+			file:    "hosts.yaml",
+			content: "[]",
+			err:     "no hosts",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.file)
+			if err := ioutil.WriteFile(path, []byte(tc.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			hosts, err := LoadInventory(path)
+			if tc.err != "" {
+				assert.Error(t, err)
+				if err != nil {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, hosts)
+		})
+	}
+}
+
+func TestImportInventory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "hosts.csv")
+	content := "name,role,bmcAddress,bmcUsername,bmcPassword,bootMACAddress\nworker-0,worker,ipmi://192.168.111.2,admin,password,52:54:00:00:00:02\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	master := &baremetal.Host{Name: "master-0", Role: "master", BootMACAddress: "52:54:00:00:00:01"}
+	platform := &baremetal.Platform{Hosts: []*baremetal.Host{master}, HostInventory: path}
+	assert.NoError(t, ImportInventory(context.Background(), platform))
+	assert.Equal(t, []*baremetal.Host{
+		master,
+		{
+			Name:           "worker-0",
+			Role:           "worker",
+			BMC:            baremetal.BMC{Address: "ipmi://192.168.111.2", Username: "admin", Password: "password"},
+			BootMACAddress: "52:54:00:00:00:02",
+		},
+	}, platform.Hosts)
+	assert.Empty(t, platform.HostInventory)
+}
@@ -0,0 +1,29 @@
+package gcp
+
+import (
+	"github.com/openshift/installer/pkg/types"
+)
+
+// installerRoles are the predefined roles the service account of the
+// installer needs to create and delete a cluster.
+var installerRoles = []string{
+	"roles/compute.admin",
+	"roles/dns.admin",
+	"roles/iam.securityAdmin",
+	"roles/iam.serviceAccountAdmin",
+	"roles/iam.serviceAccountUser",
+	"roles/storage.admin",
+}
+
+// RequiredRoles returns the predefined roles the service account of the
+// installer needs to create and delete the cluster of the install config.
+// When the cloud credential operator mints the credentials of the cluster
+// components, the service account also needs to create their keys.
+func RequiredRoles(ic *types.InstallConfig) []string {
+	roles := append([]string{}, installerRoles...)
+	switch ic.CredentialsMode {
+	case "", types.MintCredentialsMode:
+		roles = append(roles, "roles/iam.serviceAccountKeyAdmin")
+	}
+	return roles
+}
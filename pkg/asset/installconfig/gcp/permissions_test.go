@@ -0,0 +1,36 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestRequiredRoles(t *testing.T) {
+	cases := []struct {
+		credentialsMode types.CredentialsMode
+		keyAdmin        bool
+	}{{
+		keyAdmin: true,
+	}, {
+		credentialsMode: types.MintCredentialsMode,
+		keyAdmin:        true,
+	}, {
+		credentialsMode: types.PassthroughCredentialsMode,
+	}, {
+		credentialsMode: types.ManualCredentialsMode,
+	}}
+	for _, test := range cases {
+		t.Run(string(test.credentialsMode), func(t *testing.T) {
+			roles := RequiredRoles(&types.InstallConfig{CredentialsMode: test.credentialsMode})
+			assert.Subset(t, roles, installerRoles)
+			if test.keyAdmin {
+				assert.Contains(t, roles, "roles/iam.serviceAccountKeyAdmin")
+			} else {
+				assert.NotContains(t, roles, "roles/iam.serviceAccountKeyAdmin")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package installconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	azconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/azure"
+	"github.com/openshift/installer/pkg/types/gcp"
+)
+
+const (
+	iamPolicyFilename       = "iam-policy.json"
+	iamPolicyCreateFilename = "iam-policy-create.json"
+	iamPolicyDeleteFilename = "iam-policy-delete.json"
+)
+
+// IAMPolicyFiles returns the IAM policies the credentials of the installer
+// need to create and delete the cluster of the install config, with the
+// permissions that PlatformPermsCheck checks:
+//
+// - on AWS, an IAM policy document,
+// - on Azure, the definition of a custom role in the subscription,
+// - on GCP, the list of the predefined roles of the service account.
+//
+// When split is set, the permissions to create the cluster and those to
+// delete it are in separate policies.
+func IAMPolicyFiles(ic *InstallConfig, split bool) ([]*asset.File, error) {
+	switch platform := ic.Config.Platform.Name(); platform {
+	case aws.Name:
+		groups := awsconfig.RequiredPermissionGroups(ic.Config)
+		if !split {
+			doc, err := awsconfig.NewPolicyDocument(groups)
+			if err != nil {
+				return nil, err
+			}
+			return iamPolicyFiles(iamPolicy{iamPolicyFilename, doc})
+		}
+		createGroups, deleteGroups := awsconfig.SplitPermissionGroups(groups)
+		createDoc, err := awsconfig.NewPolicyDocument(createGroups)
+		if err != nil {
+			return nil, err
+		}
+		if len(deleteGroups) == 0 {
+			// The installer does not delete the clusters in C2S regions.
+			return iamPolicyFiles(iamPolicy{iamPolicyCreateFilename, createDoc})
+		}
+		deleteDoc, err := awsconfig.NewPolicyDocument(deleteGroups)
+		if err != nil {
+			return nil, err
+		}
+		return iamPolicyFiles(iamPolicy{iamPolicyCreateFilename, createDoc}, iamPolicy{iamPolicyDeleteFilename, deleteDoc})
+	case azure.Name:
+		session, err := ic.Azure.Session()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load Azure session")
+		}
+		subscriptionID := session.Credentials.SubscriptionID
+		clusterName := ic.Config.ObjectMeta.Name
+		groups := azconfig.RequiredPermissionGroups(ic.Config)
+		if !split {
+			role, err := azconfig.NewRoleDefinition(
+				fmt.Sprintf("OpenShift installer for %s", clusterName),
+				fmt.Sprintf("Creates and deletes the OpenShift cluster %s", clusterName),
+				subscriptionID, groups)
+			if err != nil {
+				return nil, err
+			}
+			return iamPolicyFiles(iamPolicy{iamPolicyFilename, role})
+		}
+		createGroups, deleteGroups := azconfig.SplitPermissionGroups(groups)
+		createRole, err := azconfig.NewRoleDefinition(
+			fmt.Sprintf("OpenShift installer for %s", clusterName),
+			fmt.Sprintf("Creates the OpenShift cluster %s", clusterName),
+			subscriptionID, createGroups)
+		if err != nil {
+			return nil, err
+		}
+		deleteRole, err := azconfig.NewRoleDefinition(
+			fmt.Sprintf("OpenShift destroyer for %s", clusterName),
+			fmt.Sprintf("Deletes the OpenShift cluster %s", clusterName),
+			subscriptionID, deleteGroups)
+		if err != nil {
+			return nil, err
+		}
+		return iamPolicyFiles(iamPolicy{iamPolicyCreateFilename, createRole}, iamPolicy{iamPolicyDeleteFilename, deleteRole})
+	case gcp.Name:
+		if split {
+			return nil, errors.New("the GCP roles are required both to create and to delete the cluster, and cannot be split")
+		}
+		return iamPolicyFiles(iamPolicy{iamPolicyFilename, struct {
+			Roles []string `json:"roles"`
+		}{Roles: gcpconfig.RequiredRoles(ic.Config)}})
+	default:
+		return nil, errors.Errorf("IAM policies are only generated for the %s, %s and %s platforms, not %s", aws.Name, azure.Name, gcp.Name, platform)
+	}
+}
+
+// iamPolicy is a policy and the name of its file.
+type iamPolicy struct {
+	filename string
+	policy   interface{}
+}
+
+func iamPolicyFiles(policies ...iamPolicy) ([]*asset.File, error) {
+	files := make([]*asset.File, 0, len(policies))
+	for _, p := range policies {
+		data, err := json.MarshalIndent(p.policy, "", "  ")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal %s", p.filename)
+		}
+		files = append(files, &asset.File{Filename: p.filename, Data: append(data, '\n')})
+	}
+	return files, nil
+}
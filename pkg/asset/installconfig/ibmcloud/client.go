@@ -11,6 +11,7 @@ import (
 	"github.com/IBM/networking-go-sdk/dnsrecordsv1"
 	"github.com/IBM/networking-go-sdk/zonesv1"
 	"github.com/IBM/platform-services-go-sdk/iamidentityv1"
+	"github.com/IBM/platform-services-go-sdk/iampolicymanagementv1"
 	"github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
 	"github.com/IBM/platform-services-go-sdk/resourcemanagerv2"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
@@ -33,6 +34,7 @@ type API interface {
 	GetVSIProfiles(ctx context.Context) ([]vpcv1.InstanceProfile, error)
 	GetVPC(ctx context.Context, vpcID string) (*vpcv1.VPC, error)
 	GetVPCZonesForRegion(ctx context.Context, region string) ([]string, error)
+	ListIAMPolicies(ctx context.Context, accountID string, iamID string) ([]iampolicymanagementv1.Policy, error)
 }
 
 // Client makes calls to the IBM Cloud API.
@@ -131,6 +133,25 @@ func (c *Client) GetAuthenticatorAPIKeyDetails(ctx context.Context) (*iamidentit
 	return details, nil
 }
 
+// ListIAMPolicies lists the access policies assigned directly to the IAM ID in the account.
+func (c *Client) ListIAMPolicies(ctx context.Context, accountID string, iamID string) ([]iampolicymanagementv1.Policy, error) {
+	iamPolicyService, err := iampolicymanagementv1.NewIamPolicyManagementV1(&iampolicymanagementv1.IamPolicyManagementV1Options{
+		Authenticator: c.Authenticator,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	options := iamPolicyService.NewListPoliciesOptions(accountID)
+	options.SetIamID(iamID)
+	options.SetType("access")
+	policies, _, err := iamPolicyService.ListPoliciesWithContext(ctx, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list IAM policies")
+	}
+	return policies.Policies, nil
+}
+
 // GetCISInstance gets a specific Cloud Internet Services instance by its CRN.
 func (c *Client) GetCISInstance(ctx context.Context, crnstr string) (*resourcecontrollerv2.ResourceInstance, error) {
 	_, cancel := context.WithTimeout(ctx, 1*time.Minute)
@@ -10,6 +10,7 @@ import (
 
 	dnsrecordsv1 "github.com/IBM/networking-go-sdk/dnsrecordsv1"
 	iamidentityv1 "github.com/IBM/platform-services-go-sdk/iamidentityv1"
+	iampolicymanagementv1 "github.com/IBM/platform-services-go-sdk/iampolicymanagementv1"
 	resourcecontrollerv2 "github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
 	resourcemanagerv2 "github.com/IBM/platform-services-go-sdk/resourcemanagerv2"
 	vpcv1 "github.com/IBM/vpc-go-sdk/vpcv1"
@@ -219,3 +220,18 @@ func (mr *MockAPIMockRecorder) GetVSIProfiles(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVSIProfiles", reflect.TypeOf((*MockAPI)(nil).GetVSIProfiles), ctx)
 }
+
+// ListIAMPolicies mocks base method.
+func (m *MockAPI) ListIAMPolicies(ctx context.Context, accountID, iamID string) ([]iampolicymanagementv1.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIAMPolicies", ctx, accountID, iamID)
+	ret0, _ := ret[0].([]iampolicymanagementv1.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIAMPolicies indicates an expected call of ListIAMPolicies.
+func (mr *MockAPIMockRecorder) ListIAMPolicies(ctx, accountID, iamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIAMPolicies", reflect.TypeOf((*MockAPI)(nil).ListIAMPolicies), ctx, accountID, iamID)
+}
@@ -0,0 +1,146 @@
+package ibmcloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/platform-services-go-sdk/iampolicymanagementv1"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// PermissionGroup is the group of permissions needed by cluster creation, operation, or teardown.
+type PermissionGroup string
+
+const (
+	// PermissionBase is a base set of permissions required in all installs.
+	PermissionBase PermissionGroup = "base"
+)
+
+// serviceRoles are the roles required on an IAM-enabled service.
+type serviceRoles struct {
+	// Service is the name of the service in the access policies.
+	Service string
+
+	// Roles are the platform and service roles required on the service.
+	Roles []string
+}
+
+var permissions = map[PermissionGroup][]serviceRoles{
+	PermissionBase: {
+		// The VPC, the instances, the images and the load balancers.
+		{Service: "is", Roles: []string{"Editor"}},
+		// The DNS records of the cluster.
+		{Service: "internet-svcs", Roles: []string{"Writer"}},
+		// The instance and the buckets of the image and of the bootstrap
+		// Ignition config, and the authorization of VPC to read the image.
+		{Service: "cloud-object-storage", Roles: []string{"Administrator", "Manager"}},
+	},
+}
+
+// roleHierarchies are the platform and service roles, each granting the
+// permissions of the roles before it.
+var roleHierarchies = [][]string{
+	{"Viewer", "Operator", "Editor", "Administrator"},
+	{"Reader", "Writer", "Manager"},
+}
+
+// RequiredPermissionGroups returns the permission groups required to create and delete
+// the cluster of the install config.
+func RequiredPermissionGroups(ic *types.InstallConfig) []PermissionGroup {
+	return []PermissionGroup{PermissionBase}
+}
+
+// ValidatePermissions checks that the access policies of the API key grant
+// the roles of the permission groups required by the install config. Only the
+// policies assigned directly to the user or service ID of the API key are
+// checked, not the policies of its access groups.
+func ValidatePermissions(client API, ic *types.InstallConfig) error {
+	ctx := context.TODO()
+	apiKey, err := client.GetAuthenticatorAPIKeyDetails(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the details of the API key")
+	}
+	policies, err := client.ListIAMPolicies(ctx, core.StringNilMapper(apiKey.AccountID), core.StringNilMapper(apiKey.IamID))
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, group := range RequiredPermissionGroups(ic) {
+		required, ok := permissions[group]
+		if !ok {
+			return errors.Errorf("unable to access permissions group %s", group)
+		}
+		for _, r := range required {
+			for _, role := range r.Roles {
+				if !granted(policies, r.Service, role) {
+					missing = append(missing, fmt.Sprintf("%s on %s", role, r.Service))
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the access policies of the API key are missing the following roles: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// granted returns whether one of the policies grants the role, or a role
+// above it, on the service or on all the IAM-enabled services.
+func granted(policies []iampolicymanagementv1.Policy, service, role string) bool {
+	for _, policy := range policies {
+		if !appliesTo(policy, service) {
+			continue
+		}
+		for _, policyRole := range policy.Roles {
+			if grants(roleName(core.StringNilMapper(policyRole.RoleID)), role) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func appliesTo(policy iampolicymanagementv1.Policy, service string) bool {
+	for _, resource := range policy.Resources {
+		serviceName := ""
+		for _, attribute := range resource.Attributes {
+			if core.StringNilMapper(attribute.Name) == "serviceName" {
+				serviceName = core.StringNilMapper(attribute.Value)
+			}
+		}
+		if serviceName == "" || serviceName == service {
+			return true
+		}
+	}
+	return false
+}
+
+// roleName returns the name of the role of a role CRN, like Editor for
+// crn:v1:bluemix:public:iam::::role:Editor.
+func roleName(roleID string) string {
+	return roleID[strings.LastIndex(roleID, ":")+1:]
+}
+
+// grants returns whether the role has the permissions of the wanted role.
+func grants(role, wanted string) bool {
+	for _, hierarchy := range roleHierarchies {
+		roleIdx, wantedIdx := -1, -1
+		for i, r := range hierarchy {
+			if r == role {
+				roleIdx = i
+			}
+			if r == wanted {
+				wantedIdx = i
+			}
+		}
+		if roleIdx >= 0 && wantedIdx >= 0 {
+			return roleIdx >= wantedIdx
+		}
+	}
+	return role == wanted
+}
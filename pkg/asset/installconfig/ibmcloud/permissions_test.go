@@ -0,0 +1,84 @@
+package ibmcloud_test
+
+import (
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/platform-services-go-sdk/iamidentityv1"
+	"github.com/IBM/platform-services-go-sdk/iampolicymanagementv1"
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
+	"github.com/openshift/installer/pkg/asset/installconfig/ibmcloud/mock"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func servicePolicy(service string, roles ...string) iampolicymanagementv1.Policy {
+	policy := iampolicymanagementv1.Policy{
+		Resources: []iampolicymanagementv1.PolicyResource{{
+			Attributes: []iampolicymanagementv1.ResourceAttribute{
+				{Name: core.StringPtr("accountId"), Value: core.StringPtr("valid-account-id")},
+			},
+		}},
+	}
+	if service != "" {
+		policy.Resources[0].Attributes = append(policy.Resources[0].Attributes, iampolicymanagementv1.ResourceAttribute{
+			Name: core.StringPtr("serviceName"), Value: core.StringPtr(service),
+		})
+	}
+	for _, role := range roles {
+		policy.Roles = append(policy.Roles, iampolicymanagementv1.PolicyRole{RoleID: core.StringPtr("crn:v1:bluemix:public:iam::::" + role)})
+	}
+	return policy
+}
+
+func TestValidatePermissions(t *testing.T) {
+	cases := []struct {
+		name     string
+		policies []iampolicymanagementv1.Policy
+		err      string
+	}{{
+		name: "service policies",
+		policies: []iampolicymanagementv1.Policy{
+			servicePolicy("is", "role:Editor"),
+			servicePolicy("internet-svcs", "role:Viewer", "serviceRole:Writer"),
+			servicePolicy("cloud-object-storage", "role:Administrator", "serviceRole:Manager"),
+		},
+	}, {
+		name: "all services",
+		policies: []iampolicymanagementv1.Policy{
+			servicePolicy("", "role:Administrator", "serviceRole:Manager"),
+		},
+	}, {
+		name: "missing roles",
+		policies: []iampolicymanagementv1.Policy{
+			servicePolicy("is", "role:Viewer"),
+			servicePolicy("internet-svcs", "serviceRole:Manager"),
+			servicePolicy("cloud-object-storage", "role:Editor", "serviceRole:Manager"),
+		},
+		err: "the access policies of the API key are missing the following roles: Editor on is, Administrator on cloud-object-storage",
+	}, {
+		name: "no policies",
+		err:  "the access policies of the API key are missing the following roles: Editor on is, Writer on internet-svcs, Administrator on cloud-object-storage, Manager on cloud-object-storage",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ibmcloudClient := mock.NewMockAPI(mockCtrl)
+			ibmcloudClient.EXPECT().GetAuthenticatorAPIKeyDetails(gomock.Any()).Return(&iamidentityv1.APIKey{
+				AccountID: core.StringPtr("valid-account-id"),
+				IamID:     core.StringPtr("valid-iam-id"),
+			}, nil)
+			ibmcloudClient.EXPECT().ListIAMPolicies(gomock.Any(), "valid-account-id", "valid-iam-id").Return(test.policies, nil)
+
+			err := ibmcloud.ValidatePermissions(ibmcloudClient, &types.InstallConfig{})
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig/aws"
 	icazure "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	icbaremetal "github.com/openshift/installer/pkg/asset/installconfig/baremetal"
 	icgcp "github.com/openshift/installer/pkg/asset/installconfig/gcp"
 	icibmcloud "github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
 	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
@@ -133,6 +134,12 @@ func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, errors.Wrap(err, "failed to upconvert install config")
 	}
 
+	if a.Config.BareMetal != nil {
+		if err := icbaremetal.ImportInventory(context.TODO(), a.Config.BareMetal); err != nil {
+			return false, errors.Wrap(err, "failed to import the bare metal host inventory")
+		}
+	}
+
 	err = a.finish(installConfigFilename)
 	if err != nil {
 		return false, err
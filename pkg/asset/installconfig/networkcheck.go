@@ -0,0 +1,82 @@
+package installconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/reachability"
+)
+
+// NetworkCheck is an asset that checks that the installer host can reach the
+// proxy of the cluster, the mirrors of its image content sources and the
+// registry of its release image, so that misconfigurations are reported
+// before the infrastructure is created instead of by the bootstrap machine.
+type NetworkCheck struct {
+}
+
+var _ asset.Asset = (*NetworkCheck)(nil)
+
+// Dependencies returns the dependencies for NetworkCheck
+func (a *NetworkCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&InstallConfig{},
+		&releaseimage.Image{},
+	}
+}
+
+// Generate checks the endpoints the cluster needs from the installer host.
+func (a *NetworkCheck) Generate(dependencies asset.Parents) error {
+	ic := &InstallConfig{}
+	releaseImage := &releaseimage.Image{}
+	dependencies.Get(ic, releaseImage)
+
+	if skip := os.Getenv("OPENSHIFT_INSTALL_SKIP_PREFLIGHT_VALIDATIONS"); skip == "1" {
+		logrus.Warnf("OVERRIDE: pre-flight validation disabled.")
+		return nil
+	}
+
+	endpoints, err := reachability.Endpoints(ic.Config, releaseImage.PullSpec)
+	if err != nil {
+		return err
+	}
+	checker, err := reachability.NewChecker(ic.Config)
+	if err != nil {
+		return err
+	}
+
+	results := checker.Check(context.TODO(), endpoints)
+	for _, result := range results {
+		if result.Err == nil {
+			logrus.Debugf("Reached %s %s", result.Endpoint.Name, result.Endpoint.Address)
+		}
+	}
+	failures, warnings := reachability.Failures(results)
+	for _, result := range warnings {
+		logrus.Warnf("Could not reach %s %s, the images of %s can still be pulled from its other mirrors or source: %v", result.Endpoint.Name, result.Endpoint.Address, result.Endpoint.Source, result.Err)
+	}
+	if len(failures) > 0 {
+		unreachable := make([]string, 0, len(failures))
+		for _, result := range failures {
+			unreachable = append(unreachable, fmt.Sprintf("%s %s: %v", result.Endpoint.Name, result.Endpoint.Address, result.Err))
+		}
+		return &diagnostics.Err{
+			Source:      a.Name(),
+			Reason:      "UnreachableEndpoints",
+			Message:     strings.Join(unreachable, "; "),
+			Remediation: "Check the proxy, imageContentSources, additionalTrustBundle and pull secret of the install config. The endpoints are checked from the installer host; if it cannot reach them but the cluster can, set OPENSHIFT_INSTALL_SKIP_PREFLIGHT_VALIDATIONS=1 to skip the checks.",
+		}
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *NetworkCheck) Name() string {
+	return "Network Check"
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./permissions.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockPermissionsAPI is a mock of PermissionsAPI interface
+type MockPermissionsAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionsAPIMockRecorder
+}
+
+// MockPermissionsAPIMockRecorder is the mock recorder for MockPermissionsAPI
+type MockPermissionsAPIMockRecorder struct {
+	mock *MockPermissionsAPI
+}
+
+// NewMockPermissionsAPI creates a new mock instance
+func NewMockPermissionsAPI(ctrl *gomock.Controller) *MockPermissionsAPI {
+	mock := &MockPermissionsAPI{ctrl: ctrl}
+	mock.recorder = &MockPermissionsAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPermissionsAPI) EXPECT() *MockPermissionsAPIMockRecorder {
+	return m.recorder
+}
+
+// GetRoles mocks base method
+func (m *MockPermissionsAPI) GetRoles(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles
+func (mr *MockPermissionsAPIMockRecorder) GetRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockPermissionsAPI)(nil).GetRoles), ctx)
+}
+
+// GetServiceTypes mocks base method
+func (m *MockPermissionsAPI) GetServiceTypes(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceTypes", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceTypes indicates an expected call of GetServiceTypes
+func (mr *MockPermissionsAPIMockRecorder) GetServiceTypes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceTypes", reflect.TypeOf((*MockPermissionsAPI)(nil).GetServiceTypes), ctx)
+}
@@ -0,0 +1,139 @@
+package openstack
+
+import (
+	"context"
+	"strings"
+
+	tokensv3 "github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/types"
+	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
+)
+
+//go:generate mockgen -source=./permissions.go -destination=mock/permissions_generated.go -package=mock
+
+// PermissionsAPI represents the calls made to check the permissions of the credentials.
+type PermissionsAPI interface {
+	GetRoles(ctx context.Context) ([]string, error)
+	GetServiceTypes(ctx context.Context) ([]string, error)
+}
+
+// PermissionGroup is the group of permissions needed by cluster creation, operation, or teardown.
+type PermissionGroup string
+
+const (
+	// PermissionBase is a base set of permissions required in all installs.
+	PermissionBase PermissionGroup = "base"
+
+	// PermissionLoadBalancer is an additional set of permissions required when Kuryr creates Octavia load balancers.
+	PermissionLoadBalancer PermissionGroup = "load-balancer"
+)
+
+// permissions are the roles the credentials need in the project. OpenStack
+// policies cannot be queried, so the roles of the default policies of the
+// services are checked.
+var permissions = map[PermissionGroup][]string{
+	PermissionBase: {
+		"member",
+	},
+	PermissionLoadBalancer: {
+		"load-balancer_member",
+	},
+}
+
+// impliedRoles are the roles that grant the permissions of another role.
+var impliedRoles = map[string][]string{
+	"member":               {"_member_", "admin"},
+	"load-balancer_member": {"load-balancer_admin", "admin"},
+}
+
+// RequiredPermissionGroups returns the permission groups required to create and delete
+// the cluster of the install config, given the types of the services of the cloud.
+func RequiredPermissionGroups(ic *types.InstallConfig, serviceTypes []string) []PermissionGroup {
+	permissionGroups := []PermissionGroup{PermissionBase}
+	if ic.Networking != nil && ic.Networking.NetworkType == "Kuryr" && sets.NewString(serviceTypes...).Has("load-balancer") {
+		permissionGroups = append(permissionGroups, PermissionLoadBalancer)
+	}
+	return permissionGroups
+}
+
+// ValidatePermissions checks that the credentials have the roles of the
+// permission groups required by the install config in the project.
+func ValidatePermissions(client PermissionsAPI, ic *types.InstallConfig) error {
+	ctx := context.TODO()
+	serviceTypes, err := client.GetServiceTypes(ctx)
+	if err != nil {
+		return err
+	}
+	roles, err := client.GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	granted := sets.NewString(roles...)
+	var missing []string
+	for _, group := range RequiredPermissionGroups(ic, serviceTypes) {
+		groupRoles, ok := permissions[group]
+		if !ok {
+			return errors.Errorf("unable to access permissions group %s", group)
+		}
+		for _, role := range groupRoles {
+			if !granted.Has(role) && !granted.HasAny(impliedRoles[role]...) {
+				missing = append(missing, role)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the credentials are missing the following roles in the project: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// PermissionsClient reads the roles and the services of the cloud from the
+// token of the credentials.
+type PermissionsClient struct {
+	token tokensv3.CreateResult
+}
+
+// NewPermissionsClient authenticates to the cloud of clouds.yaml.
+func NewPermissionsClient(cloud string) (*PermissionsClient, error) {
+	opts := openstackdefaults.DefaultClientOpts(cloud)
+	identityClient, err := clientconfig.NewServiceClient("identity", opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create an identity client")
+	}
+	token, ok := identityClient.GetAuthResult().(tokensv3.CreateResult)
+	if !ok {
+		return nil, errors.Errorf("checking the roles is only supported with the keystone v3 API")
+	}
+	return &PermissionsClient{token: token}, nil
+}
+
+// GetRoles returns the names of the roles of the token in the project.
+func (c *PermissionsClient) GetRoles(ctx context.Context) ([]string, error) {
+	roles, err := c.token.ExtractRoles()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract the roles of the token")
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, nil
+}
+
+// GetServiceTypes returns the types of the services of the catalog of the token.
+func (c *PermissionsClient) GetServiceTypes(ctx context.Context) ([]string, error) {
+	catalog, err := c.token.ExtractServiceCatalog()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract the service catalog of the token")
+	}
+	serviceTypes := make([]string, 0, len(catalog.Entries))
+	for _, entry := range catalog.Entries {
+		serviceTypes = append(serviceTypes, entry.Type)
+	}
+	return serviceTypes, nil
+}
@@ -0,0 +1,73 @@
+package openstack
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/openstack/mock"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidatePermissions(t *testing.T) {
+	cases := []struct {
+		name         string
+		networkType  string
+		serviceTypes []string
+		roles        []string
+		err          string
+	}{{
+		name:  "member",
+		roles: []string{"member", "reader"},
+	}, {
+		name:  "legacy member",
+		roles: []string{"_member_"},
+	}, {
+		name:  "admin",
+		roles: []string{"admin"},
+	}, {
+		name:  "reader",
+		roles: []string{"reader"},
+		err:   "the credentials are missing the following roles in the project: member",
+	}, {
+		name:         "Kuryr without Octavia",
+		networkType:  "Kuryr",
+		serviceTypes: []string{"compute", "network"},
+		roles:        []string{"member"},
+	}, {
+		name:         "Kuryr with Octavia",
+		networkType:  "Kuryr",
+		serviceTypes: []string{"compute", "network", "load-balancer"},
+		roles:        []string{"member", "load-balancer_member"},
+	}, {
+		name:         "Kuryr without load balancer role",
+		networkType:  "Kuryr",
+		serviceTypes: []string{"compute", "network", "load-balancer"},
+		roles:        []string{"member"},
+		err:          "the credentials are missing the following roles in the project: load-balancer_member",
+	}, {
+		name:         "OpenShiftSDN with Octavia",
+		networkType:  "OpenShiftSDN",
+		serviceTypes: []string{"compute", "network", "load-balancer"},
+		roles:        []string{"member"},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			client := mock.NewMockPermissionsAPI(mockCtrl)
+			client.EXPECT().GetServiceTypes(gomock.Any()).Return(test.serviceTypes, nil)
+			client.EXPECT().GetRoles(gomock.Any()).Return(test.roles, nil)
+
+			ic := &types.InstallConfig{Networking: &types.Networking{NetworkType: test.networkType}}
+			err := ValidatePermissions(client, ic)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
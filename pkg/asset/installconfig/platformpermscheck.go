@@ -5,12 +5,16 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
 	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	azconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	ibmcloudconfig "github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
 	kubevirtconfig "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
-	"github.com/openshift/installer/pkg/types"
+	osconfig "github.com/openshift/installer/pkg/asset/installconfig/openstack"
+	vsconfig "github.com/openshift/installer/pkg/asset/installconfig/vsphere"
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/baremetal"
@@ -52,25 +56,7 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 	platform := ic.Config.Platform.Name()
 	switch platform {
 	case aws.Name:
-		permissionGroups := []awsconfig.PermissionGroup{awsconfig.PermissionCreateBase}
-		usingExistingVPC := len(ic.Config.AWS.Subnets) != 0
-
-		if !usingExistingVPC {
-			permissionGroups = append(permissionGroups, awsconfig.PermissionCreateNetworking)
-		}
-
-		// Add delete permissions for non-C2S installs.
-		if !aws.C2SRegions.Has(ic.Config.AWS.Region) {
-			permissionGroups = append(permissionGroups, awsconfig.PermissionDeleteBase)
-			if usingExistingVPC {
-				permissionGroups = append(permissionGroups, awsconfig.PermissionDeleteSharedNetworking)
-			} else {
-				permissionGroups = append(permissionGroups, awsconfig.PermissionDeleteNetworking)
-			}
-			if awsIncludesUserSuppliedInstanceRole(ic.Config) {
-				permissionGroups = append(permissionGroups, awsconfig.PermissionDeleteSharedInstanceRole)
-			}
-		}
+		permissionGroups := awsconfig.RequiredPermissionGroups(ic.Config)
 
 		ssn, err := ic.AWS.Session(ctx)
 		if err != nil {
@@ -81,6 +67,15 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return errors.Wrap(err, "validate AWS credentials")
 		}
+	case azure.Name:
+		client, err := ic.Azure.Client()
+		if err != nil {
+			return err
+		}
+
+		if err = azconfig.ValidatePermissions(client, ic.Config); err != nil {
+			return errors.Wrap(err, "validate Azure permissions")
+		}
 	case gcp.Name:
 		client, err := gcpconfig.NewClient(context.TODO())
 		if err != nil {
@@ -91,7 +86,16 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 			return errors.Wrap(err, "failed to validate services in this project")
 		}
 	case ibmcloud.Name:
-		// TODO: IBM[#90]: platformpermscheck
+		client, err := ibmcloudconfig.NewClient()
+		if err != nil {
+			return err
+		}
+
+		// The policies of the access groups are not checked, so the
+		// missing roles might still be granted.
+		if err = ibmcloudconfig.ValidatePermissions(client, ic.Config); err != nil {
+			logrus.Warnf("Failed to validate IBM Cloud permissions: %v", err)
+		}
 	case kubevirt.Name:
 		client, err := kubevirtconfig.NewClient()
 		if err != nil {
@@ -102,7 +106,22 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return errors.Wrap(err, "Kubevirt permissions validation failed")
 		}
-	case azure.Name, baremetal.Name, libvirt.Name, none.Name, openstack.Name, ovirt.Name, vsphere.Name:
+	case openstack.Name:
+		client, err := osconfig.NewPermissionsClient(ic.Config.OpenStack.Cloud)
+		if err != nil {
+			return err
+		}
+
+		// The roles of the default policies are checked, but the policies of
+		// the cloud might grant the permissions to other roles.
+		if err = osconfig.ValidatePermissions(client, ic.Config); err != nil {
+			logrus.Warnf("Failed to validate OpenStack permissions: %v", err)
+		}
+	case vsphere.Name:
+		if err = vsconfig.ValidatePermissions(ic.Config); err != nil {
+			return errors.Wrap(err, "validate vSphere permissions")
+		}
+	case baremetal.Name, libvirt.Name, none.Name, ovirt.Name:
 		// no permissions to check
 	default:
 		err = fmt.Errorf("unknown platform type %q", platform)
@@ -114,21 +133,3 @@ func (a *PlatformPermsCheck) Generate(dependencies asset.Parents) error {
 func (a *PlatformPermsCheck) Name() string {
 	return "Platform Permissions Check"
 }
-
-func awsIncludesUserSuppliedInstanceRole(installConfig *types.InstallConfig) bool {
-	mp := &aws.MachinePool{}
-	mp.Set(installConfig.Platform.AWS.DefaultMachinePlatform)
-	mp.Set(installConfig.ControlPlane.Platform.AWS)
-	if mp.IAMRole != "" {
-		return true
-	}
-	for _, c := range installConfig.Compute {
-		mp := &aws.MachinePool{}
-		mp.Set(installConfig.Platform.AWS.DefaultMachinePlatform)
-		mp.Set(c.Platform.AWS)
-		if mp.IAMRole != "" {
-			return true
-		}
-	}
-	return false
-}
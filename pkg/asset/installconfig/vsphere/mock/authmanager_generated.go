@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./permissions.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/vmware/govmomi/vim25/types"
+	reflect "reflect"
+)
+
+// MockAuthManager is a mock of AuthManager interface
+type MockAuthManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthManagerMockRecorder
+}
+
+// MockAuthManagerMockRecorder is the mock recorder for MockAuthManager
+type MockAuthManagerMockRecorder struct {
+	mock *MockAuthManager
+}
+
+// NewMockAuthManager creates a new mock instance
+func NewMockAuthManager(ctrl *gomock.Controller) *MockAuthManager {
+	mock := &MockAuthManager{ctrl: ctrl}
+	mock.recorder = &MockAuthManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuthManager) EXPECT() *MockAuthManagerMockRecorder {
+	return m.recorder
+}
+
+// HasPrivilegeOnEntity mocks base method
+func (m *MockAuthManager) HasPrivilegeOnEntity(ctx context.Context, entity types.ManagedObjectReference, sessionID string, privID []string) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPrivilegeOnEntity", ctx, entity, sessionID, privID)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPrivilegeOnEntity indicates an expected call of HasPrivilegeOnEntity
+func (mr *MockAuthManagerMockRecorder) HasPrivilegeOnEntity(ctx, entity, sessionID, privID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPrivilegeOnEntity", reflect.TypeOf((*MockAuthManager)(nil).HasPrivilegeOnEntity), ctx, entity, sessionID, privID)
+}
@@ -0,0 +1,290 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/session"
+	vim25types "github.com/vmware/govmomi/vim25/types"
+
+	"github.com/openshift/installer/pkg/types"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+//go:generate mockgen -source=./permissions.go -destination=mock/authmanager_generated.go -package=mock
+
+// AuthManager is the part of the vCenter authorization manager that checks
+// the privileges of a session.
+type AuthManager interface {
+	HasPrivilegeOnEntity(ctx context.Context, entity vim25types.ManagedObjectReference, sessionID string, privID []string) ([]bool, error)
+}
+
+// PermissionGroup is the group of privileges needed on a vSphere object by cluster creation, operation, or teardown.
+type PermissionGroup string
+
+const (
+	// PermissionVCenter is the set of privileges required on the vCenter.
+	PermissionVCenter PermissionGroup = "vcenter"
+
+	// PermissionCluster is the set of privileges required on the vCenter cluster.
+	PermissionCluster PermissionGroup = "cluster"
+
+	// PermissionDatastore is the set of privileges required on the datastore.
+	PermissionDatastore PermissionGroup = "datastore"
+
+	// PermissionPortgroup is the set of privileges required on the port group of the network.
+	PermissionPortgroup PermissionGroup = "portgroup"
+
+	// PermissionFolder is the set of privileges required on an existing virtual machine folder.
+	PermissionFolder PermissionGroup = "folder"
+
+	// PermissionDatacenter is the set of privileges required on the datacenter when the installer creates the virtual machine folder.
+	PermissionDatacenter PermissionGroup = "datacenter"
+)
+
+// virtualMachinePrivileges are the privileges required to create and manage
+// the virtual machines in their folder.
+var virtualMachinePrivileges = []string{
+	"Resource.AssignVMToPool",
+	"VApp.Import",
+	"VirtualMachine.Config.AddExistingDisk",
+	"VirtualMachine.Config.AddNewDisk",
+	"VirtualMachine.Config.AddRemoveDevice",
+	"VirtualMachine.Config.AdvancedConfig",
+	"VirtualMachine.Config.Annotation",
+	"VirtualMachine.Config.CPUCount",
+	"VirtualMachine.Config.DiskExtend",
+	"VirtualMachine.Config.DiskLease",
+	"VirtualMachine.Config.EditDevice",
+	"VirtualMachine.Config.Memory",
+	"VirtualMachine.Config.RemoveDisk",
+	"VirtualMachine.Config.Rename",
+	"VirtualMachine.Config.ResetGuestInfo",
+	"VirtualMachine.Config.Resource",
+	"VirtualMachine.Config.Settings",
+	"VirtualMachine.Config.UpgradeVirtualHardware",
+	"VirtualMachine.Interact.GuestControl",
+	"VirtualMachine.Interact.PowerOff",
+	"VirtualMachine.Interact.PowerOn",
+	"VirtualMachine.Interact.Reset",
+	"VirtualMachine.Inventory.Create",
+	"VirtualMachine.Inventory.CreateFromExisting",
+	"VirtualMachine.Inventory.Delete",
+	"VirtualMachine.Provisioning.Clone",
+}
+
+// permissions are the privileges of docs/user/vsphere/privileges.md.
+var permissions = map[PermissionGroup][]string{
+	PermissionVCenter: {
+		"Cns.Searchable",
+		"InventoryService.Tagging.AttachTag",
+		"InventoryService.Tagging.CreateCategory",
+		"InventoryService.Tagging.CreateTag",
+		"InventoryService.Tagging.DeleteCategory",
+		"InventoryService.Tagging.DeleteTag",
+		"InventoryService.Tagging.EditCategory",
+		"InventoryService.Tagging.EditTag",
+		"Sessions.ValidateSession",
+		"StorageProfile.View",
+	},
+	PermissionCluster: {
+		"Host.Config.Storage",
+		"Resource.AssignVMToPool",
+		"VApp.AssignResourcePool",
+		"VApp.Import",
+		"VirtualMachine.Config.AddNewDisk",
+	},
+	PermissionDatastore: {
+		"Datastore.AllocateSpace",
+		"Datastore.Browse",
+		"Datastore.FileManagement",
+	},
+	PermissionPortgroup: {
+		"Network.Assign",
+	},
+	PermissionFolder: virtualMachinePrivileges,
+	PermissionDatacenter: append([]string{
+		"Folder.Create",
+		"Folder.Delete",
+	}, virtualMachinePrivileges...),
+}
+
+// RequiredPermissionGroups returns the permission groups required to create and delete
+// the cluster of the install config.
+func RequiredPermissionGroups(ic *types.InstallConfig) []PermissionGroup {
+	return requiredPermissionGroups(ic.VSphere.Folder)
+}
+
+// requiredPermissionGroups returns the permission groups required to create
+// and delete the machines of a topology with the folder.
+func requiredPermissionGroups(folder string) []PermissionGroup {
+	permissionGroups := []PermissionGroup{PermissionVCenter, PermissionCluster, PermissionDatastore, PermissionPortgroup}
+	if folder != "" {
+		permissionGroups = append(permissionGroups, PermissionFolder)
+	} else {
+		permissionGroups = append(permissionGroups, PermissionDatacenter)
+	}
+	return permissionGroups
+}
+
+// Entity is a vSphere object on which the privileges of a permission group are checked.
+type Entity struct {
+	// Path is the inventory path of the object.
+	Path string
+
+	// Reference is the reference of the object.
+	Reference vim25types.ManagedObjectReference
+}
+
+// ValidatePermissions checks that the users of the install config have the
+// privileges of the required permission groups on the vSphere objects of the
+// install config, or of its failure domains.
+func ValidatePermissions(ic *types.InstallConfig) error {
+	cfg := ic.VSphere
+	if len(cfg.FailureDomains) == 0 {
+		return validateTopologyPermissions(vcenterOf(cfg, cfg.VCenter), []vspheretypes.Topology{{
+			Datacenter:     cfg.Datacenter,
+			ComputeCluster: cfg.Cluster,
+			Datastore:      cfg.DefaultDatastore,
+			Network:        cfg.Network,
+			Folder:         cfg.Folder,
+		}})
+	}
+
+	for _, vcenter := range cfg.AllVCenters() {
+		var topologies []vspheretypes.Topology
+		for _, fd := range cfg.FailureDomains {
+			if fd.Server == vcenter.Server {
+				topologies = append(topologies, fd.Topology)
+			}
+		}
+		if len(topologies) == 0 {
+			continue
+		}
+		if err := validateTopologyPermissions(vcenter, topologies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTopologyPermissions checks that the user of the vCenter has the
+// privileges of the required permission groups on the vSphere objects of
+// the topologies.
+func validateTopologyPermissions(vcenter vspheretypes.VCenter, topologies []vspheretypes.Topology) error {
+	ctx, cancel := context.WithTimeout(context.TODO(), 60*time.Second)
+	defer cancel()
+
+	vim25Client, _, err := vspheretypes.CreateVSphereClients(ctx, vcenter.Server, vcenter.Username, vcenter.Password)
+	if err != nil {
+		return errors.Wrapf(err, "unable to connect to vCenter %s API", vcenter.Server)
+	}
+
+	userSession, err := session.NewManager(vim25Client).UserSession(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the vCenter %s session", vcenter.Server)
+	}
+
+	authManager := object.NewAuthorizationManager(vim25Client)
+	for _, topology := range topologies {
+		groups := requiredPermissionGroups(topology.Folder)
+		entities, err := findEntities(ctx, find.NewFinder(vim25Client), topology, groups)
+		if err != nil {
+			return err
+		}
+		entities[PermissionVCenter] = Entity{Path: vcenter.Server, Reference: vim25Client.ServiceContent.RootFolder}
+
+		if err := ValidatePrivileges(ctx, authManager, userSession.Key, groups, entities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findEntities returns the objects of the topology on which the privileges
+// of the permission groups are checked.
+func findEntities(ctx context.Context, finder *find.Finder, topology vspheretypes.Topology, groups []PermissionGroup) (map[PermissionGroup]Entity, error) {
+	dc, err := finder.Datacenter(ctx, topology.Datacenter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find datacenter %s", topology.Datacenter)
+	}
+	finder.SetDatacenter(dc)
+
+	entities := map[PermissionGroup]Entity{}
+	for _, group := range groups {
+		var ref object.Reference
+		var path string
+		switch group {
+		case PermissionVCenter:
+			continue
+		case PermissionDatacenter:
+			ref, path = dc, dc.InventoryPath
+		case PermissionCluster:
+			cluster, err := finder.ClusterComputeResource(ctx, topology.ComputeCluster)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to find cluster %s", topology.ComputeCluster)
+			}
+			ref, path = cluster, cluster.InventoryPath
+		case PermissionDatastore:
+			datastore, err := finder.Datastore(ctx, topology.Datastore)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to find datastore %s", topology.Datastore)
+			}
+			ref, path = datastore, datastore.InventoryPath
+		case PermissionPortgroup:
+			network, err := finder.Network(ctx, topology.Network)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to find network %s", topology.Network)
+			}
+			ref, path = network, topology.Network
+		case PermissionFolder:
+			folder, err := finder.Folder(ctx, topology.Folder)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to find folder %s", topology.Folder)
+			}
+			ref, path = folder, folder.InventoryPath
+		default:
+			return nil, errors.Errorf("unknown permissions group %s", group)
+		}
+		entities[group] = Entity{Path: path, Reference: ref.Reference()}
+	}
+	return entities, nil
+}
+
+// ValidatePrivileges checks that the session has the privileges of the
+// permission groups on their entities.
+func ValidatePrivileges(ctx context.Context, authManager AuthManager, sessionID string, groups []PermissionGroup, entities map[PermissionGroup]Entity) error {
+	var missing []string
+	for _, group := range groups {
+		privileges, ok := permissions[group]
+		if !ok {
+			return errors.Errorf("unable to access permissions group %s", group)
+		}
+		entity, ok := entities[group]
+		if !ok {
+			return errors.Errorf("no vSphere object for permissions group %s", group)
+		}
+		granted, err := authManager.HasPrivilegeOnEntity(ctx, entity.Reference, sessionID, privileges)
+		if err != nil {
+			return errors.Wrapf(err, "unable to check the privileges on %s", entity.Path)
+		}
+		var groupMissing []string
+		for i, privilege := range privileges {
+			if i >= len(granted) || !granted[i] {
+				groupMissing = append(groupMissing, privilege)
+			}
+		}
+		if len(groupMissing) > 0 {
+			missing = append(missing, fmt.Sprintf("%s on %s", strings.Join(groupMissing, ", "), entity.Path))
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the user is missing the following privileges: %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
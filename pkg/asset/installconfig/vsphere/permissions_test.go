@@ -0,0 +1,74 @@
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	vim25types "github.com/vmware/govmomi/vim25/types"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/vsphere/mock"
+)
+
+func TestRequiredPermissionGroups(t *testing.T) {
+	ic := validIPIInstallConfig()
+	assert.Equal(t, []PermissionGroup{PermissionVCenter, PermissionCluster, PermissionDatastore, PermissionPortgroup, PermissionDatacenter}, RequiredPermissionGroups(ic))
+
+	ic.VSphere.Folder = "/valid_dc/vm/valid_folder"
+	assert.Equal(t, []PermissionGroup{PermissionVCenter, PermissionCluster, PermissionDatastore, PermissionPortgroup, PermissionFolder}, RequiredPermissionGroups(ic))
+}
+
+func TestValidatePrivileges(t *testing.T) {
+	const sessionID = "session"
+	groups := []PermissionGroup{PermissionDatastore, PermissionPortgroup}
+	entities := map[PermissionGroup]Entity{
+		PermissionDatastore: {Path: "/valid_dc/datastore/valid_ds", Reference: vim25types.ManagedObjectReference{Type: "Datastore", Value: "datastore-1"}},
+		PermissionPortgroup: {Path: "valid_network", Reference: vim25types.ManagedObjectReference{Type: "Network", Value: "network-1"}},
+	}
+
+	cases := []struct {
+		name      string
+		datastore []bool
+		portgroup []bool
+		checkErr  error
+		err       string
+	}{{
+		name:      "all privileges",
+		datastore: []bool{true, true, true},
+		portgroup: []bool{true},
+	}, {
+		name:      "missing privileges",
+		datastore: []bool{true, false, false},
+		portgroup: []bool{false},
+		err:       "the user is missing the following privileges: Datastore.Browse, Datastore.FileManagement on /valid_dc/datastore/valid_ds; Network.Assign on valid_network",
+	}, {
+		name:     "check failure",
+		checkErr: errors.New("not authenticated"),
+		err:      "unable to check the privileges on /valid_dc/datastore/valid_ds: not authenticated",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authManager := mock.NewMockAuthManager(mockCtrl)
+			authManager.EXPECT().
+				HasPrivilegeOnEntity(gomock.Any(), entities[PermissionDatastore].Reference, sessionID, permissions[PermissionDatastore]).
+				Return(test.datastore, test.checkErr)
+			if test.checkErr == nil {
+				authManager.EXPECT().
+					HasPrivilegeOnEntity(gomock.Any(), entities[PermissionPortgroup].Reference, sessionID, permissions[PermissionPortgroup]).
+					Return(test.portgroup, nil)
+			}
+
+			err := ValidatePrivileges(context.Background(), authManager, sessionID, groups, entities)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -20,7 +20,15 @@ func Validate(ic *types.InstallConfig) error {
 		return errors.New(field.Required(field.NewPath("platform", "vsphere"), "vSphere validation requires a vSphere platform configuration").Error())
 	}
 
-	allErrs = append(allErrs, validation.ValidatePlatform(ic.Platform.VSphere, field.NewPath("platform").Child("vsphere"))...)
+	var machineNetworks []types.MachineNetworkEntry
+	if ic.Networking != nil {
+		machineNetworks = ic.Networking.MachineNetwork
+	}
+	var controlPlaneReplicas *int64
+	if ic.ControlPlane != nil {
+		controlPlaneReplicas = ic.ControlPlane.Replicas
+	}
+	allErrs = append(allErrs, validation.ValidatePlatform(ic.Platform.VSphere, machineNetworks, controlPlaneReplicas, field.NewPath("platform").Child("vsphere"))...)
 
 	return allErrs.ToAggregate()
 }
@@ -35,22 +43,34 @@ func ValidateForProvisioning(ic *types.InstallConfig) error {
 	}
 
 	allErrs = append(allErrs, validation.ValidateForProvisioning(ic.Platform.VSphere, field.NewPath("platform").Child("vsphere"))...)
-	allErrs = append(allErrs, folderExists(ic, field.NewPath("platform").Child("vsphere").Child("folder"))...)
+	allErrs = append(allErrs, folderExists(ic, field.NewPath("platform").Child("vsphere"))...)
 
 	return allErrs.ToAggregate()
 }
 
-// folderExists returns an error if a folder is specified in the vSphere platform but a folder with that name is not found in the datacenter.
+// folderExists returns an error if a folder is specified in the vSphere platform or in one of its failure
+// domains but a folder with that name is not found in the datacenter.
 func folderExists(ic *types.InstallConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	cfg := ic.VSphere
 
+	allErrs = append(allErrs, findFolder(vcenterOf(cfg, cfg.VCenter), cfg.Folder, fldPath.Child("folder"))...)
+	for i, fd := range cfg.FailureDomains {
+		allErrs = append(allErrs, findFolder(vcenterOf(cfg, fd.Server), fd.Topology.Folder, fldPath.Child("failureDomains").Index(i).Child("topology", "folder"))...)
+	}
+	return allErrs
+}
+
+// findFolder returns an error if the folder is not found in the vCenter.
+func findFolder(vcenter vspheretypes.VCenter, folder string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
 	// If no folder is specified, skip this check as the folder will be created.
-	if cfg.Folder == "" {
+	if folder == "" {
 		return allErrs
 	}
 
-	vim25Client, _, err := vspheretypes.CreateVSphereClients(context.TODO(), cfg.VCenter, cfg.Username, cfg.Password)
+	vim25Client, _, err := vspheretypes.CreateVSphereClients(context.TODO(), vcenter.Server, vcenter.Username, vcenter.Password)
 	if err != nil {
 		err = errors.Wrap(err, "unable to connect to vCenter API")
 		return append(allErrs, field.InternalError(fldPath, err))
@@ -61,8 +81,20 @@ func folderExists(ic *types.InstallConfig, fldPath *field.Path) field.ErrorList
 	ctx, cancel := context.WithTimeout(context.TODO(), 60*time.Second)
 	defer cancel()
 
-	if _, err = finder.Folder(ctx, cfg.Folder); err != nil {
-		return append(allErrs, field.Invalid(fldPath, cfg.Folder, err.Error()))
+	if _, err = finder.Folder(ctx, folder); err != nil {
+		return append(allErrs, field.Invalid(fldPath, folder, err.Error()))
 	}
 	return nil
 }
+
+// vcenterOf returns the vCenter of the server, which is the vCenter of the
+// platform when the server is empty.
+func vcenterOf(p *vspheretypes.Platform, server string) vspheretypes.VCenter {
+	vcenters := p.AllVCenters()
+	for _, vcenter := range vcenters {
+		if vcenter.Server == server {
+			return vcenter
+		}
+	}
+	return vcenters[0]
+}
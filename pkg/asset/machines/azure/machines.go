@@ -132,6 +132,7 @@ func provider(platform *azure.Platform, mpool *azure.MachinePool, osImage string
 		ResourceGroup:        rg,
 		NetworkResourceGroup: networkResourceGroup,
 		PublicLoadBalancer:   publicLB,
+		Tags:                 platform.UserTags,
 	}, nil
 }
 
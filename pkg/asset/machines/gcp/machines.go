@@ -119,6 +119,7 @@ func provider(clusterID string, platform *gcp.Platform, mpool *gcp.MachinePool,
 		Region:      platform.Region,
 		Zone:        az,
 		ProjectID:   platform.ProjectID,
+		Labels:      platform.UserLabels,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package ibmcloud
 
 import (
 	"fmt"
+	"sort"
 
 	ibmcloudprovider "github.com/openshift/cluster-api-provider-ibmcloud/pkg/apis/ibmcloudprovider/v1beta1"
 	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
@@ -104,7 +105,7 @@ func provider(clusterID string,
 			Kind:       "IBMCloudMachineProviderSpec",
 		},
 		VPC:           vpc,
-		Tags:          []ibmcloudprovider.TagSpecs{},
+		Tags:          tagSpecs(platform.UserTags),
 		Image:         fmt.Sprintf("%s-rhcos", clusterID),
 		Profile:       mpool.InstanceType,
 		Region:        platform.Region,
@@ -120,6 +121,16 @@ func provider(clusterID string,
 	}, nil
 }
 
+// tagSpecs returns the user tags sorted by name.
+func tagSpecs(tags map[string]string) []ibmcloudprovider.TagSpecs {
+	specs := make([]ibmcloudprovider.TagSpecs, 0, len(tags))
+	for name, value := range tags {
+		specs = append(specs, ibmcloudprovider.TagSpecs{Name: name, Value: value})
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
 func getSubnetName(clusterID string, role string, zone string) (string, error) {
 	switch role {
 	case "master":
@@ -0,0 +1,76 @@
+package machineconfig
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	machineConfigPoolFileName = "99_openshift-machineconfigpool_%s.yaml"
+)
+
+var (
+	machineConfigPoolFileNamePattern = fmt.Sprintf(machineConfigPoolFileName, "*")
+)
+
+// ForCustomPool creates the MachineConfigPool for the machines of a custom
+// compute role. The pool selects the nodes labeled with the role and both the
+// worker MachineConfigs and the MachineConfigs of the role, so that machines
+// in the pool are configured like workers plus their role-specific changes.
+func ForCustomPool(role string) *mcfgv1.MachineConfigPool {
+	return &mcfgv1.MachineConfigPool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfigPool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: role,
+		},
+		Spec: mcfgv1.MachineConfigPoolSpec{
+			MachineConfigSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "machineconfiguration.openshift.io/role",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{"worker", role},
+				}},
+			},
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					NodeRoleLabel(role): "",
+				},
+			},
+		},
+	}
+}
+
+// NodeRoleLabel returns the label of the nodes with the given role.
+func NodeRoleLabel(role string) string {
+	return fmt.Sprintf("node-role.kubernetes.io/%s", role)
+}
+
+// PoolManifests creates manifest files containing the MachineConfigPools.
+func PoolManifests(pools []*mcfgv1.MachineConfigPool, directory string) ([]*asset.File, error) {
+	var ret []*asset.File
+	for _, p := range pools {
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &asset.File{
+			Filename: filepath.Join(directory, fmt.Sprintf(machineConfigPoolFileName, p.ObjectMeta.Name)),
+			Data:     data,
+		})
+	}
+	return ret, nil
+}
+
+// LoadPools loads the MachineConfigPool manifests.
+func LoadPools(f asset.FileFetcher, directory string) ([]*asset.File, error) {
+	return f.FetchByPattern(filepath.Join(directory, machineConfigPoolFileNamePattern))
+}
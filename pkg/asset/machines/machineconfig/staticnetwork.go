@@ -0,0 +1,114 @@
+package machineconfig
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
+	mcfgv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// ForStaticNetwork creates the MachineConfig with the NetworkManager keyfiles
+// of the static network configurations of the hosts. Each keyfile matches the
+// network interface of its host by MAC address, so all the machines of the
+// role get all the keyfiles.
+func ForStaticNetwork(role string, devices []vsphere.NetworkDeviceSpec) (*mcfgv1.MachineConfig, error) {
+	files := make([]igntypes.File, 0, len(devices))
+	for _, device := range devices {
+		id := "static-" + strings.ReplaceAll(strings.ToLower(device.MACAddress), ":", "")
+		files = append(files, ignition.FileFromString(
+			fmt.Sprintf("/etc/NetworkManager/system-connections/%s.nmconnection", id),
+			"root", 0600, networkKeyfile(id, device)))
+	}
+
+	ignConfig := igntypes.Config{
+		Ignition: igntypes.Ignition{
+			Version: igntypes.MaxVersion.String(),
+		},
+		Storage: igntypes.Storage{
+			Files: files,
+		},
+	}
+
+	rawExt, err := ignition.ConvertToRawExtension(ignConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcfgv1.MachineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: mcfgv1.SchemeGroupVersion.String(),
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("99-%s-static-network", role),
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: mcfgv1.MachineConfigSpec{
+			Config: rawExt,
+		},
+	}, nil
+}
+
+// networkKeyfile returns the NetworkManager keyfile of the connection of a
+// network device.
+func networkKeyfile(id string, device vsphere.NetworkDeviceSpec) string {
+	type ipFamily struct {
+		name        string
+		addresses   []string
+		nameservers []string
+	}
+	ipv4, ipv6 := &ipFamily{name: "ipv4"}, &ipFamily{name: "ipv6"}
+	familyOf := func(ip net.IP) *ipFamily {
+		if ip.To4() != nil {
+			return ipv4
+		}
+		return ipv6
+	}
+
+	gateway := net.ParseIP(device.Gateway)
+	for _, addr := range device.IPAddrs {
+		ip, subnet, err := net.ParseCIDR(addr)
+		if err != nil {
+			continue
+		}
+		family := familyOf(ip)
+		address := fmt.Sprintf("address%d=%s", len(family.addresses)+1, addr)
+		if gateway != nil && subnet.Contains(gateway) {
+			address += "," + gateway.String()
+		}
+		family.addresses = append(family.addresses, address)
+	}
+	for _, nameserver := range device.Nameservers {
+		if ip := net.ParseIP(nameserver); ip != nil {
+			family := familyOf(ip)
+			family.nameservers = append(family.nameservers, nameserver+";")
+		}
+	}
+
+	var keyfile strings.Builder
+	fmt.Fprintf(&keyfile, "[connection]\nid=%s\ntype=ethernet\nautoconnect-priority=10\n\n", id)
+	fmt.Fprintf(&keyfile, "[ethernet]\nmac-address=%s\n", strings.ToUpper(device.MACAddress))
+	for _, family := range []*ipFamily{ipv4, ipv6} {
+		fmt.Fprintf(&keyfile, "\n[%s]\n", family.name)
+		if len(family.addresses) == 0 {
+			keyfile.WriteString("method=disabled\n")
+			continue
+		}
+		keyfile.WriteString("method=manual\n")
+		for _, address := range family.addresses {
+			keyfile.WriteString(address + "\n")
+		}
+		if len(family.nameservers) > 0 {
+			fmt.Fprintf(&keyfile, "dns=%s\n", strings.Join(family.nameservers, ""))
+		}
+	}
+	return keyfile.String()
+}
@@ -0,0 +1,74 @@
+package machineconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestNetworkKeyfile(t *testing.T) {
+	cases := []struct {
+		name     string
+		device   vsphere.NetworkDeviceSpec
+		expected string
+	}{
+		{
+			name: "IPv4",
+			device: vsphere.NetworkDeviceSpec{
+				MACAddress:  "00:50:56:aa:bb:cc",
+				IPAddrs:     []string{"10.0.0.20/24"},
+				Gateway:     "10.0.0.1",
+				Nameservers: []string{"10.0.0.2", "10.0.0.3"},
+			},
+			expected: `[connection]
+id=static-005056aabbcc
+type=ethernet
+autoconnect-priority=10
+
+[ethernet]
+mac-address=00:50:56:AA:BB:CC
+
+[ipv4]
+method=manual
+address1=10.0.0.20/24,10.0.0.1
+dns=10.0.0.2;10.0.0.3;
+
+[ipv6]
+method=disabled
+`,
+		},
+		{
+			name: "dual stack",
+			device: vsphere.NetworkDeviceSpec{
+				MACAddress:  "00:50:56:aa:bb:cc",
+				IPAddrs:     []string{"10.0.0.20/24", "fd00::20/64"},
+				Gateway:     "fd00::1",
+				Nameservers: []string{"fd00::2"},
+			},
+			expected: `[connection]
+id=static-005056aabbcc
+type=ethernet
+autoconnect-priority=10
+
+[ethernet]
+mac-address=00:50:56:AA:BB:CC
+
+[ipv4]
+method=manual
+address1=10.0.0.20/24
+
+[ipv6]
+method=manual
+address1=fd00::20/64,fd00::1
+dns=fd00::2;
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, networkKeyfile("static-005056aabbcc", tc.device))
+		})
+	}
+}
@@ -25,6 +25,10 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	}
 	platform := config.Platform.VSphere
 	mpool := pool.Platform.VSphere
+	failureDomains, err := poolFailureDomains(platform, mpool)
+	if err != nil {
+		return nil, err
+	}
 
 	total := int64(1)
 	if pool.Replicas != nil {
@@ -32,7 +36,11 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	}
 	var machines []machineapi.Machine
 	for idx := int64(0); idx < total; idx++ {
-		provider, err := provider(clusterID, platform, mpool, osImage, userDataSecret)
+		var failureDomain *vsphere.FailureDomain
+		if len(failureDomains) > 0 {
+			failureDomain = &failureDomains[int(idx)%len(failureDomains)]
+		}
+		provider, err := provider(clusterID, platform, mpool, failureDomain, osImage, userDataSecret)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create provider")
 		}
@@ -63,12 +71,59 @@ func Machines(clusterID string, config *types.InstallConfig, pool *types.Machine
 	return machines, nil
 }
 
-func provider(clusterID string, platform *vsphere.Platform, mpool *vsphere.MachinePool, osImage string, userDataSecret string) (*vsphereapis.VSphereMachineProviderSpec, error) {
-	folder := fmt.Sprintf("/%s/vm/%s", platform.Datacenter, clusterID)
-	resourcePool := fmt.Sprintf("/%s/host/%s/Resources", platform.Datacenter, platform.Cluster)
-	if platform.Folder != "" {
-		folder = platform.Folder
+// poolFailureDomains returns the failure domains of the zones of the pool,
+// all the failure domains when the pool has no zones, or none when the
+// platform has no failure domains.
+func poolFailureDomains(platform *vsphere.Platform, mpool *vsphere.MachinePool) ([]vsphere.FailureDomain, error) {
+	if len(mpool.Zones) == 0 {
+		return platform.FailureDomains, nil
+	}
+	failureDomains := make([]vsphere.FailureDomain, 0, len(mpool.Zones))
+	for _, zone := range mpool.Zones {
+		found := false
+		for _, fd := range platform.FailureDomains {
+			if fd.Name == zone {
+				failureDomains = append(failureDomains, fd)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("unknown failure domain %q", zone)
+		}
+	}
+	return failureDomains, nil
+}
+
+// TemplateName returns the name of the template the machines of the
+// failure domain are cloned from. Each failure domain has its own copy of
+// the template of the cluster, named after it.
+func TemplateName(template string, failureDomain *vsphere.FailureDomain) string {
+	if failureDomain == nil {
+		return template
+	}
+	return fmt.Sprintf("%s-%s", template, failureDomain.Name)
+}
+
+func provider(clusterID string, platform *vsphere.Platform, mpool *vsphere.MachinePool, failureDomain *vsphere.FailureDomain, osImage string, userDataSecret string) (*vsphereapis.VSphereMachineProviderSpec, error) {
+	server := platform.VCenter
+	datacenter := platform.Datacenter
+	cluster := platform.Cluster
+	datastore := platform.DefaultDatastore
+	network := platform.Network
+	folder := platform.Folder
+	if failureDomain != nil {
+		server = failureDomain.Server
+		datacenter = failureDomain.Topology.Datacenter
+		cluster = failureDomain.Topology.ComputeCluster
+		datastore = failureDomain.Topology.Datastore
+		network = failureDomain.Topology.Network
+		folder = failureDomain.Topology.Folder
+	}
+	if folder == "" {
+		folder = fmt.Sprintf("/%s/vm/%s", datacenter, clusterID)
 	}
+	resourcePool := fmt.Sprintf("/%s/host/%s/Resources", datacenter, cluster)
 
 	return &vsphereapis.VSphereMachineProviderSpec{
 		TypeMeta: metav1.TypeMeta{
@@ -77,18 +132,18 @@ func provider(clusterID string, platform *vsphere.Platform, mpool *vsphere.Machi
 		},
 		UserDataSecret:    &corev1.LocalObjectReference{Name: userDataSecret},
 		CredentialsSecret: &corev1.LocalObjectReference{Name: "vsphere-cloud-credentials"},
-		Template:          osImage,
+		Template:          TemplateName(osImage, failureDomain),
 		Network: vsphereapis.NetworkSpec{
 			Devices: []vsphereapis.NetworkDeviceSpec{
 				{
-					NetworkName: platform.Network,
+					NetworkName: network,
 				},
 			},
 		},
 		Workspace: &vsphereapis.Workspace{
-			Server:       platform.VCenter,
-			Datacenter:   platform.Datacenter,
-			Datastore:    platform.DefaultDatastore,
+			Server:       server,
+			Datacenter:   datacenter,
+			Datastore:    datastore,
 			Folder:       folder,
 			ResourcePool: resourcePool,
 		},
@@ -24,18 +24,48 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 
 	platform := config.Platform.VSphere
 	mpool := pool.Platform.VSphere
+	failureDomains, err := poolFailureDomains(platform, mpool)
+	if err != nil {
+		return nil, err
+	}
 
 	total := int32(0)
 	if pool.Replicas != nil {
 		total = int32(*pool.Replicas)
 	}
-	var machinesets []*machineapi.MachineSet
-	provider, err := provider(clusterID, platform, mpool, osImage, userDataSecret)
+	if len(failureDomains) == 0 {
+		mset, err := machineSet(clusterID, platform, mpool, nil, fmt.Sprintf("%s-%s", clusterID, pool.Name), total, osImage, role, userDataSecret)
+		if err != nil {
+			return nil, err
+		}
+		return []*machineapi.MachineSet{mset}, nil
+	}
+
+	// The replicas are spread across the failure domains of the pool,
+	// the first failure domains getting the remainder.
+	numOfFailureDomains := int32(len(failureDomains))
+	machinesets := make([]*machineapi.MachineSet, 0, len(failureDomains))
+	for idx := range failureDomains {
+		replicas := total / numOfFailureDomains
+		if int32(idx) < total%numOfFailureDomains {
+			replicas++
+		}
+		name := fmt.Sprintf("%s-%s-%s", clusterID, pool.Name, failureDomains[idx].Name)
+		mset, err := machineSet(clusterID, platform, mpool, &failureDomains[idx], name, replicas, osImage, role, userDataSecret)
+		if err != nil {
+			return nil, err
+		}
+		machinesets = append(machinesets, mset)
+	}
+	return machinesets, nil
+}
+
+func machineSet(clusterID string, platform *vsphere.Platform, mpool *vsphere.MachinePool, failureDomain *vsphere.FailureDomain, name string, replicas int32, osImage, role, userDataSecret string) (*machineapi.MachineSet, error) {
+	provider, err := provider(clusterID, platform, mpool, failureDomain, osImage, userDataSecret)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create provider")
 	}
 
-	name := fmt.Sprintf("%s-%s", clusterID, pool.Name)
 	mset := &machineapi.MachineSet{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "machine.openshift.io/v1beta1",
@@ -49,7 +79,7 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 			},
 		},
 		Spec: machineapi.MachineSetSpec{
-			Replicas: &total,
+			Replicas: &replicas,
 			Selector: metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"machine.openshift.io/cluster-api-machineset": name,
@@ -74,7 +104,5 @@ func MachineSets(clusterID string, config *types.InstallConfig, pool *types.Mach
 			},
 		},
 	}
-	machinesets = append(machinesets, mset)
-
-	return machinesets, nil
+	return mset, nil
 }
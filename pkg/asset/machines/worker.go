@@ -2,11 +2,15 @@ package machines
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
+	ignutil "github.com/coreos/ignition/v2/config/util"
+	igntypes "github.com/coreos/ignition/v2/config/v3_2/types"
 	"github.com/ghodss/yaml"
 	baremetalapi "github.com/metal3-io/cluster-api-provider-baremetal/pkg/apis"
 	baremetalprovider "github.com/metal3-io/cluster-api-provider-baremetal/pkg/apis/baremetal/v1alpha1"
@@ -36,6 +40,7 @@ import (
 	openstackprovider "sigs.k8s.io/cluster-api-provider-openstack/pkg/apis/openstackproviderconfig/v1alpha1"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/machines/aws"
@@ -73,10 +78,22 @@ const (
 
 	// workerUserDataFileName is the filename used for the worker user-data secret.
 	workerUserDataFileName = "99_openshift-cluster-api_worker-user-data-secret.yaml"
+
+	// poolUserDataFileName is the format string for constructing the user-data secret filenames of the custom
+	// compute pools.
+	poolUserDataFileName = "99_openshift-cluster-api_worker-user-data-secret-%s.yaml"
+
+	// workerRole is the role of the machines in the worker compute pool.
+	workerRole = "worker"
+
+	// machineRoleLabel and machineTypeLabel are the labels of the machines that name their role.
+	machineRoleLabel = "machine.openshift.io/cluster-api-machine-role"
+	machineTypeLabel = "machine.openshift.io/cluster-api-machine-type"
 )
 
 var (
 	workerMachineSetFileNamePattern = fmt.Sprintf(workerMachineSetFileName, "*")
+	poolUserDataFileNamePattern     = fmt.Sprintf(poolUserDataFileName, "*")
 
 	_ asset.WritableAsset = (*Worker)(nil)
 )
@@ -172,11 +189,14 @@ func awsDefaultWorkerMachineTypes(region string, arch types.Architecture) []stri
 	return types
 }
 
-// Worker generates the machinesets for `worker` machine pool.
+// Worker generates the machinesets for the compute machine pools. Pools other than `worker` get their own
+// MachineConfigPool and user-data secret, and their machines are labeled with the role of the pool.
 type Worker struct {
-	UserDataFile       *asset.File
-	MachineConfigFiles []*asset.File
-	MachineSetFiles    []*asset.File
+	UserDataFile           *asset.File
+	PoolUserDataFiles      []*asset.File
+	MachineConfigFiles     []*asset.File
+	MachineConfigPoolFiles []*asset.File
+	MachineSetFiles        []*asset.File
 }
 
 // Name returns a human friendly name for the Worker Asset.
@@ -209,31 +229,41 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 	dependencies.Get(clusterID, installConfig, rhcosImage, wign)
 
 	machineConfigs := []*mcfgv1.MachineConfig{}
-	machineSets := []runtime.Object{}
+	machineSets := []*machineapi.MachineSet{}
+	customPools := []*mcfgv1.MachineConfigPool{}
 	var err error
 	ic := installConfig.Config
 	for _, pool := range ic.Compute {
+		// The machines of each pool get the role of the pool. The custom pools also select the worker
+		// MachineConfigs, so only the MachineConfigs that differ between pools are scoped to the role.
+		// The providers look up the infrastructure of the machines, like their subnets, security groups
+		// and instance profiles, by role, and that infrastructure only exists for workers, so they
+		// always get the worker role and the role of the pool is set on the labels of the machines.
+		role := pool.Name
 		if pool.Hyperthreading == types.HyperthreadingDisabled {
-			ignHT, err := machineconfig.ForHyperthreadingDisabled("worker")
+			ignHT, err := machineconfig.ForHyperthreadingDisabled(role)
 			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for hyperthreading disabled for worker machines")
+				return errors.Wrapf(err, "failed to create ignition for hyperthreading disabled for %s machines", role)
 			}
 			machineConfigs = append(machineConfigs, ignHT)
 		}
-		if ic.SSHKey != "" {
-			ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKey, "worker")
+		userDataSecretName := fmt.Sprintf("%s-user-data", role)
+		if role != workerRole {
+			customPools = append(customPools, machineconfig.ForCustomPool(role))
+			ign, err := poolPointerIgnition(wign.File.Data, role)
 			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for authorized SSH keys for worker machines")
+				return errors.Wrapf(err, "failed to create ignition for %s machines", role)
 			}
-			machineConfigs = append(machineConfigs, ignSSH)
-		}
-		if ic.FIPS {
-			ignFIPS, err := machineconfig.ForFIPSEnabled("worker")
+			data, err := userDataSecret(userDataSecretName, ign)
 			if err != nil {
-				return errors.Wrap(err, "failed to create ignition for FIPS enabled for worker machines")
+				return errors.Wrapf(err, "failed to create user-data secret for %s machines", role)
 			}
-			machineConfigs = append(machineConfigs, ignFIPS)
+			w.PoolUserDataFiles = append(w.PoolUserDataFiles, &asset.File{
+				Filename: filepath.Join(directory, fmt.Sprintf(poolUserDataFileName, role)),
+				Data:     data,
+			})
 		}
+		poolSetsStart := len(machineSets)
 		switch ic.Platform.Name() {
 		case awstypes.Name:
 			subnets := map[string]string{}
@@ -293,16 +323,14 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 				installConfig.Config.Platform.AWS.Region,
 				subnets,
 				&pool,
-				"worker",
-				"worker-user-data",
+				workerRole,
+				userDataSecretName,
 				installConfig.Config.Platform.AWS.UserTags,
 			)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case azuretypes.Name:
 			mpool := defaultAzureMachinePoolPlatform()
 			mpool.InstanceType = azuredefaults.ComputeInstanceType(
@@ -329,25 +357,21 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			}
 
 			pool.Platform.Azure = &mpool
-			sets, err := azure.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), "worker", "worker-user-data")
+			sets, err := azure.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case baremetaltypes.Name:
 			mpool := defaultBareMetalMachinePoolPlatform()
 			mpool.Set(ic.Platform.BareMetal.DefaultMachinePlatform)
 			mpool.Set(pool.Platform.BareMetal)
 			pool.Platform.BareMetal = &mpool
-			sets, err := baremetal.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), "worker", "worker-user-data")
+			sets, err := baremetal.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case gcptypes.Name:
 			mpool := defaultGCPMachinePoolPlatform()
 			mpool.Set(ic.Platform.GCP.DefaultMachinePlatform)
@@ -360,13 +384,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 				mpool.Zones = azs
 			}
 			pool.Platform.GCP = &mpool
-			sets, err := gcp.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), "worker", "worker-user-data")
+			sets, err := gcp.MachineSets(clusterID.InfraID, ic, &pool, string(*rhcosImage), workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case ibmcloudtypes.Name:
 			mpool := defaultIBMCloudMachinePoolPlatform()
 			mpool.Set(ic.Platform.IBMCloud.DefaultMachinePlatform)
@@ -379,25 +401,21 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 				mpool.Zones = azs
 			}
 			pool.Platform.IBMCloud = &mpool
-			sets, err := ibmcloud.MachineSets(clusterID.InfraID, ic, &pool, "worker", "worker-user-data")
+			sets, err := ibmcloud.MachineSets(clusterID.InfraID, ic, &pool, workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case libvirttypes.Name:
 			mpool := defaultLibvirtMachinePoolPlatform()
 			mpool.Set(ic.Platform.Libvirt.DefaultMachinePlatform)
 			mpool.Set(pool.Platform.Libvirt)
 			pool.Platform.Libvirt = &mpool
-			sets, err := libvirt.MachineSets(clusterID.InfraID, ic, &pool, "worker", "worker-user-data")
+			sets, err := libvirt.MachineSets(clusterID.InfraID, ic, &pool, workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case openstacktypes.Name:
 			mpool := defaultOpenStackMachinePoolPlatform()
 			mpool.Set(ic.Platform.OpenStack.DefaultMachinePlatform)
@@ -406,13 +424,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 			imageName, _ := rhcosutils.GenerateOpenStackImageName(string(*rhcosImage), clusterID.InfraID)
 
-			sets, err := openstack.MachineSets(clusterID.InfraID, ic, &pool, imageName, "worker", "worker-user-data", nil)
+			sets, err := openstack.MachineSets(clusterID.InfraID, ic, &pool, imageName, workerRole, userDataSecretName, nil)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case vspheretypes.Name:
 			mpool := defaultVSphereMachinePoolPlatform()
 			mpool.Set(ic.Platform.VSphere.DefaultMachinePlatform)
@@ -420,13 +436,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 			pool.Platform.VSphere = &mpool
 			templateName := clusterID.InfraID + "-rhcos"
 
-			sets, err := vsphere.MachineSets(clusterID.InfraID, ic, &pool, templateName, "worker", "worker-user-data")
+			sets, err := vsphere.MachineSets(clusterID.InfraID, ic, &pool, templateName, workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case ovirttypes.Name:
 			mpool := defaultOvirtMachinePoolPlatform()
 			mpool.Set(ic.Platform.Ovirt.DefaultMachinePlatform)
@@ -435,13 +449,11 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 			imageName, _ := rhcosutils.GenerateOpenStackImageName(string(*rhcosImage), clusterID.InfraID)
 
-			sets, err := ovirt.MachineSets(clusterID.InfraID, ic, &pool, imageName, "worker", "worker-user-data")
+			sets, err := ovirt.MachineSets(clusterID.InfraID, ic, &pool, imageName, workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects for ovirt provider")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects for ovirt provider", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case kubevirttypes.Name:
 			mpool := defaultKubevirtMachinePoolPlatform()
 			mpool.Set(ic.Platform.Kubevirt.DefaultMachinePlatform)
@@ -450,17 +462,58 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 			imageName, _ := rhcosutils.GenerateOpenStackImageName(string(*rhcosImage), clusterID.InfraID)
 
-			sets, err := kubevirt.MachineSets(clusterID.InfraID, ic, &pool, imageName, "worker", "worker-user-data")
+			sets, err := kubevirt.MachineSets(clusterID.InfraID, ic, &pool, imageName, workerRole, userDataSecretName)
 			if err != nil {
-				return errors.Wrap(err, "failed to create worker machine objects for kubevirt provider")
-			}
-			for _, set := range sets {
-				machineSets = append(machineSets, set)
+				return errors.Wrapf(err, "failed to create %s machine objects for kubevirt provider", role)
 			}
+			machineSets = append(machineSets, sets...)
 		case nonetypes.Name:
 		default:
 			return fmt.Errorf("invalid Platform")
 		}
+		if role != workerRole {
+			for _, set := range machineSets[poolSetsStart:] {
+				labels := set.Spec.Template.ObjectMeta.Labels
+				for _, label := range []string{machineRoleLabel, machineTypeLabel} {
+					if _, ok := labels[label]; ok {
+						labels[label] = role
+					}
+				}
+				if set.Spec.Template.Spec.ObjectMeta.Labels == nil {
+					set.Spec.Template.Spec.ObjectMeta.Labels = map[string]string{}
+				}
+				set.Spec.Template.Spec.ObjectMeta.Labels[machineconfig.NodeRoleLabel(role)] = ""
+			}
+		}
+	}
+	if ic.SSHKey != "" {
+		ignSSH, err := machineconfig.ForAuthorizedKeys(ic.SSHKey, workerRole)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for authorized SSH keys for worker machines")
+		}
+		machineConfigs = append(machineConfigs, ignSSH)
+	}
+	if ic.FIPS {
+		ignFIPS, err := machineconfig.ForFIPSEnabled(workerRole)
+		if err != nil {
+			return errors.Wrap(err, "failed to create ignition for FIPS enabled for worker machines")
+		}
+		machineConfigs = append(machineConfigs, ignFIPS)
+	}
+	if ic.Platform.VSphere != nil {
+		var devices []vspheretypes.NetworkDeviceSpec
+		for _, host := range ic.Platform.VSphere.Hosts {
+			if host.Role == vspheretypes.ComputeRole {
+				devices = append(devices, host.NetworkDevice)
+			}
+		}
+		if len(devices) > 0 {
+			ignNetwork, err := machineconfig.ForStaticNetwork(workerRole, devices)
+			if err != nil {
+				return errors.Wrap(err, "failed to create ignition for the static network of worker machines")
+			}
+			machineConfigs = append(machineConfigs, ignNetwork)
+		}
 	}
 
 	data, err := userDataSecret("worker-user-data", wign.File.Data)
@@ -472,11 +525,16 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 		Data:     data,
 	}
 
-	w.MachineConfigFiles, err = machineconfig.Manifests(machineConfigs, "worker", directory)
+	w.MachineConfigFiles, err = machineconfig.Manifests(machineConfigs, workerRole, directory)
 	if err != nil {
 		return errors.Wrap(err, "failed to create MachineConfig manifests for worker machines")
 	}
 
+	w.MachineConfigPoolFiles, err = machineconfig.PoolManifests(customPools, directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create MachineConfigPool manifests for compute machines")
+	}
+
 	w.MachineSetFiles = make([]*asset.File, len(machineSets))
 	padFormat := fmt.Sprintf("%%0%dd", len(fmt.Sprintf("%d", len(machineSets))))
 	for i, machineSet := range machineSets {
@@ -496,11 +554,13 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 
 // Files returns the files generated by the asset.
 func (w *Worker) Files() []*asset.File {
-	files := make([]*asset.File, 0, 1+len(w.MachineConfigFiles)+len(w.MachineSetFiles))
+	files := make([]*asset.File, 0, 1+len(w.PoolUserDataFiles)+len(w.MachineConfigFiles)+len(w.MachineConfigPoolFiles)+len(w.MachineSetFiles))
 	if w.UserDataFile != nil {
 		files = append(files, w.UserDataFile)
 	}
+	files = append(files, w.PoolUserDataFiles...)
 	files = append(files, w.MachineConfigFiles...)
+	files = append(files, w.MachineConfigPoolFiles...)
 	files = append(files, w.MachineSetFiles...)
 	return files
 }
@@ -516,7 +576,17 @@ func (w *Worker) Load(f asset.FileFetcher) (found bool, err error) {
 	}
 	w.UserDataFile = file
 
-	w.MachineConfigFiles, err = machineconfig.Load(f, "worker", directory)
+	w.PoolUserDataFiles, err = f.FetchByPattern(filepath.Join(directory, poolUserDataFileNamePattern))
+	if err != nil {
+		return true, err
+	}
+
+	w.MachineConfigFiles, err = machineconfig.Load(f, workerRole, directory)
+	if err != nil {
+		return true, err
+	}
+
+	w.MachineConfigPoolFiles, err = machineconfig.LoadPools(f, directory)
 	if err != nil {
 		return true, err
 	}
@@ -575,3 +645,24 @@ func (w *Worker) MachineSets() ([]machineapi.MachineSet, error) {
 
 	return machineSets, nil
 }
+
+// poolPointerIgnition returns the pointer Ignition config of the worker machines, pointed at the machine config
+// server endpoint of a custom compute pool.
+func poolPointerIgnition(workerIgnition []byte, role string) ([]byte, error) {
+	config := &igntypes.Config{}
+	if err := json.Unmarshal(workerIgnition, config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the worker ignition config")
+	}
+	for i, merge := range config.Ignition.Config.Merge {
+		if merge.Source == nil {
+			continue
+		}
+		source, err := url.Parse(*merge.Source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the worker ignition config source %q", *merge.Source)
+		}
+		source.Path = fmt.Sprintf("/config/%s", role)
+		config.Ignition.Config.Merge[i].Source = ignutil.StrToPtr(source.String())
+	}
+	return ignition.Marshal(config)
+}
@@ -1,11 +1,18 @@
 package machines
 
 import (
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
+	"github.com/ghodss/yaml"
+	gcpprovider "github.com/openshift/cluster-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	awsprovider "sigs.k8s.io/cluster-api-provider-aws/pkg/apis/awsprovider/v1beta1"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
@@ -13,6 +20,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/rhcos"
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
 )
 
 func TestWorkerGenerate(t *testing.T) {
@@ -158,6 +166,7 @@ spec:
 						},
 						Compute: []types.MachinePool{
 							{
+								Name:           "worker",
 								Replicas:       pointer.Int64Ptr(1),
 								Hyperthreading: tc.hyperthreading,
 								Platform: types.MachinePoolPlatform{
@@ -213,6 +222,7 @@ func TestComputeIsNotModified(t *testing.T) {
 			},
 			Compute: []types.MachinePool{
 				{
+					Name:           "worker",
 					Replicas:       pointer.Int64Ptr(1),
 					Hyperthreading: types.HyperthreadingDisabled,
 					Platform: types.MachinePoolPlatform{
@@ -249,3 +259,225 @@ func TestComputeIsNotModified(t *testing.T) {
 		t.Fatalf("compute in the install config has been modified")
 	}
 }
+
+func TestWorkerGenerateCustomPools(t *testing.T) {
+	pool := func(name string, hyperthreading types.HyperthreadingMode) types.MachinePool {
+		return types.MachinePool{
+			Name:           name,
+			Replicas:       pointer.Int64Ptr(1),
+			Hyperthreading: hyperthreading,
+			Platform: types.MachinePoolPlatform{
+				AWS: &awstypes.MachinePool{
+					Zones:        []string{"us-east-1a"},
+					InstanceType: "m5.large",
+				},
+			},
+		}
+	}
+	parents := asset.Parents{}
+	parents.Add(
+		&installconfig.ClusterID{
+			UUID:    "test-uuid",
+			InfraID: "test-infra-id",
+		},
+		&installconfig.InstallConfig{
+			Config: &types.InstallConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				SSHKey:     "ssh-rsa: dummy-key",
+				BaseDomain: "test-domain",
+				Platform: types.Platform{
+					AWS: &awstypes.Platform{
+						Region: "us-east-1",
+					},
+				},
+				Compute: []types.MachinePool{
+					pool("worker", types.HyperthreadingEnabled),
+					pool("infra", types.HyperthreadingDisabled),
+					pool("gpu-ready", types.HyperthreadingEnabled),
+				},
+			},
+		},
+		(*rhcos.Image)(pointer.StringPtr("test-image")),
+		&machine.Worker{
+			File: &asset.File{
+				Filename: "worker.ign",
+				Data:     []byte(`{"ignition":{"config":{"merge":[{"source":"https://api-int.test-cluster.test-domain:22623/config/worker"}]},"version":"3.2.0"}}`),
+			},
+		},
+	)
+	worker := &Worker{}
+	if err := worker.Generate(parents); err != nil {
+		t.Fatalf("failed to generate worker machines: %v", err)
+	}
+
+	filenames := func(files []*asset.File) []string {
+		names := make([]string, 0, len(files))
+		for _, f := range files {
+			names = append(names, filepath.Base(f.Filename))
+		}
+		return names
+	}
+	assert.Equal(t, []string{
+		"99_openshift-machineconfig_99-infra-disable-hyperthreading.yaml",
+		"99_openshift-machineconfig_99-worker-ssh.yaml",
+	}, filenames(worker.MachineConfigFiles))
+	assert.Equal(t, []string{
+		"99_openshift-machineconfigpool_infra.yaml",
+		"99_openshift-machineconfigpool_gpu-ready.yaml",
+	}, filenames(worker.MachineConfigPoolFiles))
+	assert.Equal(t, `apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfigPool
+metadata:
+  creationTimestamp: null
+  name: infra
+spec:
+  configuration: {}
+  machineConfigSelector:
+    matchExpressions:
+    - key: machineconfiguration.openshift.io/role
+      operator: In
+      values:
+      - worker
+      - infra
+  nodeSelector:
+    matchLabels:
+      node-role.kubernetes.io/infra: ""
+  paused: false
+status:
+  conditions: null
+  configuration: {}
+  degradedMachineCount: 0
+  machineCount: 0
+  readyMachineCount: 0
+  unavailableMachineCount: 0
+  updatedMachineCount: 0
+`, string(worker.MachineConfigPoolFiles[0].Data))
+
+	assert.Equal(t, []string{
+		"99_openshift-cluster-api_worker-user-data-secret-infra.yaml",
+		"99_openshift-cluster-api_worker-user-data-secret-gpu-ready.yaml",
+	}, filenames(worker.PoolUserDataFiles))
+	secret := &corev1.Secret{}
+	if assert.NoError(t, yaml.Unmarshal(worker.PoolUserDataFiles[0].Data, secret)) {
+		assert.Equal(t, "infra-user-data", secret.Name)
+		assert.Equal(t, `{"ignition":{"config":{"merge":[{"source":"https://api-int.test-cluster.test-domain:22623/config/infra"}]},"version":"3.2.0"}}`, string(secret.Data["userData"]))
+	}
+
+	assert.Len(t, worker.MachineSetFiles, 3)
+	for i, expected := range []struct {
+		name     string
+		role     string
+		nodeRole string
+	}{
+		{name: "test-infra-id-worker-us-east-1a", role: "worker"},
+		{name: "test-infra-id-infra-us-east-1a", role: "infra", nodeRole: "node-role.kubernetes.io/infra"},
+		{name: "test-infra-id-gpu-ready-us-east-1a", role: "gpu-ready", nodeRole: "node-role.kubernetes.io/gpu-ready"},
+	} {
+		machineSet := &machineapi.MachineSet{}
+		if !assert.NoError(t, yaml.Unmarshal(worker.MachineSetFiles[i].Data, machineSet)) {
+			continue
+		}
+		assert.Equal(t, expected.name, machineSet.Name)
+		assert.Equal(t, expected.role, machineSet.Spec.Template.Labels["machine.openshift.io/cluster-api-machine-role"])
+		if expected.nodeRole != "" {
+			assert.Contains(t, machineSet.Spec.Template.Spec.Labels, expected.nodeRole)
+		} else {
+			assert.Empty(t, machineSet.Spec.Template.Spec.Labels)
+		}
+	}
+}
+
+func TestWorkerGenerateCustomPoolInfrastructure(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform types.Platform
+		pool     types.MachinePoolPlatform
+		check    func(t *testing.T, raw []byte)
+	}{
+		{
+			name:     "aws",
+			platform: types.Platform{AWS: &awstypes.Platform{Region: "us-east-1"}},
+			pool:     types.MachinePoolPlatform{AWS: &awstypes.MachinePool{Zones: []string{"us-east-1a"}, InstanceType: "m5.large"}},
+			check: func(t *testing.T, raw []byte) {
+				spec := &awsprovider.AWSMachineProviderConfig{}
+				if !assert.NoError(t, json.Unmarshal(raw, spec)) {
+					return
+				}
+				assert.Equal(t, "test-infra-id-worker-profile", *spec.IAMInstanceProfile.ID)
+				assert.Equal(t, []string{"test-infra-id-worker-sg"}, spec.SecurityGroups[0].Filters[0].Values)
+				assert.Equal(t, []string{"test-infra-id-private-us-east-1a"}, spec.Subnet.Filters[0].Values)
+			},
+		},
+		{
+			name: "gcp",
+			platform: types.Platform{GCP: &gcptypes.Platform{
+				ProjectID:          "test-project",
+				Region:             "us-central1",
+				Network:            "test-network",
+				ControlPlaneSubnet: "test-control-plane-subnet",
+				ComputeSubnet:      "test-compute-subnet",
+			}},
+			pool: types.MachinePoolPlatform{GCP: &gcptypes.MachinePool{Zones: []string{"us-central1-a"}, InstanceType: "n1-standard-4"}},
+			check: func(t *testing.T, raw []byte) {
+				spec := &gcpprovider.GCPMachineProviderSpec{}
+				if !assert.NoError(t, json.Unmarshal(raw, spec)) {
+					return
+				}
+				assert.Equal(t, "test-network", spec.NetworkInterfaces[0].Network)
+				assert.Equal(t, "test-compute-subnet", spec.NetworkInterfaces[0].Subnetwork)
+				assert.Equal(t, "test-infra-id-w@test-project.iam.gserviceaccount.com", spec.ServiceAccounts[0].Email)
+				assert.Equal(t, []string{"test-infra-id-worker"}, spec.Tags)
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(
+				&installconfig.ClusterID{
+					UUID:    "test-uuid",
+					InfraID: "test-infra-id",
+				},
+				&installconfig.InstallConfig{
+					Config: &types.InstallConfig{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "test-cluster",
+						},
+						BaseDomain: "test-domain",
+						Platform:   tc.platform,
+						Compute: []types.MachinePool{{
+							Name:           "infra",
+							Replicas:       pointer.Int64Ptr(1),
+							Hyperthreading: types.HyperthreadingEnabled,
+							Platform:       tc.pool,
+						}},
+					},
+				},
+				(*rhcos.Image)(pointer.StringPtr("test-image")),
+				&machine.Worker{
+					File: &asset.File{
+						Filename: "worker.ign",
+						Data:     []byte(`{"ignition":{"config":{"merge":[{"source":"https://api-int.test-cluster.test-domain:22623/config/worker"}]},"version":"3.2.0"}}`),
+					},
+				},
+			)
+			worker := &Worker{}
+			if err := worker.Generate(parents); err != nil {
+				t.Fatalf("failed to generate worker machines: %v", err)
+			}
+
+			if !assert.Len(t, worker.MachineSetFiles, 1) {
+				return
+			}
+			machineSet := &machineapi.MachineSet{}
+			if !assert.NoError(t, yaml.Unmarshal(worker.MachineSetFiles[0].Data, machineSet)) {
+				return
+			}
+			assert.Equal(t, "infra", machineSet.Spec.Template.Labels["machine.openshift.io/cluster-api-machine-role"])
+			assert.Equal(t, "infra", machineSet.Spec.Template.Labels["machine.openshift.io/cluster-api-machine-type"])
+			tc.check(t, machineSet.Spec.Template.Spec.ProviderSpec.Value.Raw)
+		})
+	}
+}
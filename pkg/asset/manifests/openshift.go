@@ -184,12 +184,20 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 			},
 		}
 	case vspheretypes.Name:
+		vcenters := []VSphereCredsSecretData{{
+			VCenter:              installConfig.Config.VSphere.VCenter,
+			Base64encodeUsername: base64.StdEncoding.EncodeToString([]byte(installConfig.Config.VSphere.Username)),
+			Base64encodePassword: base64.StdEncoding.EncodeToString([]byte(installConfig.Config.VSphere.Password)),
+		}}
+		for _, vcenter := range installConfig.Config.VSphere.VCenters {
+			vcenters = append(vcenters, VSphereCredsSecretData{
+				VCenter:              vcenter.Server,
+				Base64encodeUsername: base64.StdEncoding.EncodeToString([]byte(vcenter.Username)),
+				Base64encodePassword: base64.StdEncoding.EncodeToString([]byte(vcenter.Password)),
+			})
+		}
 		cloudCreds = cloudCredsSecretData{
-			VSphere: &VSphereCredsSecretData{
-				VCenter:              installConfig.Config.VSphere.VCenter,
-				Base64encodeUsername: base64.StdEncoding.EncodeToString([]byte(installConfig.Config.VSphere.Username)),
-				Base64encodePassword: base64.StdEncoding.EncodeToString([]byte(installConfig.Config.VSphere.Password)),
-			},
+			VSphere: vcenters,
 		}
 	case ovirttypes.Name:
 		conf, err := ovirt.NewConfig()
@@ -16,6 +16,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/tls"
 	"github.com/openshift/installer/pkg/types"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
 
 const (
@@ -226,6 +227,12 @@ func redactedInstallConfig(config types.InstallConfig) ([]byte, error) {
 		p := *config.Platform.VSphere
 		p.Username = ""
 		p.Password = ""
+		if len(p.VCenters) > 0 {
+			p.VCenters = make([]vspheretypes.VCenter, len(p.VCenters))
+			for i, vcenter := range config.Platform.VSphere.VCenters {
+				p.VCenters[i] = vspheretypes.VCenter{Server: vcenter.Server}
+			}
+		}
 		config.Platform.VSphere = &p
 	}
 	return yaml.Marshal(config)
@@ -53,6 +53,11 @@ func TestRedactedInstallConfig(t *testing.T) {
 					Password:         "test-pass-1",
 					Datacenter:       "test-datacenter",
 					DefaultDatastore: "test-datastore",
+					VCenters: []vspheretypes.VCenter{{
+						Server:   "test-server-2",
+						Username: "test-user-2",
+						Password: "test-pass-2",
+					}},
 				},
 			},
 			PullSecret: "test-pull-secret",
@@ -89,6 +94,10 @@ platform:
     password: ""
     username: ""
     vCenter: test-server-1
+    vcenters:
+    - password: ""
+      server: test-server-2
+      username: ""
 pullSecret: ""
 sshKey: test-ssh-key
 `
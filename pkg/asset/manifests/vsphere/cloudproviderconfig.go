@@ -3,6 +3,9 @@ package vsphere
 import (
 	"bytes"
 	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
@@ -33,7 +36,37 @@ func CloudProviderConfig(folderPath string, p *vspheretypes.Platform) (string, e
 	fmt.Fprintln(buf, "")
 
 	fmt.Fprintf(buf, "[VirtualCenter %q]\n", p.VCenter)
-	printIfNotEmpty(buf, "datacenters", p.Datacenter)
+	printIfNotEmpty(buf, "datacenters", strings.Join(datacenters(p, p.VCenter, p.Datacenter), ","))
+
+	if len(p.FailureDomains) == 0 {
+		return buf.String(), nil
+	}
+
+	for _, vcenter := range p.VCenters {
+		fmt.Fprintln(buf, "")
+		fmt.Fprintf(buf, "[VirtualCenter %q]\n", vcenter.Server)
+		printIfNotEmpty(buf, "datacenters", strings.Join(datacenters(p, vcenter.Server), ","))
+	}
+
+	// The regions and the zones of the nodes are the tags of their
+	// datacenters and clusters in these tag categories.
+	fmt.Fprintln(buf, "")
+	fmt.Fprintln(buf, "[Labels]")
+	printIfNotEmpty(buf, "region", vspheretypes.RegionTagCategory)
+	printIfNotEmpty(buf, "zone", vspheretypes.ZoneTagCategory)
 
 	return buf.String(), nil
 }
+
+// datacenters returns the datacenters of the failure domains of the vCenter
+// server, after the given ones.
+func datacenters(p *vspheretypes.Platform, server string, datacenters ...string) []string {
+	seen := sets.NewString(datacenters...)
+	for _, fd := range p.FailureDomains {
+		if fd.Server == server && !seen.Has(fd.Topology.Datacenter) {
+			datacenters = append(datacenters, fd.Topology.Datacenter)
+			seen.Insert(fd.Topology.Datacenter)
+		}
+	}
+	return datacenters
+}
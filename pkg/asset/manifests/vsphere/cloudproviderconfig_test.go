@@ -36,3 +36,66 @@ datacenters = "test-datacenter"
 	assert.NoError(t, err, "failed to create cloud provider config")
 	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
 }
+
+func TestCloudProviderConfigFailureDomains(t *testing.T) {
+	platform := &vspheretypes.Platform{
+		VCenter:          "test-name",
+		Username:         "test-username",
+		Password:         "test-password",
+		Datacenter:       "test-datacenter",
+		DefaultDatastore: "test-datastore",
+		VCenters: []vspheretypes.VCenter{{
+			Server:   "test-name-b",
+			Username: "test-username",
+			Password: "test-password",
+		}},
+		FailureDomains: []vspheretypes.FailureDomain{
+			{
+				Name:     "a",
+				Region:   "us-east",
+				Zone:     "us-east-1a",
+				Server:   "test-name",
+				Topology: vspheretypes.Topology{Datacenter: "test-datacenter"},
+			},
+			{
+				Name:     "b",
+				Region:   "us-east",
+				Zone:     "us-east-1b",
+				Server:   "test-name",
+				Topology: vspheretypes.Topology{Datacenter: "test-datacenter-b"},
+			},
+			{
+				Name:     "c",
+				Region:   "us-west",
+				Zone:     "us-west-1a",
+				Server:   "test-name-b",
+				Topology: vspheretypes.Topology{Datacenter: "test-datacenter-c"},
+			},
+		},
+	}
+	expectedConfig := `[Global]
+secret-name = "vsphere-creds"
+secret-namespace = "kube-system"
+insecure-flag = "1"
+
+[Workspace]
+server = "test-name"
+datacenter = "test-datacenter"
+default-datastore = "test-datastore"
+folder = "/test-datacenter/vm/clusterID"
+
+[VirtualCenter "test-name"]
+datacenters = "test-datacenter,test-datacenter-b"
+
+[VirtualCenter "test-name-b"]
+datacenters = "test-datacenter-c"
+
+[Labels]
+region = "openshift-region"
+zone = "openshift-zone"
+`
+	folderPath := fmt.Sprintf("/%s/vm/%s", "test-datacenter", "clusterID")
+	actualConfig, err := CloudProviderConfig(folderPath, platform)
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
+}
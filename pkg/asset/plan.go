@@ -0,0 +1,33 @@
+package asset
+
+// PlanAction describes what a Store would do to obtain an asset.
+type PlanAction string
+
+const (
+	// PlanGenerate means the asset has no existing source and would be
+	// generated.
+	PlanGenerate PlanAction = "generate"
+	// PlanRegenerate means the asset would be generated again because one
+	// of its parents is dirty, discarding any existing copy.
+	PlanRegenerate PlanAction = "regenerate"
+	// PlanLoadFromDisk means the asset would be taken from the target
+	// directory, making its dependents dirty.
+	PlanLoadFromDisk PlanAction = "load-from-disk"
+	// PlanLoadFromState means the asset would be reused from the state file.
+	PlanLoadFromState PlanAction = "load-from-state"
+)
+
+// PlannedAsset is the planned action for a single asset.
+type PlannedAsset struct {
+	// Name is the human-friendly name of the asset.
+	Name string `json:"name"`
+	// Type is the Go type of the asset, as used for the state file key.
+	Type string `json:"type"`
+	// Action is what the store would do to obtain the asset.
+	Action PlanAction `json:"action"`
+	// OnDisk is true if the asset is present in the target directory.
+	OnDisk bool `json:"onDisk,omitempty"`
+	// DirtyParents are the direct dependencies that caused the asset to
+	// be regenerated.
+	DirtyParents []string `json:"dirtyParents,omitempty"`
+}
@@ -0,0 +1,170 @@
+package azure
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	azsku "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/compute/mgmt/compute"
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	azureprovider "sigs.k8s.io/cluster-api-provider-azure/pkg/apis/azureprovider/v1beta1"
+
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types"
+	typesazure "github.com/openshift/installer/pkg/types/azure"
+)
+
+// VirtualMachineSkuGetter returns the resource SKU of a virtual machine size
+// in a region using the Azure API.
+type VirtualMachineSkuGetter interface {
+	GetVirtualMachineSku(ctx context.Context, name, region string) (*azsku.ResourceSku, error)
+}
+
+// Constraints returns a list of quota constraints based on the InstallConfig.
+// These constraints can be used to check if there is enough quota for creating a cluster
+// for the install config.
+func Constraints(client VirtualMachineSkuGetter, config *types.InstallConfig, controlPlanes []machineapi.Machine, computes []machineapi.MachineSet) []quota.Constraint {
+	ctrplConfigs := make([]*azureprovider.AzureMachineProviderSpec, len(controlPlanes))
+	for i, m := range controlPlanes {
+		ctrplConfigs[i] = m.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec)
+	}
+	computeReplicas := make([]int64, len(computes))
+	computeConfigs := make([]*azureprovider.AzureMachineProviderSpec, len(computes))
+	for i, w := range computes {
+		computeReplicas[i] = int64(*w.Spec.Replicas)
+		computeConfigs[i] = w.Spec.Template.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec)
+	}
+
+	var ret []quota.Constraint
+	for _, gen := range []constraintGenerator{
+		network(config),
+		controlPlane(client, config, ctrplConfigs),
+		compute(client, config, computeReplicas, computeConfigs),
+	} {
+		ret = append(ret, gen()...)
+	}
+	return aggregate(ret)
+}
+
+func aggregate(quotas []quota.Constraint) []quota.Constraint {
+	sort.SliceStable(quotas, func(i, j int) bool {
+		return quotas[i].Name < quotas[j].Name
+	})
+
+	i := 0
+	for j := 1; j < len(quotas); j++ {
+		if quotas[i].Name == quotas[j].Name && quotas[i].Region == quotas[j].Region {
+			quotas[i].Count += quotas[j].Count
+		} else {
+			i++
+			if i != j {
+				quotas[i] = quotas[j]
+			}
+		}
+	}
+	return quotas[:i+1]
+}
+
+// constraintGenerator generates a list of constraints.
+type constraintGenerator func() []quota.Constraint
+
+func network(config *types.InstallConfig) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		region := config.Platform.Azure.Region
+		ret := []quota.Constraint{{
+			Name:   "network/LoadBalancers", // public and internal
+			Region: region,
+			Count:  2,
+		}}
+
+		families := int64(1)
+		for _, network := range config.MachineNetwork {
+			if network.CIDR.IP.To4() == nil {
+				families = 2
+			}
+		}
+		private := config.Publish == types.InternalPublishingStrategy
+		if !private || config.Platform.Azure.OutboundType != typesazure.UserDefinedRoutingOutboundType {
+			ret = append(ret, quota.Constraint{
+				Name:   "network/PublicIPAddresses", // public load balancer, per IP family
+				Region: region,
+				Count:  families,
+			})
+		}
+		if !private {
+			ret = append(ret, quota.Constraint{
+				Name:   "network/PublicIPAddresses", // bootstrap, per IP family
+				Region: region,
+				Count:  families,
+			})
+		}
+		return ret
+	}
+}
+
+func controlPlane(client VirtualMachineSkuGetter, config *types.InstallConfig, machines []*azureprovider.AzureMachineProviderSpec) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		var ret []quota.Constraint
+		for _, m := range machines {
+			ret = append(ret, vmSizeToQuota(client, config.Platform.Azure.Region, m.VMSize, 1)...)
+		}
+		// The bootstrap machine has the size of the control plane machines.
+		if len(machines) > 0 {
+			ret = append(ret, vmSizeToQuota(client, config.Platform.Azure.Region, machines[0].VMSize, 1)...)
+		}
+		return ret
+	}
+}
+
+func compute(client VirtualMachineSkuGetter, config *types.InstallConfig, replicas []int64, machines []*azureprovider.AzureMachineProviderSpec) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		var ret []quota.Constraint
+		for idx, m := range machines {
+			ret = append(ret, vmSizeToQuota(client, config.Platform.Azure.Region, m.VMSize, replicas[idx])...)
+		}
+		return ret
+	}
+}
+
+// vmSizeToQuota returns the constraints of count virtual machines of a size:
+// the cores of their VM family, the total regional cores and the number of
+// virtual machines.
+func vmSizeToQuota(client VirtualMachineSkuGetter, region string, vmSize string, count int64) []quota.Constraint {
+	ret := []quota.Constraint{{
+		Name:   "compute/virtualMachines",
+		Region: region,
+		Count:  count,
+	}}
+
+	sku, err := client.GetVirtualMachineSku(context.TODO(), vmSize, region)
+	if err != nil || sku == nil {
+		return append(ret, quota.Constraint{Name: "compute/cores", Region: region, Count: guessVMCores(vmSize) * count})
+	}
+	var cores int64
+	if sku.Capabilities != nil {
+		for _, capability := range *sku.Capabilities {
+			if capability.Name != nil && capability.Value != nil && strings.EqualFold(*capability.Name, "vCPUs") {
+				cores, _ = strconv.ParseInt(*capability.Value, 10, 64)
+			}
+		}
+	}
+	ret = append(ret, quota.Constraint{Name: "compute/cores", Region: region, Count: cores * count})
+	if sku.Family != nil {
+		ret = append(ret, quota.Constraint{Name: "compute/" + *sku.Family, Region: region, Count: cores * count})
+	}
+	return ret
+}
+
+var vmSizeCoresRegex = regexp.MustCompile(`^Standard_[A-Za-z]+(\d+)`)
+
+// the guess is based on https://docs.microsoft.com/en-us/azure/virtual-machines/vm-naming-conventions
+func guessVMCores(vmSize string) int64 {
+	if submatch := vmSizeCoresRegex.FindStringSubmatch(vmSize); submatch != nil {
+		if c, err := strconv.ParseInt(submatch[1], 10, 64); err == nil {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,193 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	azsku "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/compute/mgmt/compute"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types"
+	typesazure "github.com/openshift/installer/pkg/types/azure"
+)
+
+func Test_guessVMCores(t *testing.T) {
+	cases := []struct {
+		vmSize   string
+		expected int64
+	}{{
+		vmSize:   "Standard_D4s_v3",
+		expected: 4,
+	}, {
+		vmSize:   "Standard_D8s_v3",
+		expected: 8,
+	}, {
+		vmSize:   "Standard_DS13_v2",
+		expected: 13,
+	}, {
+		vmSize:   "Standard_E16as_v4",
+		expected: 16,
+	}, {
+		vmSize:   "Standard_M128ms",
+		expected: 128,
+	}, {
+		vmSize:   "Basic_A1",
+		expected: 0,
+	}}
+	for idx, test := range cases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			got := guessVMCores(test.vmSize)
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func Test_vmSizeToQuota(t *testing.T) {
+	fake := newFakeVirtualMachineSkuGetter([]azsku.ResourceSku{
+		newSku("centralus", "Standard_D4s_v3", "standardDSv3Family", "4"),
+		newSku("centralus", "Standard_D8s_v3", "standardDSv3Family", "8"),
+		newSku("eastus", "Standard_D8s_v3", "standardDSv3Family", "8"),
+	})
+
+	tests := []struct {
+		region   string
+		vmSize   string
+		count    int64
+		expected []quota.Constraint
+	}{{
+		region: "centralus",
+		vmSize: "Standard_D4s_v3",
+		count:  1,
+		expected: []quota.Constraint{
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 1},
+			{Name: "compute/cores", Region: "centralus", Count: 4},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 4},
+		},
+	}, {
+		region: "centralus",
+		vmSize: "Standard_D8s_v3",
+		count:  3,
+		expected: []quota.Constraint{
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 3},
+			{Name: "compute/cores", Region: "centralus", Count: 24},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 24},
+		},
+	}, {
+		region: "westus",
+		vmSize: "Standard_D8s_v3",
+		count:  2,
+		expected: []quota.Constraint{
+			{Name: "compute/virtualMachines", Region: "westus", Count: 2},
+			{Name: "compute/cores", Region: "westus", Count: 16},
+		},
+	}}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			got := vmSizeToQuota(fake, test.region, test.vmSize, test.count)
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func Test_network(t *testing.T) {
+	ipv4 := *ipnet.MustParseCIDR("10.0.0.0/16")
+	ipv6 := *ipnet.MustParseCIDR("fd00::/48")
+
+	tests := []struct {
+		name         string
+		publish      types.PublishingStrategy
+		outboundType typesazure.OutboundType
+		networks     []ipnet.IPNet
+		expected     []quota.Constraint
+	}{{
+		name:     "external",
+		publish:  types.ExternalPublishingStrategy,
+		networks: []ipnet.IPNet{ipv4},
+		expected: []quota.Constraint{
+			{Name: "network/LoadBalancers", Region: "centralus", Count: 2},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 2},
+		},
+	}, {
+		name:     "external dual-stack",
+		publish:  types.ExternalPublishingStrategy,
+		networks: []ipnet.IPNet{ipv4, ipv6},
+		expected: []quota.Constraint{
+			{Name: "network/LoadBalancers", Region: "centralus", Count: 2},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 4},
+		},
+	}, {
+		name:         "internal",
+		publish:      types.InternalPublishingStrategy,
+		outboundType: typesazure.LoadbalancerOutboundType,
+		networks:     []ipnet.IPNet{ipv4},
+		expected: []quota.Constraint{
+			{Name: "network/LoadBalancers", Region: "centralus", Count: 2},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 1},
+		},
+	}, {
+		name:         "internal with user-defined routing",
+		publish:      types.InternalPublishingStrategy,
+		outboundType: typesazure.UserDefinedRoutingOutboundType,
+		networks:     []ipnet.IPNet{ipv4},
+		expected: []quota.Constraint{
+			{Name: "network/LoadBalancers", Region: "centralus", Count: 2},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				Publish:    test.publish,
+				Networking: &types.Networking{},
+				Platform: types.Platform{Azure: &typesazure.Platform{
+					Region:       "centralus",
+					OutboundType: test.outboundType,
+				}},
+			}
+			for _, n := range test.networks {
+				config.MachineNetwork = append(config.MachineNetwork, types.MachineNetworkEntry{CIDR: n})
+			}
+			got := aggregate(network(config)())
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func newSku(region, name, family, vcpus string) azsku.ResourceSku {
+	return azsku.ResourceSku{
+		Name:         pointer.StringPtr(name),
+		Family:       pointer.StringPtr(family),
+		Locations:    &[]string{region},
+		Capabilities: &[]azsku.ResourceSkuCapabilities{{Name: pointer.StringPtr("vCPUs"), Value: pointer.StringPtr(vcpus)}},
+	}
+}
+
+type fakeVirtualMachineSkuGetter struct {
+	knownSkus map[string]*azsku.ResourceSku
+}
+
+func newFakeVirtualMachineSkuGetter(skus []azsku.ResourceSku) *fakeVirtualMachineSkuGetter {
+	fake := &fakeVirtualMachineSkuGetter{
+		knownSkus: map[string]*azsku.ResourceSku{},
+	}
+
+	for idx, sku := range skus {
+		fake.knownSkus[fmt.Sprintf("%s__%s", (*sku.Locations)[0], *sku.Name)] = &skus[idx]
+	}
+
+	return fake
+}
+
+func (fake *fakeVirtualMachineSkuGetter) GetVirtualMachineSku(ctx context.Context, name, region string) (*azsku.ResourceSku, error) {
+	sku, ok := fake.knownSkus[fmt.Sprintf("%s__%s", region, name)]
+	if !ok {
+		return nil, errors.New("unknown")
+	}
+	return sku, nil
+}
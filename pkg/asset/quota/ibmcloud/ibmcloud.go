@@ -0,0 +1,122 @@
+package ibmcloud
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	ibmcloudprovider "github.com/openshift/cluster-api-provider-ibmcloud/pkg/apis/ibmcloudprovider/v1beta1"
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+
+	"github.com/openshift/installer/pkg/quota"
+	quotaibmcloud "github.com/openshift/installer/pkg/quota/ibmcloud"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Constraints returns a list of quota constraints based on the InstallConfig.
+// These constraints can be used to check if there is enough quota for creating a cluster
+// for the install config.
+func Constraints(config *types.InstallConfig, controlPlanes []machineapi.Machine, computes []machineapi.MachineSet) []quota.Constraint {
+	ctrplConfigs := make([]*ibmcloudprovider.IBMCloudMachineProviderSpec, len(controlPlanes))
+	for i, m := range controlPlanes {
+		ctrplConfigs[i] = m.Spec.ProviderSpec.Value.Object.(*ibmcloudprovider.IBMCloudMachineProviderSpec)
+	}
+	computeReplicas := make([]int64, len(computes))
+	computeConfigs := make([]*ibmcloudprovider.IBMCloudMachineProviderSpec, len(computes))
+	for i, w := range computes {
+		computeReplicas[i] = int64(*w.Spec.Replicas)
+		computeConfigs[i] = w.Spec.Template.Spec.ProviderSpec.Value.Object.(*ibmcloudprovider.IBMCloudMachineProviderSpec)
+	}
+
+	var ret []quota.Constraint
+	for _, gen := range []constraintGenerator{
+		network(config),
+		controlPlane(config, ctrplConfigs),
+		compute(config, computeReplicas, computeConfigs),
+	} {
+		ret = append(ret, gen()...)
+	}
+	return aggregate(ret)
+}
+
+func aggregate(quotas []quota.Constraint) []quota.Constraint {
+	sort.SliceStable(quotas, func(i, j int) bool {
+		return quotas[i].Name < quotas[j].Name
+	})
+
+	i := 0
+	for j := 1; j < len(quotas); j++ {
+		if quotas[i].Name == quotas[j].Name && quotas[i].Region == quotas[j].Region {
+			quotas[i].Count += quotas[j].Count
+		} else {
+			i++
+			if i != j {
+				quotas[i] = quotas[j]
+			}
+		}
+	}
+	return quotas[:i+1]
+}
+
+// constraintGenerator generates a list of constraints.
+type constraintGenerator func() []quota.Constraint
+
+func network(config *types.InstallConfig) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		region := config.Platform.IBMCloud.Region
+		ret := []quota.Constraint{{
+			Name:   quotaibmcloud.QuotaVPCs,
+			Region: region,
+			Count:  1,
+		}, {
+			Name:   quotaibmcloud.QuotaLoadBalancers, // private
+			Region: region,
+			Count:  1,
+		}}
+		if config.Publish != types.InternalPublishingStrategy {
+			ret = append(ret, quota.Constraint{
+				Name:   quotaibmcloud.QuotaLoadBalancers, // public
+				Region: region,
+				Count:  1,
+			})
+		}
+		return ret
+	}
+}
+
+func controlPlane(config *types.InstallConfig, machines []*ibmcloudprovider.IBMCloudMachineProviderSpec) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		var ret []quota.Constraint
+		for _, m := range machines {
+			ret = append(ret, profileToQuota(config.Platform.IBMCloud.Region, m.Profile, 1))
+		}
+		// The bootstrap machine has the profile of the control plane machines.
+		if len(machines) > 0 {
+			ret = append(ret, profileToQuota(config.Platform.IBMCloud.Region, machines[0].Profile, 1))
+		}
+		return ret
+	}
+}
+
+func compute(config *types.InstallConfig, replicas []int64, machines []*ibmcloudprovider.IBMCloudMachineProviderSpec) func() []quota.Constraint {
+	return func() []quota.Constraint {
+		var ret []quota.Constraint
+		for idx, m := range machines {
+			ret = append(ret, profileToQuota(config.Platform.IBMCloud.Region, m.Profile, replicas[idx]))
+		}
+		return ret
+	}
+}
+
+// profileVCPURegex matches the vCPUs of profile names like "bx2-4x16", for
+// 4 vCPUs and 16 GiB of memory.
+var profileVCPURegex = regexp.MustCompile(`^[a-z0-9]+-(\d+)x\d+`)
+
+// the profile names are described at https://cloud.ibm.com/docs/vpc?topic=vpc-profiles
+func profileToQuota(region string, profile string, count int64) quota.Constraint {
+	var vcpus int64
+	if submatch := profileVCPURegex.FindStringSubmatch(profile); submatch != nil {
+		vcpus, _ = strconv.ParseInt(submatch[1], 10, 64)
+	}
+	return quota.Constraint{Name: quotaibmcloud.QuotaVCPU, Region: region, Count: vcpus * count}
+}
@@ -0,0 +1,75 @@
+package ibmcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types"
+	typesibmcloud "github.com/openshift/installer/pkg/types/ibmcloud"
+)
+
+func Test_profileToQuota(t *testing.T) {
+	cases := []struct {
+		profile  string
+		count    int64
+		expected quota.Constraint
+	}{{
+		profile:  "bx2-4x16",
+		count:    1,
+		expected: quota.Constraint{Name: "vpc/vcpu", Region: "us-south", Count: 4},
+	}, {
+		profile:  "bx2-16x64",
+		count:    3,
+		expected: quota.Constraint{Name: "vpc/vcpu", Region: "us-south", Count: 48},
+	}, {
+		profile:  "gx2-8x64x1v100",
+		count:    2,
+		expected: quota.Constraint{Name: "vpc/vcpu", Region: "us-south", Count: 16},
+	}, {
+		profile:  "bx2d-metal-96x384",
+		count:    1,
+		expected: quota.Constraint{Name: "vpc/vcpu", Region: "us-south", Count: 0},
+	}, {
+		profile:  "unknown",
+		count:    1,
+		expected: quota.Constraint{Name: "vpc/vcpu", Region: "us-south", Count: 0},
+	}}
+	for idx, test := range cases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			got := profileToQuota("us-south", test.profile, test.count)
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func Test_network(t *testing.T) {
+	cases := []struct {
+		publish  types.PublishingStrategy
+		expected []quota.Constraint
+	}{{
+		publish: types.ExternalPublishingStrategy,
+		expected: []quota.Constraint{
+			{Name: "vpc/load-balancers", Region: "us-south", Count: 2},
+			{Name: "vpc/vpcs", Region: "us-south", Count: 1},
+		},
+	}, {
+		publish: types.InternalPublishingStrategy,
+		expected: []quota.Constraint{
+			{Name: "vpc/load-balancers", Region: "us-south", Count: 1},
+			{Name: "vpc/vpcs", Region: "us-south", Count: 1},
+		},
+	}}
+	for _, test := range cases {
+		t.Run(string(test.publish), func(t *testing.T) {
+			config := &types.InstallConfig{
+				Publish:  test.publish,
+				Platform: types.Platform{IBMCloud: &typesibmcloud.Platform{Region: "us-south"}},
+			}
+			got := aggregate(network(config)())
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
@@ -15,14 +16,18 @@ import (
 	openstackvalidation "github.com/openshift/installer/pkg/asset/installconfig/openstack/validation"
 	"github.com/openshift/installer/pkg/asset/machines"
 	"github.com/openshift/installer/pkg/asset/quota/aws"
+	"github.com/openshift/installer/pkg/asset/quota/azure"
 	"github.com/openshift/installer/pkg/asset/quota/gcp"
+	ibmcloudquota "github.com/openshift/installer/pkg/asset/quota/ibmcloud"
 	"github.com/openshift/installer/pkg/asset/quota/openstack"
 	"github.com/openshift/installer/pkg/diagnostics"
 	"github.com/openshift/installer/pkg/quota"
 	quotaaws "github.com/openshift/installer/pkg/quota/aws"
+	quotaazure "github.com/openshift/installer/pkg/quota/azure"
 	quotagcp "github.com/openshift/installer/pkg/quota/gcp"
+	quotaibmcloud "github.com/openshift/installer/pkg/quota/ibmcloud"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
-	"github.com/openshift/installer/pkg/types/azure"
+	typesazure "github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	typesgcp "github.com/openshift/installer/pkg/types/gcp"
 	"github.com/openshift/installer/pkg/types/ibmcloud"
@@ -67,86 +72,149 @@ func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
 		return err
 	}
 
+	reports, err := Reports(context.TODO(), ic, masters, workers)
+	if err != nil {
+		return err
+	}
+	if Failing(reports) {
+		err := summarizeFailingReport(reports)
+		if err != nil && ic.Config.Platform.Name() == ibmcloud.Name {
+			logrus.Warnf("%v. These are the default quotas of IBM Cloud accounts, which cannot be read from the API, so ignore this if the quotas of your account were increased.", err)
+			return nil
+		}
+		return err
+	}
+	summarizeReport(reports)
+	return nil
+}
+
+// Reports checks the quota constraints of the cluster of the install config
+// against the quotas of the platform, and returns a report for each of the
+// constraints. It returns no reports when the platform has no quotas to
+// check, or when they cannot be loaded with the credentials of the user.
+func Reports(ctx context.Context, ic *installconfig.InstallConfig, masters []machineapi.Machine, workers []machineapi.MachineSet) ([]quota.ConstraintReport, error) {
+	var q []quota.Quota
+	var constraints []quota.Constraint
 	platform := ic.Config.Platform.Name()
 	switch platform {
 	case typesaws.Name:
 		if !quotaaws.SupportedRegions.Has(ic.AWS.Region) {
 			logrus.Debugf("%s does not support API for checking quotas, therefore skipping.", ic.AWS.Region)
-			return nil
+			return nil, nil
 		}
 		services := []string{"ec2", "vpc"}
-		session, err := ic.AWS.Session(context.TODO())
+		session, err := ic.AWS.Session(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to load AWS session")
+			return nil, errors.Wrap(err, "failed to load AWS session")
 		}
-		q, err := quotaaws.Load(context.TODO(), session, ic.AWS.Region, services...)
+		q, err = quotaaws.Load(ctx, session, ic.AWS.Region, services...)
 		if quotaaws.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `servicequotas:ListAWSDefaultServiceQuotas` permission available to the user.", err)
-			return nil
+			return nil, nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
+			return nil, errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
 		}
-		instanceTypes, err := aws.InstanceTypes(context.TODO(), session, ic.AWS.Region)
+		instanceTypes, err := aws.InstanceTypes(ctx, session, ic.AWS.Region)
 		if quotaaws.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch instance types and therefore will skip checking Quotas: %v, make sure you have `ec2:DescribeInstanceTypes` permission available to the user.", err)
-			return nil
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load instance types for %s", ic.AWS.Region)
+		}
+		constraints = aws.Constraints(ic.Config, masters, workers, instanceTypes)
+	case typesazure.Name:
+		session, err := ic.Azure.Session()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load Azure session")
+		}
+		q, err = quotaazure.Load(ctx, quotaazure.NewClient(session), ic.Config.Platform.Azure.Region)
+		if quotaazure.IsUnauthorized(err) {
+			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `Microsoft.Compute/locations/usages/read` and `Microsoft.Network/locations/usages/read` permissions available to the user.", err)
+			return nil, nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to load instance types for %s", ic.AWS.Region)
+			return nil, errors.Wrap(err, "failed to load Quota for services: compute, network")
 		}
-		reports, err := quota.Check(q, aws.Constraints(ic.Config, masters, workers, instanceTypes))
+		client, err := ic.Azure.Client()
 		if err != nil {
-			return summarizeFailingReport(reports)
+			return nil, errors.Wrap(err, "failed to create client for quota constraints")
 		}
-		summarizeReport(reports)
+		constraints = azure.Constraints(client, ic.Config, masters, workers)
 	case typesgcp.Name:
 		services := []string{"compute.googleapis.com", "iam.googleapis.com"}
-		q, err := quotagcp.Load(context.TODO(), ic.Config.Platform.GCP.ProjectID, services...)
+		var err error
+		q, err = quotagcp.Load(ctx, ic.Config.Platform.GCP.ProjectID, services...)
 		if quotagcp.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `roles/servicemanagement.quotaViewer` assigned to the user.", err)
-			return nil
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
 		}
+		session, err := configgcp.GetSession(ctx)
 		if err != nil {
-			return errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
+			return nil, errors.Wrap(err, "failed to load GCP session")
 		}
-		session, err := configgcp.GetSession(context.TODO())
+		client, err := gcp.NewClient(ctx, session, ic.Config.Platform.GCP.ProjectID)
 		if err != nil {
-			return errors.Wrap(err, "failed to load GCP session")
+			return nil, errors.Wrap(err, "failed to create client for quota constraints")
 		}
-		client, err := gcp.NewClient(context.TODO(), session, ic.Config.Platform.GCP.ProjectID)
+		constraints = gcp.Constraints(client, ic.Config, masters, workers)
+	case ibmcloud.Name:
+		icClient, err := ic.IBMCloud.Client()
 		if err != nil {
-			return errors.Wrap(err, "failed to create client for quota constraints")
+			return nil, errors.Wrap(err, "failed to create IBM Cloud client")
 		}
-		reports, err := quota.Check(q, gcp.Constraints(client, ic.Config, masters, workers))
+		client, err := quotaibmcloud.NewClient(icClient.Authenticator, ic.Config.Platform.IBMCloud.Region)
 		if err != nil {
-			return summarizeFailingReport(reports)
+			return nil, err
 		}
-		summarizeReport(reports)
+		q, err = quotaibmcloud.Load(ctx, client, ic.Config.Platform.IBMCloud.Region)
+		if quotaibmcloud.IsUnauthorized(err) {
+			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have the Viewer role on the VPC Infrastructure Services available to the user.", err)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load Quota for services: vpc")
+		}
+		constraints = ibmcloudquota.Constraints(ic.Config, masters, workers)
 	case typesopenstack.Name:
 		if skip := os.Getenv("OPENSHIFT_INSTALL_SKIP_PREFLIGHT_VALIDATIONS"); skip == "1" {
 			logrus.Warnf("OVERRIDE: pre-flight validation disabled.")
-			return nil
+			return nil, nil
 		}
 		ci, err := openstackvalidation.GetCloudInfo(ic.Config)
 		if err != nil {
-			return errors.Wrap(err, "failed to get cloud info")
+			return nil, errors.Wrap(err, "failed to get cloud info")
 		}
 		if ci == nil {
 			logrus.Warnf("Empty OpenStack cloud info and therefore will skip checking quota validation.")
-			return nil
-		}
-		reports, err := quota.Check(ci.Quotas, openstack.Constraints(ci, masters, workers, ic.Config.NetworkType))
-		if err != nil {
-			return summarizeFailingReport(reports)
+			return nil, nil
 		}
-		summarizeReport(reports)
-	case azure.Name, baremetal.Name, ibmcloud.Name, libvirt.Name, none.Name, ovirt.Name, vsphere.Name, kubevirt.Name:
+		q = ci.Quotas
+		constraints = openstack.Constraints(ci, masters, workers, ic.Config.NetworkType)
+	case baremetal.Name, libvirt.Name, none.Name, ovirt.Name, vsphere.Name, kubevirt.Name:
 		// no special provisioning requirements to check
+		return nil, nil
 	default:
-		err = fmt.Errorf("unknown platform type %q", platform)
+		return nil, fmt.Errorf("unknown platform type %q", platform)
+	}
+	// The failing constraints are in the reports.
+	reports, _ := quota.Check(q, constraints)
+	return reports, nil
+}
+
+// Failing returns whether any of the reports is for a constraint that is
+// not available, or whose quota is unknown.
+func Failing(reports []quota.ConstraintReport) bool {
+	for _, report := range reports {
+		if report.Result == quota.NotAvailable || report.Result == quota.Unknown {
+			return true
+		}
 	}
-	return err
+	return false
 }
 
 // Name returns the human-friendly name of the asset.
@@ -18,4 +18,26 @@ type Store interface {
 	// Load retrieves the state of the given asset but does not generate it if it
 	// does not exist and instead will return nil if not found.
 	Load(Asset) (Asset, error)
+
+	// Plan reports how the given assets and their dependencies would be
+	// fetched, without generating, persisting or purging anything.
+	Plan(...Asset) ([]PlannedAsset, error)
+}
+
+// StateBackend persists the serialized state of a Store. Writes are
+// guarded by an opaque revision so that two installers sharing the same
+// backend cannot silently overwrite each other's state.
+type StateBackend interface {
+	// Read returns the persisted state and its revision. When no state has
+	// been persisted yet, Read returns nil data and an empty revision.
+	Read() (data []byte, revision string, err error)
+
+	// Write persists the state if the backend is still at the given
+	// revision and returns the new revision. An empty revision means the
+	// state is expected not to exist yet.
+	Write(data []byte, revision string) (string, error)
+
+	// Delete removes the persisted state. It is not an error if no state
+	// has been persisted.
+	Delete() error
 }
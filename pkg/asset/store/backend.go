@@ -0,0 +1,69 @@
+package store
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	// StateBackendEnvVar is the environment variable that selects the state
+	// backend when none was given on the command line.
+	StateBackendEnvVar = "OPENSHIFT_INSTALL_STATE_BACKEND"
+)
+
+// ErrStateConflict is returned when the persisted state was modified by
+// someone else since it was last read.
+var ErrStateConflict = errors.New("state was modified concurrently")
+
+// NewBackend returns the state backend described by location. An empty
+// location selects the local asset directory. Supported locations are:
+//
+//	file://                                      the asset directory
+//	s3://<bucket>/<key>?region=<r>&endpoint=<e>  an S3-compatible object
+//	secret://<namespace>/<name>?kubeconfig=<p>   a Kubernetes Secret
+//
+// Concurrent writers are only fully serialized by S3-compatible object
+// stores that enforce the If-Match and If-None-Match headers on PutObject,
+// like AWS S3. The state in a Secret is compressed, and must fit in the 1 MiB
+// a Secret can hold.
+func NewBackend(directory, location string) (asset.StateBackend, error) {
+	if location == "" {
+		return newLocalBackend(directory), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid state backend %q", location)
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" || (u.Path != "" && u.Path != "/") {
+			return nil, errors.Errorf("file state backend %q must not have a path; use --dir instead", location)
+		}
+		return newLocalBackend(directory), nil
+	case "s3":
+		key := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || key == "" {
+			return nil, errors.Errorf("s3 state backend %q must be of the form s3://<bucket>/<key>", location)
+		}
+		return newS3Backend(u.Host, key, u.Query().Get("region"), u.Query().Get("endpoint"))
+	case "secret":
+		name := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || name == "" || strings.Contains(name, "/") {
+			return nil, errors.Errorf("secret state backend %q must be of the form secret://<namespace>/<name>", location)
+		}
+		kubeconfig := u.Query().Get("kubeconfig")
+		if kubeconfig == "" {
+			kubeconfig = os.Getenv("KUBECONFIG")
+		}
+		return newSecretBackend(u.Host, name, kubeconfig)
+	default:
+		return nil, errors.Errorf("unsupported state backend scheme %q", u.Scheme)
+	}
+}
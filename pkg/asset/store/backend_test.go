@@ -0,0 +1,203 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBackend(t *testing.T) {
+	cases := []struct {
+		name        string
+		location    string
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "default",
+			location: "",
+			expected: &localBackend{},
+		},
+		{
+			name:     "file",
+			location: "file://",
+			expected: &localBackend{},
+		},
+		{
+			name:        "file with path",
+			location:    "file:///tmp/state",
+			expectedErr: `^file state backend "file:///tmp/state" must not have a path; use --dir instead$`,
+		},
+		{
+			name:        "s3 without key",
+			location:    "s3://bucket",
+			expectedErr: `^s3 state backend "s3://bucket" must be of the form s3://<bucket>/<key>$`,
+		},
+		{
+			name:        "secret without name",
+			location:    "secret://namespace",
+			expectedErr: `^secret state backend "secret://namespace" must be of the form secret://<namespace>/<name>$`,
+		},
+		{
+			name:        "secret with nested name",
+			location:    "secret://namespace/a/b",
+			expectedErr: `^secret state backend "secret://namespace/a/b" must be of the form secret://<namespace>/<name>$`,
+		},
+		{
+			name:        "unknown scheme",
+			location:    "gs://bucket/key",
+			expectedErr: `^unsupported state backend scheme "gs"$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, err := NewBackend("dir", tc.location)
+			if tc.expectedErr != "" {
+				assert.Regexp(t, tc.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, tc.expected, backend)
+		})
+	}
+}
+
+func TestLocalBackendOptimisticLocking(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestLocalBackendOptimisticLocking")
+	if err != nil {
+		t.Fatalf("could not create the temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	first := newLocalBackend(dir)
+	second := newLocalBackend(dir)
+
+	data, revision, err := first.Read()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, revision)
+
+	firstRevision, err := first.Write([]byte(`{"a":1}`), revision)
+	assert.NoError(t, err)
+
+	_, err = second.Write([]byte(`{"b":1}`), revision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	data, secondRevision, err := second.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+	assert.Equal(t, firstRevision, secondRevision)
+
+	_, err = second.Write([]byte(`{"b":1}`), secondRevision)
+	assert.NoError(t, err)
+
+	_, err = first.Write([]byte(`{"a":2}`), firstRevision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	assert.NoError(t, first.Delete())
+	assert.NoError(t, first.Delete())
+}
+
+func TestLocalBackendStaleLock(t *testing.T) {
+	// A process that has exited, to hold a stale lock.
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("could not run a process: %v", err)
+	}
+	exited := cmd.Process.Pid
+
+	cases := []struct {
+		name  string
+		lock  string
+		age   time.Duration
+		stale bool
+	}{
+		{
+			name:  "running process of this host",
+			lock:  fmt.Sprintf("%d %s\n", os.Getpid(), hostname()),
+			age:   2 * staleLockAge,
+			stale: false,
+		},
+		{
+			name:  "exited process of this host",
+			lock:  fmt.Sprintf("%d %s\n", exited, hostname()),
+			stale: true,
+		},
+		{
+			name:  "recent lock of another host",
+			lock:  fmt.Sprintf("%d other-host\n", os.Getpid()),
+			stale: false,
+		},
+		{
+			name:  "old lock of another host",
+			lock:  fmt.Sprintf("%d other-host\n", os.Getpid()),
+			age:   2 * staleLockAge,
+			stale: true,
+		},
+		{
+			name:  "recent lock without host",
+			lock:  "12345\n",
+			stale: false,
+		},
+		{
+			name:  "old lock without host",
+			lock:  "12345\n",
+			age:   2 * staleLockAge,
+			stale: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "TestLocalBackendStaleLock")
+			if err != nil {
+				t.Fatalf("could not create the temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			backend := newLocalBackend(dir)
+			lockPath := backend.path + ".lock"
+			if err := ioutil.WriteFile(lockPath, []byte(tc.lock), 0640); err != nil {
+				t.Fatalf("could not create the lock file: %v", err)
+			}
+			modTime := time.Now().Add(-tc.age)
+			if err := os.Chtimes(lockPath, modTime, modTime); err != nil {
+				t.Fatalf("could not age the lock file: %v", err)
+			}
+
+			_, err = backend.Write([]byte(`{"a":1}`), "")
+			if !tc.stale {
+				assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+				assert.Contains(t, err.Error(), "locked by process")
+				return
+			}
+			assert.NoError(t, err)
+			_, err = os.Stat(lockPath)
+			assert.True(t, os.IsNotExist(err), "expected the lock to be released, got %v", err)
+		})
+	}
+}
+
+func TestStoreDetectsConcurrentWriters(t *testing.T) {
+	clearAssetBehaviors()
+
+	dir, err := ioutil.TempDir("", "TestStoreDetectsConcurrentWriters")
+	if err != nil {
+		t.Fatalf("could not create the temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	first, err := newStore(dir)
+	assert.NoError(t, err)
+	second, err := newStore(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, first.Fetch(&testStoreAssetA{}))
+	err = second.Fetch(&testStoreAssetB{})
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+}
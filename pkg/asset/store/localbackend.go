@@ -0,0 +1,170 @@
+package store
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// staleLockAge is the age after which the lock file of the local backend is
+// considered to be left behind by a crashed process, when that process cannot
+// be checked because it ran on another host.
+const staleLockAge = time.Minute
+
+// localBackend keeps the state file in the asset directory. The revision
+// is the SHA-256 digest of the file contents, and writes are serialized
+// with a lock file next to the state file that records the PID and the host
+// of its holder.
+type localBackend struct {
+	path string
+}
+
+func newLocalBackend(directory string) *localBackend {
+	return &localBackend{path: filepath.Join(directory, stateFileName)}
+}
+
+// Read returns the contents of the state file.
+func (b *localBackend) Read() ([]byte, string, error) {
+	data, err := ioutil.ReadFile(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	return data, digest(data), nil
+}
+
+// Write replaces the state file if its contents still match revision.
+func (b *localBackend) Write(data []byte, revision string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0750); err != nil {
+		return "", err
+	}
+
+	unlock, err := b.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	_, current, err := b.Read()
+	if err != nil {
+		return "", err
+	}
+	if current != revision {
+		return "", errors.Wrapf(ErrStateConflict, "%s", b.path)
+	}
+
+	tmp := b.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0640); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return "", err
+	}
+	return digest(data), nil
+}
+
+// Delete removes the state file.
+func (b *localBackend) Delete() error {
+	if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lock creates the lock file, recording the PID and the host of this process
+// in it. A lock file of a process of this host that is no longer running was
+// left behind by a crash, and is removed. The processes of other hosts cannot
+// be checked, so their lock files are only removed once older than
+// staleLockAge, since the lock is only held for the duration of a write.
+func (b *localBackend) lock() (func(), error) {
+	path := b.path + ".lock"
+	for attempt := 0; ; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d %s\n", os.Getpid(), hostname())
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		holder, stale := readLock(path)
+		if !stale || attempt > 0 {
+			return nil, errors.Wrapf(ErrStateConflict, "%s is locked by %s; remove %s if no installer is running", b.path, holder, path)
+		}
+		logrus.Warnf("Removing the stale lock %s of %s", path, holder)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+}
+
+// readLock describes the holder of the lock file at path and reports whether
+// the lock is stale.
+func readLock(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// The lock was released in the meantime.
+		return "another process", os.IsNotExist(err)
+	}
+	old := time.Since(info.ModTime()) > staleLockAge
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "another process", old
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "another process", old
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return "another process", old
+	}
+	if len(fields) < 2 {
+		// Lock files of older installers only record the PID.
+		return fmt.Sprintf("process %d", pid), old
+	}
+	holder := fmt.Sprintf("process %d on %s", pid, fields[1])
+	if fields[1] != hostname() {
+		return holder, old
+	}
+	return holder, !processRunning(pid)
+}
+
+// processRunning returns whether the process with the PID is running on this
+// host.
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "localhost"
+	}
+	return name
+}
+
+func digest(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
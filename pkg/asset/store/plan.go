@@ -0,0 +1,63 @@
+package store
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// Plan reports how each of the given assets and all of their dependencies
+// would be fetched. Assets are loaded from the target directory and the
+// state file exactly as Fetch would load them, but nothing is generated,
+// persisted or purged. The assets are returned in the order Fetch would
+// visit them, with every asset listed after its dependencies.
+func (s *storeImpl) Plan(assets ...asset.Asset) ([]asset.PlannedAsset, error) {
+	var plan []asset.PlannedAsset
+	planned := map[reflect.Type]bool{}
+	for _, a := range assets {
+		if _, err := s.load(a, ""); err != nil {
+			return nil, errors.Wrapf(err, "failed to load %s", a.Name())
+		}
+		plan = s.plan(a, planned, plan)
+	}
+	return plan, nil
+}
+
+func (s *storeImpl) plan(a asset.Asset, planned map[reflect.Type]bool, plan []asset.PlannedAsset) []asset.PlannedAsset {
+	if planned[reflect.TypeOf(a)] {
+		return plan
+	}
+
+	var dirtyParents []string
+	for _, d := range a.Dependencies() {
+		plan = s.plan(d, planned, plan)
+		state := s.assets[reflect.TypeOf(d)]
+		if state.anyParentsDirty || state.source == onDiskSource {
+			dirtyParents = append(dirtyParents, d.Name())
+		}
+	}
+
+	state := s.assets[reflect.TypeOf(a)]
+	entry := asset.PlannedAsset{
+		Name:   a.Name(),
+		Type:   reflect.TypeOf(a).String(),
+		OnDisk: state.presentOnDisk,
+	}
+	switch {
+	// Assets without a previous copy are generated for the first time,
+	// whether or not their parents are dirty.
+	case state.anyParentsDirty && (state.presentOnDisk || s.isAssetInState(a)):
+		entry.Action = asset.PlanRegenerate
+		entry.DirtyParents = dirtyParents
+	case state.source == onDiskSource:
+		entry.Action = asset.PlanLoadFromDisk
+	case state.source == stateFileSource:
+		entry.Action = asset.PlanLoadFromState
+	default:
+		entry.Action = asset.PlanGenerate
+	}
+	planned[reflect.TypeOf(a)] = true
+	return append(plan, entry)
+}
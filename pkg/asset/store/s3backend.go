@@ -0,0 +1,133 @@
+package store
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+)
+
+// s3Backend keeps the state file in an S3-compatible object store. The
+// revision is the object's ETag, and writes are made conditional with the
+// If-Match and If-None-Match headers, which AWS S3 enforces. Some
+// S3-compatible object stores ignore these headers, so the ETag is also
+// checked before each write. That check detects writers working from a stale
+// state, but not two writers racing between the check and the write, which
+// only an object store that enforces conditional writes prevents.
+type s3Backend struct {
+	client s3iface.S3API
+	bucket string
+	key    string
+}
+
+func newS3Backend(bucket, key, region, endpoint string) (*s3Backend, error) {
+	config := aws.NewConfig()
+	if region != "" {
+		config = config.WithRegion(region)
+	}
+	if endpoint != "" {
+		// Most S3-compatible object stores do not support virtual-hosted
+		// buckets.
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	ssn, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	return &s3Backend{client: s3.New(ssn), bucket: bucket, key: key}, nil
+}
+
+// Read returns the contents of the state object.
+func (b *s3Backend) Read() ([]byte, string, error) {
+	out, err := b.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", errors.Wrapf(err, "failed to get s3://%s/%s", b.bucket, b.key)
+	}
+	defer out.Body.Close()
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read s3://%s/%s", b.bucket, b.key)
+	}
+	return data, aws.StringValue(out.ETag), nil
+}
+
+// Write replaces the state object if its ETag still matches revision.
+func (b *s3Backend) Write(data []byte, revision string) (string, error) {
+	current := ""
+	head, err := b.client.HeadObjectWithContext(aws.BackgroundContext(), &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	if err == nil {
+		current = aws.StringValue(head.ETag)
+	} else if !isS3NotFound(err) {
+		return "", errors.Wrapf(err, "failed to get s3://%s/%s", b.bucket, b.key)
+	}
+	if current != revision {
+		return "", errors.Wrapf(ErrStateConflict, "s3://%s/%s", b.bucket, b.key)
+	}
+
+	condition := map[string]string{"If-None-Match": "*"}
+	if revision != "" {
+		condition = map[string]string{"If-Match": revision}
+	}
+	out, err := b.client.PutObjectWithContext(aws.BackgroundContext(), &s3.PutObjectInput{
+		Bucket:      aws.String(b.bucket),
+		Key:         aws.String(b.key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}, request.WithSetRequestHeaders(condition))
+	if err != nil {
+		if isS3PreconditionFailed(err) {
+			return "", errors.Wrapf(ErrStateConflict, "s3://%s/%s", b.bucket, b.key)
+		}
+		return "", errors.Wrapf(err, "failed to put s3://%s/%s", b.bucket, b.key)
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+// Delete removes the state object.
+func (b *s3Backend) Delete() error {
+	_, err := b.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	if err != nil && !isS3NotFound(err) {
+		return errors.Wrapf(err, "failed to delete s3://%s/%s", b.bucket, b.key)
+	}
+	return nil
+}
+
+func isS3NotFound(err error) bool {
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey
+}
+
+func isS3PreconditionFailed(err error) bool {
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+	// S3 answers 409 when a conflicting conditional write is in flight.
+	return reqErr.StatusCode() == http.StatusPreconditionFailed || reqErr.StatusCode() == http.StatusConflict
+}
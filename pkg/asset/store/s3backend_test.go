@@ -0,0 +1,149 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is an in-memory bucket that honors the conditional headers of
+// PutObject the way S3 does, unless ignoreConditions is set.
+type fakeS3 struct {
+	s3iface.S3API
+	objects          map[string][]byte
+	etags            map[string]string
+	puts             int
+	ignoreConditions bool
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, etags: map[string]string{}}
+}
+
+func (f *fakeS3) GetObject(in *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	data, ok := f.objects[aws.StringValue(in.Key)]
+	if !ok {
+		return nil, awserr.NewRequestFailure(awserr.New(s3.ErrCodeNoSuchKey, "not found", nil), http.StatusNotFound, "")
+	}
+	return &s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(data)),
+		ETag: aws.String(f.etags[aws.StringValue(in.Key)]),
+	}, nil
+}
+
+func (f *fakeS3) HeadObjectWithContext(_ aws.Context, in *s3.HeadObjectInput, _ ...request.Option) (*s3.HeadObjectOutput, error) {
+	etag, ok := f.etags[aws.StringValue(in.Key)]
+	if !ok {
+		return nil, awserr.NewRequestFailure(awserr.New("NotFound", "not found", nil), http.StatusNotFound, "")
+	}
+	return &s3.HeadObjectOutput{ETag: aws.String(etag)}, nil
+}
+
+func (f *fakeS3) PutObjectWithContext(_ aws.Context, in *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	req := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+	for _, opt := range opts {
+		opt(req)
+	}
+	key := aws.StringValue(in.Key)
+	etag, exists := f.etags[key]
+	if !f.ignoreConditions && ((req.HTTPRequest.Header.Get("If-None-Match") == "*" && exists) ||
+		(req.HTTPRequest.Header.Get("If-Match") != "" && req.HTTPRequest.Header.Get("If-Match") != etag)) {
+		return nil, awserr.NewRequestFailure(awserr.New("PreconditionFailed", "precondition failed", nil), http.StatusPreconditionFailed, "")
+	}
+
+	data, err := ioutil.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.puts++
+	f.objects[key] = data
+	f.etags[key] = fmt.Sprintf(`"%d"`, f.puts)
+	return &s3.PutObjectOutput{ETag: aws.String(f.etags[key])}, nil
+}
+
+func (f *fakeS3) DeleteObject(in *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, aws.StringValue(in.Key))
+	delete(f.etags, aws.StringValue(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3BackendConditionalWrites(t *testing.T) {
+	for _, ignoreConditions := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ignore conditions %t", ignoreConditions), func(t *testing.T) {
+			client := newFakeS3()
+			client.ignoreConditions = ignoreConditions
+			testS3BackendConditionalWrites(t, client)
+		})
+	}
+}
+
+func testS3BackendConditionalWrites(t *testing.T, client *fakeS3) {
+	first := &s3Backend{client: client, bucket: "bucket", key: "state.json"}
+	second := &s3Backend{client: client, bucket: "bucket", key: "state.json"}
+
+	data, revision, err := first.Read()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, revision)
+
+	firstRevision, err := first.Write([]byte(`{"a":1}`), revision)
+	assert.NoError(t, err)
+
+	_, err = second.Write([]byte(`{"b":1}`), revision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	data, secondRevision, err := second.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+	assert.Equal(t, firstRevision, secondRevision)
+
+	_, err = second.Write([]byte(`{"b":1}`), secondRevision)
+	assert.NoError(t, err)
+
+	_, err = first.Write([]byte(`{"a":2}`), firstRevision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	assert.NoError(t, first.Delete())
+	data, revision, err = first.Read()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, revision)
+}
+
+func TestS3BackendErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		conflict bool
+	}{
+		{
+			name:     "precondition failed",
+			err:      awserr.NewRequestFailure(awserr.New("PreconditionFailed", "", nil), http.StatusPreconditionFailed, ""),
+			conflict: true,
+		},
+		{
+			name:     "conflicting write in flight",
+			err:      awserr.NewRequestFailure(awserr.New("ConditionalRequestConflict", "", nil), http.StatusConflict, ""),
+			conflict: true,
+		},
+		{
+			name: "access denied",
+			err:  awserr.NewRequestFailure(awserr.New("AccessDenied", "", nil), http.StatusForbidden, ""),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.conflict, isS3PreconditionFailed(tc.err))
+		})
+	}
+}
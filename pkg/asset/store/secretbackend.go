@@ -0,0 +1,131 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// secretStateKey is the key of the uncompressed state of earlier
+	// installers, which is still read.
+	secretStateKey           = "state.json"
+	secretCompressedStateKey = "state.json.gz"
+	secretTimeout            = 30 * time.Second
+
+	// maxSecretSize is the limit of the API server on the size of the data
+	// of a Secret.
+	maxSecretSize = 1024 * 1024
+)
+
+// secretBackend keeps the state file, compressed with gzip, in a Kubernetes
+// Secret. The revision is the Secret's resourceVersion, which the API server
+// already uses for optimistic concurrency.
+type secretBackend struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+func newSecretBackend(namespace, name, kubeconfig string) (*secretBackend, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading kubeconfig for the state backend")
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating a Kubernetes client for the state backend")
+	}
+	return &secretBackend{client: client, namespace: namespace, name: name}, nil
+}
+
+// Read returns the state stored in the Secret.
+func (b *secretBackend) Read() ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	secret, err := b.client.CoreV1().Secrets(b.namespace).Get(ctx, b.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", errors.Wrapf(err, "failed to get secret %s/%s", b.namespace, b.name)
+	}
+	compressed, ok := secret.Data[secretCompressedStateKey]
+	if !ok {
+		return secret.Data[secretStateKey], secret.ResourceVersion, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to decompress the state of secret %s/%s", b.namespace, b.name)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to decompress the state of secret %s/%s", b.namespace, b.name)
+	}
+	return data, secret.ResourceVersion, nil
+}
+
+// Write creates or updates the Secret if its resourceVersion still matches
+// revision.
+func (b *secretBackend) Write(data []byte, revision string) (string, error) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return "", errors.Wrap(err, "failed to compress the state")
+	}
+	if err := writer.Close(); err != nil {
+		return "", errors.Wrap(err, "failed to compress the state")
+	}
+	if compressed.Len() > maxSecretSize {
+		return "", errors.Errorf("the state is %d bytes once compressed, more than the %d bytes a secret can hold; use another state backend", compressed.Len(), maxSecretSize)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       b.namespace,
+			Name:            b.name,
+			ResourceVersion: revision,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{secretCompressedStateKey: compressed.Bytes()},
+	}
+
+	var err error
+	if revision == "" {
+		secret, err = b.client.CoreV1().Secrets(b.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret, err = b.client.CoreV1().Secrets(b.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) || apierrors.IsNotFound(err) {
+			return "", errors.Wrapf(ErrStateConflict, "secret %s/%s", b.namespace, b.name)
+		}
+		return "", errors.Wrapf(err, "failed to write secret %s/%s", b.namespace, b.name)
+	}
+	return secret.ResourceVersion, nil
+}
+
+// Delete removes the Secret.
+func (b *secretBackend) Delete() error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	err := b.client.CoreV1().Secrets(b.namespace).Delete(ctx, b.name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete secret %s/%s", b.namespace, b.name)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// fakeSecretsAPI serves the Secrets of a namespace and rejects writes with a
+// stale resourceVersion the way the API server does.
+type fakeSecretsAPI struct {
+	lock     sync.Mutex
+	secrets  map[string]*corev1.Secret
+	revision int
+}
+
+func (f *fakeSecretsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	const prefix = "/api/v1/namespaces/test/secrets"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	resource := schema.GroupResource{Resource: "secrets"}
+
+	var secret corev1.Secret
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+			writeStatus(w, apierrors.NewBadRequest(err.Error()))
+			return
+		}
+		name = secret.Name
+	}
+	current, exists := f.secrets[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeStatus(w, apierrors.NewNotFound(resource, name))
+			return
+		}
+		writeJSON(w, http.StatusOK, current)
+	case http.MethodPost:
+		if exists {
+			writeStatus(w, apierrors.NewAlreadyExists(resource, name))
+			return
+		}
+		f.store(&secret)
+		writeJSON(w, http.StatusCreated, &secret)
+	case http.MethodPut:
+		if !exists {
+			writeStatus(w, apierrors.NewNotFound(resource, name))
+			return
+		}
+		if secret.ResourceVersion != current.ResourceVersion {
+			writeStatus(w, apierrors.NewConflict(resource, name, errors.New("the object has been modified")))
+			return
+		}
+		f.store(&secret)
+		writeJSON(w, http.StatusOK, &secret)
+	case http.MethodDelete:
+		if !exists {
+			writeStatus(w, apierrors.NewNotFound(resource, name))
+			return
+		}
+		delete(f.secrets, name)
+		writeJSON(w, http.StatusOK, current)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeSecretsAPI) store(secret *corev1.Secret) {
+	f.revision++
+	secret.ResourceVersion = strconv.Itoa(f.revision)
+	f.secrets[secret.Name] = secret
+}
+
+func writeStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.ErrStatus
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func TestSecretBackendOptimisticLocking(t *testing.T) {
+	server := httptest.NewServer(&fakeSecretsAPI{secrets: map[string]*corev1.Secret{}})
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("could not create the client: %v", err)
+	}
+	first := &secretBackend{client: client, namespace: "test", name: "state"}
+	second := &secretBackend{client: client, namespace: "test", name: "state"}
+
+	data, revision, err := first.Read()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Empty(t, revision)
+
+	firstRevision, err := first.Write([]byte(`{"a":1}`), revision)
+	assert.NoError(t, err)
+
+	_, err = second.Write([]byte(`{"b":1}`), revision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	data, secondRevision, err := second.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+	assert.Equal(t, firstRevision, secondRevision)
+
+	_, err = second.Write([]byte(`{"b":1}`), secondRevision)
+	assert.NoError(t, err)
+
+	_, err = first.Write([]byte(`{"a":2}`), firstRevision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict, got %v", err)
+
+	assert.NoError(t, first.Delete())
+	assert.NoError(t, first.Delete())
+
+	_, err = first.Write([]byte(`{"a":3}`), secondRevision)
+	assert.True(t, errors.Is(err, ErrStateConflict), "expected a conflict for a deleted secret, got %v", err)
+}
+
+func TestSecretBackendSize(t *testing.T) {
+	server := httptest.NewServer(&fakeSecretsAPI{secrets: map[string]*corev1.Secret{}})
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("could not create the client: %v", err)
+	}
+	backend := &secretBackend{client: client, namespace: "test", name: "state"}
+
+	// The state compresses well, so it fits even when larger than a secret.
+	large := []byte(`{"a":"` + strings.Repeat("x", 2*maxSecretSize) + `"}`)
+	revision, err := backend.Write(large, "")
+	assert.NoError(t, err)
+	data, _, err := backend.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, large, data)
+
+	random := make([]byte, 2*maxSecretSize)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatalf("could not generate random data: %v", err)
+	}
+	_, err = backend.Write(random, revision)
+	assert.Regexp(t, "more than the 1048576 bytes a secret can hold", err)
+}
+
+func TestSecretBackendReadsUncompressedState(t *testing.T) {
+	api := &fakeSecretsAPI{secrets: map[string]*corev1.Secret{}}
+	secret := &corev1.Secret{Data: map[string][]byte{secretStateKey: []byte(`{"a":1}`)}}
+	secret.Name = "state"
+	api.store(secret)
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("could not create the client: %v", err)
+	}
+	backend := &secretBackend{client: client, namespace: "test", name: "state"}
+	data, _, err := backend.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+}
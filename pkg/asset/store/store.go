@@ -2,15 +2,13 @@ package store
 
 import (
 	"encoding/json"
-	"io/ioutil"
-	"os"
-	"path/filepath"
 	"reflect"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/events"
 )
 
 const (
@@ -50,18 +48,34 @@ type storeImpl struct {
 	assets          map[reflect.Type]*assetState
 	stateFileAssets map[string]json.RawMessage
 	fileFetcher     asset.FileFetcher
+	backend         asset.StateBackend
+	// revision is the backend revision of the state that was last read or
+	// written by this store.
+	revision string
 }
 
 // NewStore returns an asset store that implements the asset.Store interface.
+// The state file is kept in the given directory.
 func NewStore(dir string) (asset.Store, error) {
 	return newStore(dir)
 }
 
+// NewStoreWithBackend returns an asset store that implements the
+// asset.Store interface and keeps its state in the given backend.
+func NewStoreWithBackend(dir string, backend asset.StateBackend) (asset.Store, error) {
+	return newStoreWithBackend(dir, backend)
+}
+
 func newStore(dir string) (*storeImpl, error) {
+	return newStoreWithBackend(dir, newLocalBackend(dir))
+}
+
+func newStoreWithBackend(dir string, backend asset.StateBackend) (*storeImpl, error) {
 	store := &storeImpl{
 		directory:   dir,
 		fileFetcher: &fileFetcher{directory: dir},
 		assets:      map[reflect.Type]*assetState{},
+		backend:     backend,
 	}
 
 	if err := store.loadStateFile(); err != nil {
@@ -111,35 +125,28 @@ func (s *storeImpl) Destroy(a asset.Asset) error {
 	return s.saveStateFile()
 }
 
-// DestroyState removes the state file from disk
+// DestroyState removes the state file from the backend
 func (s *storeImpl) DestroyState() error {
 	s.stateFileAssets = nil
-	path := filepath.Join(s.directory, stateFileName)
-	err := os.Remove(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-	return nil
+	s.revision = ""
+	return s.backend.Delete()
 }
 
-// loadStateFile retrieves the state from the state file present in the given directory
+// loadStateFile retrieves the state from the state backend
 // and returns the assets map
 func (s *storeImpl) loadStateFile() error {
-	path := filepath.Join(s.directory, stateFileName)
 	assets := map[string]json.RawMessage{}
-	data, err := ioutil.ReadFile(path)
+	data, revision, err := s.backend.Read()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
 		return err
 	}
+	s.revision = revision
+	if data == nil {
+		return nil
+	}
 	err = json.Unmarshal(data, &assets)
 	if err != nil {
-		return errors.Wrapf(err, "failed to unmarshal state file %q", path)
+		return errors.Wrapf(err, "failed to unmarshal state file %q", stateFileName)
 	}
 	s.stateFileAssets = assets
 	return nil
@@ -160,7 +167,7 @@ func (s *storeImpl) isAssetInState(a asset.Asset) bool {
 	return ok
 }
 
-// saveStateFile dumps the entire state map into a file
+// saveStateFile dumps the entire state map into the state backend
 func (s *storeImpl) saveStateFile() error {
 	if s.stateFileAssets == nil {
 		s.stateFileAssets = map[string]json.RawMessage{}
@@ -180,13 +187,11 @@ func (s *storeImpl) saveStateFile() error {
 		return err
 	}
 
-	path := filepath.Join(s.directory, stateFileName)
-	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
-		return err
-	}
-	if err := ioutil.WriteFile(path, data, 0640); err != nil {
+	revision, err := s.backend.Write(data, s.revision)
+	if err != nil {
 		return err
 	}
+	s.revision = revision
 	return nil
 }
 
@@ -227,6 +232,7 @@ func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 	if err := a.Generate(parents); err != nil {
 		return errors.Wrapf(err, "failed to generate asset %q", a.Name())
 	}
+	events.Emit(events.Event{Type: events.AssetGenerated, Asset: a.Name()})
 	assetState.asset = a
 	assetState.source = generatedSource
 	return nil
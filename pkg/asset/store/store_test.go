@@ -1,6 +1,8 @@
 package store
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -268,6 +270,7 @@ func TestStoreFetch(t *testing.T) {
 			store := &storeImpl{
 				directory: dir,
 				assets:    map[reflect.Type]*assetState{},
+				backend:   newLocalBackend(dir),
 			}
 			assets := make(map[string]asset.Asset, len(tc.assets))
 			for name := range tc.assets {
@@ -484,3 +487,112 @@ func TestStoreLoadOnDiskAssets(t *testing.T) {
 		})
 	}
 }
+
+func TestStorePlan(t *testing.T) {
+	cases := []struct {
+		name           string
+		assets         map[string][]string
+		onDiskAssets   []string
+		stateAssets    []string
+		target         string
+		expectedPlan   []string
+		expectedDirty  map[string][]string
+		expectedOnDisk []string
+	}{
+		{
+			name: "nothing present",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {},
+			},
+			target:       "a",
+			expectedPlan: []string{"b=generate", "a=generate"},
+		},
+		{
+			name: "parent on disk",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {},
+			},
+			onDiskAssets:   []string{"b"},
+			stateAssets:    []string{"a"},
+			target:         "a",
+			expectedPlan:   []string{"b=load-from-disk", "a=regenerate"},
+			expectedDirty:  map[string][]string{"a": {"b"}},
+			expectedOnDisk: []string{"b"},
+		},
+		{
+			name: "parent on disk without a previous copy",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {},
+			},
+			onDiskAssets:   []string{"b"},
+			target:         "a",
+			expectedPlan:   []string{"b=load-from-disk", "a=generate"},
+			expectedOnDisk: []string{"b"},
+		},
+		{
+			name: "reused from state file",
+			assets: map[string][]string{
+				"a": {"b", "c"},
+				"b": {"c"},
+				"c": {},
+			},
+			stateAssets:  []string{"a", "c"},
+			target:       "a",
+			expectedPlan: []string{"c=load-from-state", "b=generate", "a=load-from-state"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearAssetBehaviors()
+			store := &storeImpl{
+				assets:          map[reflect.Type]*assetState{},
+				stateFileAssets: map[string]json.RawMessage{},
+			}
+			assets := make(map[string]asset.Asset, len(tc.assets))
+			for name := range tc.assets {
+				assets[name] = newTestStoreAsset(name)
+			}
+			for name, deps := range tc.assets {
+				dependenciesOfAsset := make([]asset.Asset, len(deps))
+				for i, d := range deps {
+					dependenciesOfAsset[i] = assets[d]
+				}
+				dependencies[reflect.TypeOf(assets[name])] = dependenciesOfAsset
+			}
+			for _, name := range tc.onDiskAssets {
+				onDiskAssets[reflect.TypeOf(assets[name])] = true
+			}
+			for _, name := range tc.stateAssets {
+				store.stateFileAssets[reflect.TypeOf(assets[name]).String()] = json.RawMessage("{}")
+			}
+			plan, err := store.Plan(assets[tc.target])
+			assert.NoError(t, err, "unexpected error")
+
+			actualPlan := make([]string, len(plan))
+			actualDirty := map[string][]string{}
+			actualOnDisk := []string{}
+			for i, p := range plan {
+				actualPlan[i] = fmt.Sprintf("%s=%s", p.Name, p.Action)
+				if len(p.DirtyParents) > 0 {
+					actualDirty[p.Name] = p.DirtyParents
+				}
+				if p.OnDisk {
+					actualOnDisk = append(actualOnDisk, p.Name)
+				}
+			}
+			if tc.expectedDirty == nil {
+				tc.expectedDirty = map[string][]string{}
+			}
+			if tc.expectedOnDisk == nil {
+				tc.expectedOnDisk = []string{}
+			}
+			assert.Equal(t, tc.expectedPlan, actualPlan)
+			assert.Equal(t, tc.expectedDirty, actualDirty)
+			assert.Equal(t, tc.expectedOnDisk, actualOnDisk)
+			assert.Empty(t, generationLog, "plan must not generate assets")
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	cryptotls "crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+const pkiInstallConfig = `
+apiVersion: v1
+baseDomain: test-domain
+metadata:
+  name: test-cluster
+platform:
+  none: {}
+pullSecret: |
+  {
+    "auths": {
+      "example.com": {
+        "auth": "test-auth"
+       }
+    }
+  }
+`
+
+func TestTLSAssetsRoundTrip(t *testing.T) {
+	cases := []struct {
+		name         string
+		pki          string
+		expectedType reflect.Type
+		expectedSize int
+	}{
+		{
+			name:         "default",
+			expectedType: reflect.TypeOf(&rsa.PrivateKey{}),
+			expectedSize: 2048,
+		},
+		{
+			name:         "ECDSA P-256",
+			pki:          "pki:\n  keyAlgorithm: ECDSA\n",
+			expectedType: reflect.TypeOf(&ecdsa.PrivateKey{}),
+			expectedSize: 256,
+		},
+		{
+			name:         "ECDSA P-384",
+			pki:          "pki:\n  keyAlgorithm: ECDSA\n  keySize: 384\n",
+			expectedType: reflect.TypeOf(&ecdsa.PrivateKey{}),
+			expectedSize: 384,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "TestTLSAssetsRoundTrip")
+			if err != nil {
+				t.Fatalf("could not create the temp dir: %v", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			if err := ioutil.WriteFile(filepath.Join(tempDir, "install-config.yaml"), []byte(pkiInstallConfig+tc.pki), 0666); err != nil {
+				t.Fatalf("could not write the install-config.yaml file: %v", err)
+			}
+
+			assetStore, err := newStore(tempDir)
+			if err != nil {
+				t.Fatalf("failed to create asset store: %v", err)
+			}
+
+			var targets []asset.WritableAsset
+			for _, a := range tls.CertKeyAssets() {
+				targets = append(targets, a)
+			}
+			targets = append(targets, &tls.ServiceAccountKeyPair{})
+			for _, a := range targets {
+				if err := assetStore.Fetch(a, targets...); err != nil {
+					t.Fatalf("failed to fetch %q: %v", a.Name(), err)
+				}
+			}
+
+			newAssetStore, err := newStore(tempDir)
+			if err != nil {
+				t.Fatalf("failed to create new asset store: %v", err)
+			}
+
+			for _, a := range targets {
+				newAsset := reflect.New(reflect.TypeOf(a).Elem()).Interface().(asset.WritableAsset)
+				loaded, err := newAssetStore.Load(newAsset)
+				if !assert.NoErrorf(t, err, "failed to load %q", a.Name()) || !assert.NotNilf(t, loaded, "%q was not loaded", a.Name()) {
+					continue
+				}
+				assert.Equalf(t, a, loaded, "loaded and generated asset %q are not equal", a.Name())
+
+				var keyPEM []byte
+				switch loaded := loaded.(type) {
+				case tls.CertKeyAsset:
+					if _, err := cryptotls.X509KeyPair(loaded.Cert(), loaded.Key()); err != nil {
+						t.Errorf("the certificate and key of %q do not match: %v", a.Name(), err)
+					}
+					keyPEM = loaded.Key()
+				case *tls.ServiceAccountKeyPair:
+					if _, err := tls.PemToPublicKey(loaded.Public()); err != nil {
+						t.Errorf("failed to parse the public key of %q: %v", a.Name(), err)
+					}
+					keyPEM = loaded.Private()
+				}
+				key, err := tls.PemToPrivateKey(keyPEM)
+				if !assert.NoErrorf(t, err, "failed to parse the private key of %q", a.Name()) {
+					continue
+				}
+				assert.Equalf(t, tc.expectedType, reflect.TypeOf(key), "unexpected key type for %q", a.Name())
+				switch key := key.(type) {
+				case *rsa.PrivateKey:
+					assert.Equalf(t, tc.expectedSize, key.N.BitLen(), "unexpected key size for %q", a.Name())
+				case *ecdsa.PrivateKey:
+					assert.Equalf(t, tc.expectedSize, key.Curve.Params().BitSize, "unexpected key size for %q", a.Name())
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/x509/pkix"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 )
 
 // AdminKubeConfigSignerCertKey is a key/cert pair that signs the admin kubeconfig client certs.
@@ -14,21 +15,31 @@ type AdminKubeConfigSignerCertKey struct {
 
 var _ asset.WritableAsset = (*AdminKubeConfigSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *AdminKubeConfigSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *AdminKubeConfigSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "admin-kubeconfig-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityTenYears,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "admin-kubeconfig-signer")
+	return c.generateSigner(cfg, userCA, "admin-kubeconfig-signer")
 }
 
 // Name returns the human-friendly name of the asset.
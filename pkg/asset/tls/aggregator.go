@@ -5,6 +5,7 @@ import (
 	"crypto/x509/pkix"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 )
 
 // AggregatorCA is the asset that generates the aggregator-ca key/cert pair.
@@ -19,19 +20,27 @@ var _ asset.Asset = (*AggregatorCA)(nil)
 // the parent CA, and install config if it depends on the install config for
 // DNS names, etc.
 func (a *AggregatorCA) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *AggregatorCA) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	dependencies.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "aggregator", OrganizationalUnit: []string{"bootkube"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityOneDay,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return a.SelfSignedCertKey.Generate(cfg, "aggregator-ca")
+	return a.generateSigner(cfg, userCA, "aggregator-ca")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -83,21 +92,31 @@ type AggregatorSignerCertKey struct {
 
 var _ asset.WritableAsset = (*AggregatorSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *AggregatorSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *AggregatorSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "aggregator-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityOneDay,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "aggregator-signer")
+	return c.generateSigner(cfg, userCA, "aggregator-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -17,21 +17,31 @@ type KubeAPIServerToKubeletSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeAPIServerToKubeletSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeAPIServerToKubeletSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerToKubeletSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-to-kubelet-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityOneYear,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-to-kubelet-signer")
+	return c.generateSigner(cfg, userCA, "kube-apiserver-to-kubelet-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -110,21 +120,31 @@ type KubeAPIServerLocalhostSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeAPIServerLocalhostSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeAPIServerLocalhostSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerLocalhostSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-localhost-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityTenYears,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-localhost-signer")
+	return c.generateSigner(cfg, userCA, "kube-apiserver-localhost-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -207,21 +227,31 @@ type KubeAPIServerServiceNetworkSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeAPIServerServiceNetworkSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeAPIServerServiceNetworkSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerServiceNetworkSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-service-network-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityTenYears,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-service-network-signer")
+	return c.generateSigner(cfg, userCA, "kube-apiserver-service-network-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -315,21 +345,31 @@ type KubeAPIServerLBSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeAPIServerLBSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeAPIServerLBSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerLBSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-lb-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityTenYears,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-lb-signer")
+	return c.generateSigner(cfg, userCA, "kube-apiserver-lb-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -33,7 +33,7 @@ func (a *BootstrapSSHKeyPair) Name() string {
 // Generate generates the key pair based on its dependencies.
 func (a *BootstrapSSHKeyPair) Generate(dependencies asset.Parents) error {
 	kp := KeyPair{}
-	if err := kp.Generate(bootstrapSSHKeyPairFilenameBase); err != nil {
+	if err := kp.Generate(DefaultKeyParams, bootstrapSSHKeyPairFilenameBase); err != nil {
 		return errors.Wrap(err, "failed to generate key pair")
 	}
 
@@ -27,15 +27,24 @@ func (b *CertBundle) Generate(filename string, certs ...CertInterface) error {
 	}
 
 	buf := bytes.Buffer{}
+	seen := map[string]bool{}
 	for _, c := range certs {
-		cert, err := PemToCertificate(c.Cert())
+		// a CA signed by a user-provided CA carries the chain of the
+		// user-provided CA, which the bundle has to include once
+		chain, err := PemToCertificates(c.Cert())
 		if err != nil {
 			logrus.Debugf("Failed to decode bundle certificate: %s", err)
 			return errors.Wrap(err, "decoding certificate from PEM")
 		}
-		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
-			logrus.Debugf("Failed to encode bundle certificates: %s", err)
-			return errors.Wrap(err, "encoding certificate to PEM")
+		for _, cert := range chain {
+			if seen[string(cert.Raw)] {
+				continue
+			}
+			seen[string(cert.Raw)] = true
+			if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				logrus.Debugf("Failed to encode bundle certificates: %s", err)
+				return errors.Wrap(err, "encoding certificate to PEM")
+			}
 		}
 	}
 	b.BundleRaw = buf.Bytes()
@@ -2,7 +2,7 @@ package tls
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 
 	"github.com/pkg/errors"
@@ -93,14 +93,14 @@ func (c *SignedCertKey) Generate(
 	filenameBase string,
 	appendParent AppendParentChoice,
 ) error {
-	var key *rsa.PrivateKey
+	var key crypto.Signer
 	var crt *x509.Certificate
 	var err error
 
 	caKey, err := PemToPrivateKey(parentCA.Key())
 	if err != nil {
-		logrus.Debugf("Failed to parse RSA private key: %s", err)
-		return errors.Wrap(err, "failed to parse rsa private key")
+		logrus.Debugf("Failed to parse private key: %s", err)
+		return errors.Wrap(err, "failed to parse private key")
 	}
 
 	caCert, err := PemToCertificate(parentCA.Cert())
@@ -115,7 +115,10 @@ func (c *SignedCertKey) Generate(
 		return errors.Wrap(err, "failed to generate signed cert/key pair")
 	}
 
-	c.KeyRaw = PrivateKeyToPem(key)
+	c.KeyRaw, err = PrivateKeyToPem(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode private key")
+	}
 	c.CertRaw = CertToPem(crt)
 
 	if appendParent {
@@ -142,7 +145,10 @@ func (c *SelfSignedCertKey) Generate(
 		return errors.Wrap(err, "failed to generate self-signed cert/key pair")
 	}
 
-	c.KeyRaw = PrivateKeyToPem(key)
+	c.KeyRaw, err = PrivateKeyToPem(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode private key")
+	}
 	c.CertRaw = CertToPem(crt)
 
 	c.generateFiles(filenameBase)
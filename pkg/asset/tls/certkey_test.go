@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 )
 
 func TestSignedCertKeyGenerate(t *testing.T) {
@@ -45,8 +48,10 @@ func TestSignedCertKeyGenerate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{}, &UserCA{})
 			rootCA := &RootCA{}
-			err := rootCA.Generate(nil)
+			err := rootCA.Generate(parents)
 			assert.NoError(t, err, "failed to generate root CA")
 
 			certKey := &SignedCertKey{}
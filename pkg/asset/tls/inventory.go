@@ -0,0 +1,235 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// CertKeyAsset is an asset of a certificate and its private key.
+type CertKeyAsset interface {
+	asset.WritableAsset
+	CertKeyInterface
+}
+
+// CertKeyAssets returns the certificate/key pair assets that the installer
+// generates, CAs first.
+func CertKeyAssets() []CertKeyAsset {
+	return []CertKeyAsset{
+		&RootCA{},
+		&AdminKubeConfigSignerCertKey{},
+		&AggregatorCA{},
+		&AggregatorSignerCertKey{},
+		&KubeAPIServerLBSignerCertKey{},
+		&KubeAPIServerLocalhostSignerCertKey{},
+		&KubeAPIServerServiceNetworkSignerCertKey{},
+		&KubeAPIServerToKubeletSignerCertKey{},
+		&KubeControlPlaneSignerCertKey{},
+		&KubeletBootstrapCertSigner{},
+		&KubeletCSRSignerCertKey{},
+		&AdminKubeConfigClientCertKey{},
+		&AggregatorClientCertKey{},
+		&APIServerProxyCertKey{},
+		&JournalCertKey{},
+		&KubeAPIServerExternalLBServerCertKey{},
+		&KubeAPIServerInternalLBServerCertKey{},
+		&KubeAPIServerLocalhostServerCertKey{},
+		&KubeAPIServerServiceNetworkServerCertKey{},
+		&KubeAPIServerToKubeletClientCertKey{},
+		&KubeControlPlaneKubeControllerManagerClientCertKey{},
+		&KubeControlPlaneKubeSchedulerClientCertKey{},
+		&KubeletClientCertKey{},
+		&MCSCertKey{},
+	}
+}
+
+// CertificateInfo describes a certificate in an Inventory.
+type CertificateInfo struct {
+	// Asset is the name of the asset of the certificate.
+	Asset string `json:"asset"`
+	// Filename is the name of the certificate file in the asset directory.
+	Filename string `json:"filename,omitempty"`
+	// Subject is the distinguished name of the subject of the certificate.
+	Subject string `json:"subject"`
+	// Issuer is the distinguished name of the issuer of the certificate.
+	Issuer string `json:"issuer"`
+	// SANs are the DNS names and IP addresses of the certificate.
+	SANs []string `json:"sans,omitempty"`
+	// KeyType is the algorithm and size of the public key, for example "RSA 2048".
+	KeyType string `json:"keyType"`
+	// IsCA is true if the certificate can sign other certificates.
+	IsCA bool `json:"isCA"`
+	// NotBefore is the start of the validity period.
+	NotBefore time.Time `json:"notBefore"`
+	// NotAfter is the end of the validity period.
+	NotAfter time.Time `json:"notAfter"`
+	// Remaining is the validity left when the inventory was taken, for
+	// example "23h" or "3649d". It is empty for expired certificates.
+	Remaining string `json:"remaining,omitempty"`
+	// Expired is true if the certificate is no longer valid.
+	Expired bool `json:"expired"`
+	// ShortLived is true if the validity period of the certificate is at
+	// most a day, so that configs embedding it cannot be reused for long.
+	// Signed certificates are valid from the start of the validity of their
+	// CA, a little earlier than they were signed, so the period is rounded to
+	// the hour.
+	ShortLived bool `json:"shortLived"`
+	// Issued are the certificates in the inventory signed by this one.
+	Issued []*CertificateInfo `json:"issued,omitempty"`
+
+	cert *x509.Certificate
+}
+
+// Inventory is a tree of certificates, from the CAs to the leaves.
+type Inventory struct {
+	// Certificates are the certificates whose issuer is not in the
+	// inventory, usually self-signed CAs.
+	Certificates []*CertificateInfo `json:"certificates"`
+}
+
+// NewInventory builds the tree of the given certificate assets as of now. The
+// first certificate of each asset is the one described; the rest of its chain
+// is ignored.
+func NewInventory(assets []CertKeyAsset, now time.Time) (*Inventory, error) {
+	infos := make([]*CertificateInfo, 0, len(assets))
+	for _, a := range assets {
+		cert, err := PemToCertificate(a.Cert())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the certificate of %s", a.Name())
+		}
+		info := &CertificateInfo{
+			Asset:      a.Name(),
+			Subject:    cert.Subject.String(),
+			Issuer:     cert.Issuer.String(),
+			KeyType:    keyType(cert),
+			IsCA:       cert.IsCA,
+			NotBefore:  cert.NotBefore,
+			NotAfter:   cert.NotAfter,
+			Expired:    !now.Before(cert.NotAfter),
+			ShortLived: cert.NotAfter.Sub(cert.NotBefore).Round(time.Hour) <= ValidityOneDay,
+			cert:       cert,
+		}
+		for _, f := range a.Files() {
+			if strings.HasSuffix(f.Filename, ".crt") {
+				info.Filename = f.Filename
+			}
+		}
+		info.SANs = append(info.SANs, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			info.SANs = append(info.SANs, ip.String())
+		}
+		if !info.Expired {
+			info.Remaining = duration.HumanDuration(cert.NotAfter.Sub(now))
+		}
+		infos = append(infos, info)
+	}
+
+	inventory := &Inventory{}
+	for _, info := range infos {
+		if issuer := findIssuer(info, infos); issuer != nil {
+			issuer.Issued = append(issuer.Issued, info)
+		} else {
+			inventory.Certificates = append(inventory.Certificates, info)
+		}
+	}
+	return inventory, nil
+}
+
+// findIssuer returns the certificate among candidates that signed info, other
+// than info itself.
+func findIssuer(info *CertificateInfo, candidates []*CertificateInfo) *CertificateInfo {
+	for _, candidate := range candidates {
+		if candidate == info || !candidate.IsCA || !bytes.Equal(info.cert.RawIssuer, candidate.cert.RawSubject) {
+			continue
+		}
+		if info.cert.CheckSignatureFrom(candidate.cert) == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func keyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+// Flagged returns the certificates of the inventory that are expired or
+// short-lived.
+func (i *Inventory) Flagged() []*CertificateInfo {
+	var flagged []*CertificateInfo
+	var walk func([]*CertificateInfo)
+	walk = func(infos []*CertificateInfo) {
+		for _, info := range infos {
+			if info.Expired || info.ShortLived {
+				flagged = append(flagged, info)
+			}
+			walk(info.Issued)
+		}
+	}
+	walk(i.Certificates)
+	return flagged
+}
+
+// WriteText writes the tree of the inventory in a human-readable form.
+func (i *Inventory) WriteText(w io.Writer) error {
+	return writeCertificates(w, i.Certificates, "")
+}
+
+func writeCertificates(w io.Writer, infos []*CertificateInfo, indent string) error {
+	for _, info := range infos {
+		title := info.Asset
+		if info.Expired {
+			title += " [EXPIRED]"
+		}
+		if info.ShortLived {
+			title += " [SHORT-LIVED]"
+		}
+		validity := "expired"
+		if !info.Expired {
+			validity = info.Remaining + " remaining"
+		}
+		lines := []string{
+			title,
+			"  subject: " + info.Subject,
+		}
+		if info.Filename != "" {
+			lines = append(lines, "  file: "+info.Filename)
+		}
+		if len(info.SANs) > 0 {
+			lines = append(lines, "  SANs: "+strings.Join(info.SANs, ", "))
+		}
+		lines = append(lines,
+			"  key: "+info.KeyType,
+			fmt.Sprintf("  valid: %s to %s (%s)", info.NotBefore.UTC().Format(time.RFC3339), info.NotAfter.UTC().Format(time.RFC3339), validity),
+		)
+		for _, line := range lines {
+			if _, err := fmt.Fprintf(w, "%s%s\n", indent, line); err != nil {
+				return err
+			}
+		}
+		if err := writeCertificates(w, info.Issued, indent+"    "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package tls
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+func TestNewInventory(t *testing.T) {
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{}, &UserCA{})
+	rootCA := &RootCA{}
+	aggregatorCA := &AggregatorCA{}
+	for _, ca := range []asset.Asset{rootCA, aggregatorCA} {
+		if err := ca.Generate(parents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parents.Add(rootCA, aggregatorCA)
+	journal := &JournalCertKey{}
+	proxy := &APIServerProxyCertKey{}
+	for _, leaf := range []asset.Asset{journal, proxy} {
+		if err := leaf.Generate(parents); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assets := []CertKeyAsset{proxy, rootCA, journal, aggregatorCA}
+
+	inventory, err := NewInventory(assets, time.Now())
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, inventory.Certificates, 2) {
+		return
+	}
+	root, aggregator := inventory.Certificates[0], inventory.Certificates[1]
+	assert.Equal(t, "Root CA", root.Asset)
+	assert.Equal(t, "tls/root-ca.crt", root.Filename)
+	assert.Equal(t, "CN=root-ca,OU=openshift", root.Subject)
+	assert.Equal(t, "RSA 2048", root.KeyType)
+	assert.True(t, root.IsCA)
+	assert.False(t, root.Expired)
+	assert.False(t, root.ShortLived)
+	if assert.Len(t, root.Issued, 1) {
+		assert.Equal(t, "Certificate (journal-gatewayd)", root.Issued[0].Asset)
+		assert.Equal(t, "CN=root-ca,OU=openshift", root.Issued[0].Issuer)
+	}
+	assert.Equal(t, "Certificate (aggregator)", aggregator.Asset)
+	assert.True(t, aggregator.ShortLived)
+	if assert.Len(t, aggregator.Issued, 1) {
+		assert.Equal(t, "Certificate (system:kube-apiserver-proxy)", aggregator.Issued[0].Asset)
+	}
+	assert.Len(t, inventory.Flagged(), 2)
+
+	var text bytes.Buffer
+	assert.NoError(t, inventory.WriteText(&text))
+	assert.Contains(t, text.String(), "Certificate (aggregator) [SHORT-LIVED]\n")
+	assert.Contains(t, text.String(), "\n    Certificate (system:kube-apiserver-proxy) [SHORT-LIVED]\n")
+
+	later, err := NewInventory(assets, time.Now().Add(2*ValidityOneDay))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, cert := range later.Flagged() {
+		assert.True(t, cert.Expired, "%s should have expired", cert.Asset)
+		assert.Empty(t, cert.Remaining)
+	}
+	assert.True(t, strings.Contains(later.Certificates[0].Remaining, "y"), "unexpected remaining validity %q", later.Certificates[0].Remaining)
+}
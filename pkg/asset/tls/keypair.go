@@ -20,19 +20,22 @@ type KeyPair struct {
 	FileList []*asset.File
 }
 
-// Generate generates the rsa private / public key pair.
-func (k *KeyPair) Generate(filenameBase string) error {
-	key, err := PrivateKey()
+// Generate generates the private / public key pair with the given parameters.
+func (k *KeyPair) Generate(params KeyParams, filenameBase string) error {
+	key, err := PrivateKey(params)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate private key")
 	}
 
-	pubkeyData, err := PublicKeyToPem(&key.PublicKey)
+	pubkeyData, err := PublicKeyToPem(key.Public())
 	if err != nil {
 		return errors.Wrap(err, "failed to get public key data from private key")
 	}
 
-	k.Pvt = PrivateKeyToPem(key)
+	k.Pvt, err = PrivateKeyToPem(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode private key")
+	}
 	k.Pub = pubkeyData
 
 	k.FileList = []*asset.File{
@@ -5,6 +5,7 @@ import (
 	"crypto/x509/pkix"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 )
 
 // KubeControlPlaneSignerCertKey is a key/cert pair that signs the kube control-plane client certs.
@@ -14,21 +15,31 @@ type KubeControlPlaneSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeControlPlaneSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeControlPlaneSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeControlPlaneSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-control-plane-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityOneYear,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-control-plane-signer")
+	return c.generateSigner(cfg, userCA, "kube-control-plane-signer")
 }
 
 // Name returns the human-friendly name of the asset.
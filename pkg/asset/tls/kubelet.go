@@ -5,6 +5,7 @@ import (
 	"crypto/x509/pkix"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 )
 
 // KubeletCSRSignerCertKey is a key/cert pair that signs the kubelet client certs.
@@ -14,21 +15,31 @@ type KubeletCSRSignerCertKey struct {
 
 var _ asset.WritableAsset = (*KubeletCSRSignerCertKey)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeletCSRSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeletCSRSignerCertKey) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kubelet-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityOneDay,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kubelet-signer")
+	return c.generateSigner(cfg, userCA, "kubelet-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -104,21 +115,31 @@ type KubeletBootstrapCertSigner struct {
 
 var _ asset.WritableAsset = (*KubeletBootstrapCertSigner)(nil)
 
-// Dependencies returns the dependency of the root-ca, which is empty.
+// Dependencies returns the dependencies of the cert/key pair, which are the
+// install config for the parameters of the key and the user-provided CA that
+// signs it, if any.
 func (c *KubeletBootstrapCertSigner) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&UserCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeletBootstrapCertSigner) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	userCA := &UserCA{}
+	parents.Get(installConfig, userCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kubelet-bootstrap-kubeconfig-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		Validity:  ValidityTenYears,
 		IsCA:      true,
+		Key:       keyParams(installConfig),
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kubelet-bootstrap-kubeconfig-signer")
+	return c.generateSigner(cfg, userCA, "kubelet-bootstrap-kubeconfig-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -1,6 +1,9 @@
 package tls
 
-import "github.com/openshift/installer/pkg/asset"
+import (
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
 
 // ServiceAccountKeyPair is the asset that generates the service-account public/private key pair.
 type ServiceAccountKeyPair struct {
@@ -13,12 +16,17 @@ var _ asset.WritableAsset = (*ServiceAccountKeyPair)(nil)
 // the parent CA, and install config if it depends on the install config for
 // DNS names, etc.
 func (a *ServiceAccountKeyPair) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *ServiceAccountKeyPair) Generate(dependencies asset.Parents) error {
-	return a.KeyPair.Generate("service-account")
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	return a.KeyPair.Generate(keyParams(installConfig), "service-account")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -17,6 +17,9 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
 )
 
 const (
@@ -41,6 +44,47 @@ type CertCfg struct {
 	Subject      pkix.Name
 	Validity     time.Duration
 	IsCA         bool
+	// Key are the parameters of the generated private key. When unset, a
+	// self-signed certificate gets the default key and a signed certificate
+	// gets a key like the one of its CA.
+	Key KeyParams
+}
+
+// KeyParams are the algorithm and size of a private key.
+type KeyParams struct {
+	// Algorithm is the algorithm of the key.
+	Algorithm types.KeyAlgorithm
+	// Size is the modulus size in bits of an RSA key, or the curve size of an
+	// ECDSA key.
+	Size int
+}
+
+// DefaultKeyParams are the parameters of the generated keys when the install
+// config does not configure them.
+var DefaultKeyParams = KeyParams{Algorithm: types.RSAKeyAlgorithm, Size: keySize}
+
+// keyParams returns the parameters of the private keys configured in the
+// install config.
+func keyParams(installConfig *installconfig.InstallConfig) KeyParams {
+	if installConfig.Config == nil || installConfig.Config.PKI == nil {
+		return DefaultKeyParams
+	}
+	return KeyParams{
+		Algorithm: installConfig.Config.PKI.KeyAlgorithm,
+		Size:      installConfig.Config.PKI.KeySize,
+	}
+}
+
+// keyParamsOf returns the parameters of the private key.
+func keyParamsOf(key crypto.Signer) KeyParams {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return KeyParams{Algorithm: types.RSAKeyAlgorithm, Size: key.N.BitLen()}
+	case *ecdsa.PrivateKey:
+		return KeyParams{Algorithm: types.ECDSAKeyAlgorithm, Size: key.Curve.Params().BitSize}
+	default:
+		return DefaultKeyParams
+	}
 }
 
 // rsaPublicKey reflects the ASN.1 structure of a PKCS#1 public key.
@@ -49,18 +93,43 @@ type rsaPublicKey struct {
 	E int
 }
 
-// PrivateKey generates an RSA Private key and returns the value
-func PrivateKey() (*rsa.PrivateKey, error) {
-	rsaKey, err := rsa.GenerateKey(rand.Reader, keySize)
-	if err != nil {
-		return nil, errors.Wrap(err, "error generating RSA private key")
+// PrivateKey generates a private key with the given parameters, or with the
+// default parameters if they are unset.
+func PrivateKey(params KeyParams) (crypto.Signer, error) {
+	if params == (KeyParams{}) {
+		params = DefaultKeyParams
+	}
+	switch params.Algorithm {
+	case types.RSAKeyAlgorithm:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, params.Size)
+		if err != nil {
+			return nil, errors.Wrap(err, "error generating RSA private key")
+		}
+		return rsaKey, nil
+	case types.ECDSAKeyAlgorithm:
+		var curve elliptic.Curve
+		switch params.Size {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported ECDSA key size %d", params.Size)
+		}
+		ecdsaKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "error generating ECDSA private key")
+		}
+		return ecdsaKey, nil
+	default:
+		return nil, errors.Errorf("unsupported key algorithm %q", params.Algorithm)
 	}
-
-	return rsaKey, nil
 }
 
 // SelfSignedCertificate creates a self signed certificate
-func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, error) {
+func SelfSignedCertificate(cfg *CertCfg, key crypto.Signer) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -94,9 +163,9 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 func SignedCertificate(
 	cfg *CertCfg,
 	csr *x509.CertificateRequest,
-	key *rsa.PrivateKey,
+	key crypto.Signer,
 	caCert *x509.Certificate,
-	caKey *rsa.PrivateKey,
+	caKey crypto.Signer,
 ) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
@@ -150,11 +219,15 @@ func generateSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
 }
 
 // GenerateSignedCertificate generate a key and cert defined by CertCfg and signed by CA.
-func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
-	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+func GenerateSignedCertificate(caKey crypto.Signer, caCert *x509.Certificate,
+	cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
 
-	// create a private key
-	key, err := PrivateKey()
+	// create a private key, like the one of the CA unless configured otherwise
+	params := cfg.Key
+	if params == (KeyParams{}) {
+		params = keyParamsOf(caKey)
+	}
+	key, err := PrivateKey(params)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
@@ -182,8 +255,8 @@ func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
 }
 
 // GenerateSelfSignedCertificate generates a key/cert pair defined by CertCfg.
-func GenerateSelfSignedCertificate(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
-	key, err := PrivateKey()
+func GenerateSelfSignedCertificate(cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
+	key, err := PrivateKey(cfg.Key)
 	if err != nil {
 		logrus.Debugf("Failed to generate a private key: %s", err)
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
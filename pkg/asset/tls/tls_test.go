@@ -6,10 +6,12 @@ import (
 	"crypto/x509/pkix"
 	"testing"
 	"time"
+
+	"github.com/openshift/installer/pkg/types"
 )
 
 func TestSelfSignedCertificate(t *testing.T) {
-	key, err := PrivateKey()
+	key, err := PrivateKey(DefaultKeyParams)
 	if err != nil {
 		t.Fatalf("Failed to generate Private Key: %v", err)
 	}
@@ -61,7 +63,7 @@ func TestSelfSignedCertificate(t *testing.T) {
 }
 
 func TestSignedCertificate(t *testing.T) {
-	key, err := PrivateKey()
+	key, err := PrivateKey(DefaultKeyParams)
 	if err != nil {
 		t.Fatalf("Failed to generate private key: %v", err)
 	}
@@ -105,3 +107,56 @@ func TestSignedCertificate(t *testing.T) {
 		}
 	}
 }
+
+func TestPrivateKey(t *testing.T) {
+	cases := []struct {
+		params KeyParams
+		err    bool
+	}{
+		{params: KeyParams{}},
+		{params: KeyParams{Algorithm: types.RSAKeyAlgorithm, Size: 3072}},
+		{params: KeyParams{Algorithm: types.ECDSAKeyAlgorithm, Size: 256}},
+		{params: KeyParams{Algorithm: types.ECDSAKeyAlgorithm, Size: 384}},
+		{params: KeyParams{Algorithm: types.ECDSAKeyAlgorithm, Size: 2048}, err: true},
+		{params: KeyParams{Algorithm: "Ed25519"}, err: true},
+	}
+	for _, c := range cases {
+		key, err := PrivateKey(c.params)
+		if c.err {
+			if err == nil {
+				t.Errorf("expected an error generating a %v key", c.params)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed to generate a %v key: %v", c.params, err)
+			continue
+		}
+
+		expected := c.params
+		if expected == (KeyParams{}) {
+			expected = DefaultKeyParams
+		}
+		keyPEM, err := PrivateKeyToPem(key)
+		if err != nil {
+			t.Errorf("failed to encode a %v key: %v", c.params, err)
+			continue
+		}
+		decoded, err := PemToPrivateKey(keyPEM)
+		if err != nil {
+			t.Errorf("failed to decode a %v key: %v", c.params, err)
+			continue
+		}
+		if actual := keyParamsOf(decoded); actual != expected {
+			t.Errorf("expected a %v key, got %v", expected, actual)
+		}
+		publicPEM, err := PublicKeyToPem(decoded.Public())
+		if err != nil {
+			t.Errorf("failed to encode the public key of a %v key: %v", c.params, err)
+			continue
+		}
+		if _, err := PemToPublicKey(publicPEM); err != nil {
+			t.Errorf("failed to decode the public key of a %v key: %v", c.params, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+var (
+	userCACertFilename = filepath.Join(tlsDir, "user-ca.crt")
+	userCAKeyFilename  = filepath.Join(tlsDir, "user-ca.key")
+)
+
+// SignerProvider provides the private key of a user-provided CA that is not
+// in the assets directory, for example a key held by a PKCS#11 token.
+type SignerProvider interface {
+	// Signer returns the private key of the certificate, or nil if the
+	// provider does not hold it.
+	Signer(cert *x509.Certificate) (crypto.Signer, error)
+}
+
+var signerProviders []SignerProvider
+
+// RegisterSignerProvider registers a provider that is asked for the private
+// key of the user-provided CA.
+func RegisterSignerProvider(provider SignerProvider) {
+	signerProviders = append(signerProviders, provider)
+}
+
+// RegisterAssetsDirectory registers a provider for the private key of the
+// user-provided CA in tls/user-ca.key of the assets directory. The key is read
+// from the file whenever a CA of the cluster is signed, and is never copied
+// into the assets or the state of the installer.
+func RegisterAssetsDirectory(directory string) {
+	RegisterSignerProvider(keyFileProvider(filepath.Join(directory, userCAKeyFilename)))
+}
+
+// keyFileProvider provides the private key in the PEM file at its path.
+type keyFileProvider string
+
+// Signer returns the private key in the file, or nil if there is no file.
+func (p keyFileProvider) Signer(*x509.Certificate) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(string(p))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	key, err := PemToPrivateKey(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", userCAKeyFilename)
+	}
+	return key, nil
+}
+
+// UserCA contains a user-provided CA, usually an enterprise intermediate CA,
+// that signs the CAs of the cluster instead of them being self-signed.
+// This asset does not generate any new content and only loads the
+// certificate chain from disk when provided by the user. Its private key is
+// left where the user provided it, and is only read from the registered
+// SignerProviders when a CA of the cluster is signed.
+type UserCA struct {
+	CertRaw  []byte
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*UserCA)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*UserCA) Name() string {
+	return "User-provided CA"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*UserCA) Dependencies() []asset.Asset {
+	return nil
+}
+
+// Generate is a no-op because the CA can only be provided by the user.
+func (*UserCA) Generate(asset.Parents) error { return nil }
+
+// Files returns the files generated by the asset.
+func (c *UserCA) Files() []*asset.File {
+	return c.FileList
+}
+
+// Cert returns the certificate of the CA followed by the rest of its chain.
+func (c *UserCA) Cert() []byte {
+	return c.CertRaw
+}
+
+// Provided returns true if the user provided a CA.
+func (c *UserCA) Provided() bool {
+	return len(c.CertRaw) > 0
+}
+
+// Load reads the certificate chain from the disk. It ensures that the
+// certificate can sign the CAs of the cluster and that its private key is
+// available.
+func (c *UserCA) Load(f asset.FileFetcher) (bool, error) {
+	certFile, err := f.FetchByName(userCACertFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	chain, err := PemToCertificates(certFile.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s", userCACertFilename)
+	}
+	if err := validateUserCA(chain, time.Now()); err != nil {
+		return false, errors.Wrapf(err, "invalid %s", userCACertFilename)
+	}
+
+	c.CertRaw = certFile.Data
+	c.FileList = []*asset.File{certFile}
+	if _, err := userCASigner(chain[0]); err != nil {
+		return false, err
+	}
+
+	if validity := time.Until(chain[0].NotAfter); validity < ValidityTenYears {
+		logrus.Warnf("The user-provided CA expires at %s, the CAs of the cluster it signs will expire with it", chain[0].NotAfter.UTC().Format(time.RFC3339))
+	}
+	return true, nil
+}
+
+// userCASigner returns the private key of the certificate of the CA from the
+// registered providers.
+func userCASigner(cert *x509.Certificate) (crypto.Signer, error) {
+	var key crypto.Signer
+	for _, provider := range signerProviders {
+		if key != nil {
+			break
+		}
+		var err error
+		key, err = provider.Signer(cert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the private key of the user-provided CA")
+		}
+	}
+	if key == nil {
+		return nil, errors.Errorf("the private key of the user-provided CA was not found in %s", userCAKeyFilename)
+	}
+
+	certPublic, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the public key of the user-provided CA")
+	}
+	keyPublic, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the public key of the private key of the user-provided CA")
+	}
+	if !bytes.Equal(certPublic, keyPublic) {
+		return nil, errors.New("the private key of the user-provided CA does not match its certificate")
+	}
+	return key, nil
+}
+
+// validateUserCA checks that the first certificate of the chain can sign the
+// CAs of the cluster, that the constraints of the chain allow the
+// certificates those CAs sign, and that each certificate of the chain is
+// signed by the next one.
+func validateUserCA(chain []*x509.Certificate, now time.Time) error {
+	cert := chain[0]
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return errors.New("the certificate is not a CA")
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return errors.New("the key usage of the certificate does not allow signing certificates")
+	}
+	if now.Before(cert.NotBefore) || !now.Before(cert.NotAfter) {
+		return errors.Errorf("the certificate is only valid from %s to %s", cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	for i, cert := range chain {
+		// the CAs of the cluster are intermediates between the chain and
+		// the certificates they sign
+		if (cert.MaxPathLen > 0 || cert.MaxPathLenZero) && cert.MaxPathLen <= i {
+			return errors.Errorf("the path length constraint of certificate %d of the chain does not allow signing CAs", i+1)
+		}
+		if !allowsServerAndClientAuth(cert.ExtKeyUsage) {
+			return errors.Errorf("the extended key usage of certificate %d of the chain does not allow server and client certificates", i+1)
+		}
+		if len(cert.PermittedDNSDomains) > 0 || len(cert.ExcludedDNSDomains) > 0 || len(cert.PermittedIPRanges) > 0 || len(cert.ExcludedIPRanges) > 0 {
+			logrus.Warnf("Certificate %d of the user-provided CA chain has name constraints, the certificates of the cluster must satisfy them", i+1)
+		}
+		if i > 0 {
+			if err := chain[i-1].CheckSignatureFrom(cert); err != nil {
+				return errors.Wrapf(err, "certificate %d of the chain is not signed by the next one", i)
+			}
+		}
+	}
+	return nil
+}
+
+// allowsServerAndClientAuth returns true if the extended key usage of a CA
+// allows it to sign server and client certificates.
+func allowsServerAndClientAuth(usages []x509.ExtKeyUsage) bool {
+	if len(usages) == 0 {
+		return true
+	}
+	var server, client bool
+	for _, usage := range usages {
+		switch usage {
+		case x509.ExtKeyUsageAny:
+			return true
+		case x509.ExtKeyUsageServerAuth:
+			server = true
+		case x509.ExtKeyUsageClientAuth:
+			client = true
+		}
+	}
+	return server && client
+}
+
+// generateSigner generates the cert/key pair of a CA of the cluster. The
+// certificate is signed by the user-provided CA, and followed by its chain,
+// when there is one, and is self-signed otherwise.
+func (c *SelfSignedCertKey) generateSigner(cfg *CertCfg, userCA *UserCA, filenameBase string) error {
+	if !userCA.Provided() {
+		return c.Generate(cfg, filenameBase)
+	}
+
+	chain, err := PemToCertificates(userCA.Cert())
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the user-provided CA")
+	}
+	caCert := chain[0]
+	caKey, err := userCASigner(caCert)
+	if err != nil {
+		return err
+	}
+
+	// a CA cannot outlive the CA that signs it
+	signerCfg := *cfg
+	if validity := time.Until(caCert.NotAfter); validity < signerCfg.Validity {
+		signerCfg.Validity = validity
+	}
+	key, crt, err := GenerateSignedCertificate(caKey, caCert, &signerCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate cert/key pair signed by the user-provided CA")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(chain[len(chain)-1])
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[:len(chain)-1] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := crt.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return errors.Wrapf(err, "failed to verify %s against the user-provided CA", cfg.Subject.CommonName)
+	}
+
+	c.KeyRaw, err = PrivateKeyToPem(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode private key")
+	}
+	certs := [][]byte{CertToPem(crt)}
+	for _, cert := range chain {
+		certs = append(certs, CertToPem(cert))
+	}
+	c.CertRaw = bytes.Join(certs, nil)
+
+	c.generateFiles(filenameBase)
+
+	return nil
+}
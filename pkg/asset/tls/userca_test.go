@@ -0,0 +1,265 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/mock"
+)
+
+// newUserCA creates a CA certificate from the template, signed by itself.
+func newUserCA(t *testing.T, template *x509.Certificate) ([]byte, []byte, crypto.Signer) {
+	key, err := PrivateKey(DefaultKeyParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.SerialNumber = big.NewInt(1)
+	template.Subject = pkix.Name{CommonName: "enterprise-ca", Organization: []string{"Example"}}
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(ValidityOneYear)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := PrivateKeyToPem(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return CertToPem(cert), keyPEM, key
+}
+
+func caTemplate() *x509.Certificate {
+	return &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+}
+
+// assetsDirectory returns a temporary assets directory with the private key
+// of the user-provided CA, if any.
+func assetsDirectory(t *testing.T, key []byte) string {
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if key == nil {
+		return dir
+	}
+	if err := os.MkdirAll(filepath.Join(dir, tlsDir), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, userCAKeyFilename), key, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+type fakeSignerProvider struct {
+	key crypto.Signer
+}
+
+func (p *fakeSignerProvider) Signer(*x509.Certificate) (crypto.Signer, error) {
+	return p.key, nil
+}
+
+func TestUserCALoad(t *testing.T) {
+	validCert, validKey, validSigner := newUserCA(t, caTemplate())
+	_, otherKey, _ := newUserCA(t, caTemplate())
+	notCA := caTemplate()
+	notCA.IsCA = false
+	notCACert, notCAKey, _ := newUserCA(t, notCA)
+	pathLenZero := caTemplate()
+	pathLenZero.MaxPathLenZero = true
+	pathLenZeroCert, pathLenZeroKey, _ := newUserCA(t, pathLenZero)
+	expired := caTemplate()
+	expired.NotBefore = time.Now().Add(-2 * ValidityOneYear)
+	expired.NotAfter = time.Now().Add(-ValidityOneYear)
+	expiredCert, expiredKey, _ := newUserCA(t, expired)
+	serverOnly := caTemplate()
+	serverOnly.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverOnlyCert, serverOnlyKey, _ := newUserCA(t, serverOnly)
+
+	cases := []struct {
+		name          string
+		cert          []byte
+		key           []byte
+		provider      SignerProvider
+		expectedFound bool
+		expectedError string
+	}{
+		{
+			name: "not provided",
+		},
+		{
+			name:          "valid",
+			cert:          validCert,
+			key:           validKey,
+			expectedFound: true,
+		},
+		{
+			name:          "key from a provider",
+			cert:          validCert,
+			provider:      &fakeSignerProvider{key: validSigner},
+			expectedFound: true,
+		},
+		{
+			name:          "missing key",
+			cert:          validCert,
+			expectedError: "the private key of the user-provided CA was not found in tls/user-ca.key",
+		},
+		{
+			name:          "mismatched key",
+			cert:          validCert,
+			key:           otherKey,
+			expectedError: "the private key of the user-provided CA does not match its certificate",
+		},
+		{
+			name:          "not a CA",
+			cert:          notCACert,
+			key:           notCAKey,
+			expectedError: "invalid tls/user-ca.crt: the certificate is not a CA",
+		},
+		{
+			name:          "path length zero",
+			cert:          pathLenZeroCert,
+			key:           pathLenZeroKey,
+			expectedError: "invalid tls/user-ca.crt: the path length constraint of certificate 1 of the chain does not allow signing CAs",
+		},
+		{
+			name:          "server authentication only",
+			cert:          serverOnlyCert,
+			key:           serverOnlyKey,
+			expectedError: "invalid tls/user-ca.crt: the extended key usage of certificate 1 of the chain does not allow server and client certificates",
+		},
+		{
+			name: "expired",
+			cert: expiredCert,
+			key:  expiredKey,
+			expectedError: "invalid tls/user-ca.crt: the certificate is only valid from " +
+				expired.NotBefore.UTC().Format(time.RFC3339) + " to " + expired.NotAfter.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			fetch := func(filename string, data []byte) {
+				if data == nil {
+					fileFetcher.EXPECT().FetchByName(filename).Return(nil, &os.PathError{Err: os.ErrNotExist}).AnyTimes()
+				} else {
+					fileFetcher.EXPECT().FetchByName(filename).Return(&asset.File{Filename: filename, Data: data}, nil).AnyTimes()
+				}
+			}
+			fetch(userCACertFilename, tc.cert)
+
+			defer func(providers []SignerProvider) { signerProviders = providers }(signerProviders)
+			signerProviders = nil
+			if tc.provider != nil {
+				RegisterSignerProvider(tc.provider)
+			}
+			RegisterAssetsDirectory(assetsDirectory(t, tc.key))
+
+			userCA := &UserCA{}
+			found, err := userCA.Load(fileFetcher)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedFound, userCA.Provided())
+			for _, file := range userCA.Files() {
+				assert.NotEqual(t, userCAKeyFilename, file.Filename, "the private key is kept in the assets")
+			}
+		})
+	}
+}
+
+func TestSignersSignedByUserCA(t *testing.T) {
+	userCACert, userCAKey, _ := newUserCA(t, caTemplate())
+	defer func(providers []SignerProvider) { signerProviders = providers }(signerProviders)
+	signerProviders = nil
+	RegisterAssetsDirectory(assetsDirectory(t, userCAKey))
+	userCA := &UserCA{
+		CertRaw:  userCACert,
+		FileList: []*asset.File{{Filename: userCACertFilename, Data: userCACert}},
+	}
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{}, userCA)
+
+	rootCA := &RootCA{}
+	if err := rootCA.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+	chain, err := PemToCertificates(rootCA.Cert())
+	if !assert.NoError(t, err) || !assert.Len(t, chain, 2) {
+		return
+	}
+	caCert, err := PemToCertificate(userCACert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, chain[0].CheckSignatureFrom(caCert), "the root CA is not signed by the user-provided CA")
+	assert.True(t, chain[0].IsCA)
+	assert.Equal(t, caCert.Raw, chain[1].Raw)
+	assert.False(t, chain[0].NotAfter.After(caCert.NotAfter), "the root CA outlives the user-provided CA")
+
+	// the certificates signed by the root CA verify against the user-provided CA
+	parents.Add(rootCA)
+	journal := &JournalCertKey{}
+	if err := journal.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := PemToCertificates(journal.Cert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(chain[0])
+	_, err = leaf[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	assert.NoError(t, err)
+
+	// the trust bundles include the chain of the user-provided CA once
+	signer := &KubeAPIServerLBSignerCertKey{}
+	if err := signer.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+	bundle := &CertBundle{}
+	if err := bundle.Generate("test-ca-bundle", rootCA, signer); err != nil {
+		t.Fatal(err)
+	}
+	bundled, err := PemToCertificates(bundle.Cert())
+	if !assert.NoError(t, err) || !assert.Len(t, bundled, 3) {
+		return
+	}
+	assert.Equal(t, chain[0].Raw, bundled[0].Raw)
+	assert.Equal(t, caCert.Raw, bundled[1].Raw)
+	assert.Equal(t, "kube-apiserver-lb-signer", bundled[2].Subject.CommonName)
+}
@@ -1,6 +1,8 @@
 package tls
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -9,16 +11,29 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// PrivateKeyToPem converts an rsa.PrivateKey object to pem string
-func PrivateKeyToPem(key *rsa.PrivateKey) []byte {
-	keyInBytes := x509.MarshalPKCS1PrivateKey(key)
-	keyinPem := pem.EncodeToMemory(
-		&pem.Block{
+// PrivateKeyToPem converts an RSA or ECDSA private key to pem string. RSA
+// keys are encoded in PKCS #1 and ECDSA keys in SEC 1 form.
+func PrivateKeyToPem(key crypto.Signer) ([]byte, error) {
+	var block *pem.Block
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{
 			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}
+	case *ecdsa.PrivateKey:
+		keyInBytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal EC private key")
+		}
+		block = &pem.Block{
+			Type:  "EC PRIVATE KEY",
 			Bytes: keyInBytes,
-		},
-	)
-	return keyinPem
+		}
+	default:
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+	return pem.EncodeToMemory(block), nil
 }
 
 // CertToPem converts an x509.Certificate object to a pem string
@@ -43,33 +58,58 @@ func CSRToPem(cert *x509.CertificateRequest) []byte {
 	return certInPem
 }
 
-// PublicKeyToPem converts an rsa.PublicKey object to pem string
-func PublicKeyToPem(key *rsa.PublicKey) ([]byte, error) {
+// PublicKeyToPem converts an RSA or ECDSA public key to pem string
+func PublicKeyToPem(key crypto.PublicKey) ([]byte, error) {
 	keyInBytes, err := x509.MarshalPKIXPublicKey(key)
-	logrus.Debugf("Failed to marshal PKIX public key: %s", err)
 	if err != nil {
+		logrus.Debugf("Failed to marshal PKIX public key: %s", err)
 		return nil, errors.Wrap(err, "failed to MarshalPKIXPublicKey")
 	}
+	blockType := "PUBLIC KEY"
+	if _, ok := key.(*rsa.PublicKey); ok {
+		blockType = "RSA PUBLIC KEY"
+	}
 	keyinPem := pem.EncodeToMemory(
 		&pem.Block{
-			Type:  "RSA PUBLIC KEY",
+			Type:  blockType,
 			Bytes: keyInBytes,
 		},
 	)
 	return keyinPem, nil
 }
 
-// PemToPrivateKey converts a data block to rsa.PrivateKey.
-func PemToPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+// PemToPrivateKey converts a data block to an RSA or ECDSA private key. The
+// key may be in PKCS #1, SEC 1 or PKCS #8 form.
+func PemToPrivateKey(data []byte) (crypto.Signer, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
 		return nil, errors.Errorf("could not find a PEM block in the private key")
 	}
-	return x509.ParsePKCS1PrivateKey(block.Bytes)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, errors.Errorf("unsupported private key type %T, expected RSA or ECDSA", key)
+		}
+	default:
+		return nil, errors.Errorf("unsupported PEM block type %q in the private key", block.Type)
+	}
 }
 
-// PemToPublicKey converts a data block to rsa.PublicKey.
-func PemToPublicKey(data []byte) (*rsa.PublicKey, error) {
+// PemToPublicKey converts a data block to an RSA or ECDSA public key.
+func PemToPublicKey(data []byte) (crypto.PublicKey, error) {
 	block, _ := pem.Decode(data)
 	if block == nil {
 		return nil, errors.Errorf("could not find a PEM block in the public key")
@@ -78,11 +118,14 @@ func PemToPublicKey(data []byte) (*rsa.PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	publicKey, ok := obji.(*rsa.PublicKey)
-	if !ok {
-		return nil, errors.Errorf("invalid public key format, expected RSA")
+	switch publicKey := obji.(type) {
+	case *rsa.PublicKey:
+		return publicKey, nil
+	case *ecdsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, errors.Errorf("invalid public key format, expected RSA or ECDSA")
 	}
-	return publicKey, nil
 }
 
 // PemToCertificate converts a data block to x509.Certificate.
@@ -93,3 +136,28 @@ func PemToCertificate(data []byte) (*x509.Certificate, error) {
 	}
 	return x509.ParseCertificate(block.Bytes)
 }
+
+// PemToCertificates converts the data blocks of a certificate chain to
+// x509.Certificates, in order.
+func PemToCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.Errorf("could not find a PEM block in the certificate")
+	}
+	return certs, nil
+}
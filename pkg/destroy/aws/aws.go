@@ -68,7 +68,8 @@ type ClusterUninstaller struct {
 
 	// Session is the AWS session to be used for deletion.  If nil, a
 	// new session will be created based on the usual credential
-	// configuration (AWS_PROFILE, AWS_ACCESS_KEY_ID, etc.).
+	// configuration (AWS_PROFILE, AWS_ACCESS_KEY_ID, etc.).  A session
+	// set by the caller is used as is, with the caller's user agent.
 	Session *session.Session
 }
 
@@ -86,6 +87,7 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	if err != nil {
 		return nil, err
 	}
+	addUserAgentHandler(session)
 
 	return &ClusterUninstaller{
 		Filters:       filters,
@@ -122,40 +124,11 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 		return nil, err
 	}
 
-	awsSession := o.Session
-	if awsSession == nil {
-		// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
-		awsSession, err = session.NewSession(aws.NewConfig().WithRegion(o.Region))
-		if err != nil {
-			return nil, err
-		}
-	}
-	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
-		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
-		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
-	})
-
-	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
-		resourcegroupstaggingapi.New(awsSession),
-	}
-
-	switch o.Region {
-	case endpoints.CnNorth1RegionID, endpoints.CnNorthwest1RegionID:
-		if o.Region != endpoints.CnNorthwest1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.CnNorthwest1RegionID)))
-		}
-	case endpoints.UsGovEast1RegionID, endpoints.UsGovWest1RegionID:
-		if o.Region != endpoints.UsGovWest1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsGovWest1RegionID)))
-		}
-	default:
-		if o.Region != endpoints.UsEast1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsEast1RegionID)))
-		}
+	awsSession, err := o.session()
+	if err != nil {
+		return nil, err
 	}
+	tagClients := o.tagClients(awsSession)
 
 	iamClient := iam.New(awsSession)
 	iamRoleSearch := &iamRoleSearch{
@@ -265,6 +238,55 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 	return nil, nil
 }
 
+// session returns the AWS session of the uninstaller, creating it on first use.
+func (o *ClusterUninstaller) session() (*session.Session, error) {
+	if o.Session == nil {
+		// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
+		awsSession, err := session.NewSession(aws.NewConfig().WithRegion(o.Region))
+		if err != nil {
+			return nil, err
+		}
+		addUserAgentHandler(awsSession)
+		o.Session = awsSession
+	}
+	return o.Session, nil
+}
+
+// addUserAgentHandler identifies the installer in the user agent of the requests of the session.
+func addUserAgentHandler(awsSession *session.Session) {
+	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
+		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
+	})
+}
+
+// tagClients returns the clients of the tagging API for the cluster region and for the region that holds the global
+// resources of its partition.
+func (o *ClusterUninstaller) tagClients(awsSession *session.Session) []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI {
+	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
+		resourcegroupstaggingapi.New(awsSession),
+	}
+
+	switch o.Region {
+	case endpoints.CnNorth1RegionID, endpoints.CnNorthwest1RegionID:
+		if o.Region != endpoints.CnNorthwest1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.CnNorthwest1RegionID)))
+		}
+	case endpoints.UsGovEast1RegionID, endpoints.UsGovWest1RegionID:
+		if o.Region != endpoints.UsGovWest1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsGovWest1RegionID)))
+		}
+	default:
+		if o.Region != endpoints.UsEast1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsEast1RegionID)))
+		}
+	}
+	return tagClients
+}
+
 // findEC2Instances returns the EC2 instances with tags that satisfy the filters.
 // returns two lists, first one is the list of all resources that are not terminated and are not in shutdown
 // stage and the second list is the list of resources that are not terminated.
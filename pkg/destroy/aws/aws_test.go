@@ -0,0 +1,19 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionAddsUserAgentOnce(t *testing.T) {
+	o := &ClusterUninstaller{Region: "us-east-1"}
+	first, err := o.session()
+	assert.NoError(t, err)
+	handlers := first.Handlers.Build.Len()
+
+	second, err := o.session()
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, handlers, second.Handlers.Build.Len())
+}
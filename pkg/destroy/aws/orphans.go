@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	awssession "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// clusterTagPrefix is the prefix of the kubernetes.io/cluster/<infraID> tag
+// that New filters resources with.
+const clusterTagPrefix = "kubernetes.io/cluster/"
+
+// ListOrphans lists the clusters with resources tagged as owned by them in
+// the region of the metadata.
+func ListOrphans(logger logrus.FieldLogger, metadata *types.ClusterMetadata) ([]providers.OrphanedCluster, error) {
+	region := metadata.ClusterPlatformMetadata.AWS.Region
+	session, err := awssession.GetSessionWithOptions(
+		awssession.WithRegion(region),
+		awssession.WithServiceEndpoints(region, metadata.ClusterPlatformMetadata.AWS.ServiceEndpoints),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	client := resourcegroupstaggingapi.New(session)
+	err = client.GetResourcesPagesWithContext(
+		context.TODO(),
+		&resourcegroupstaggingapi.GetResourcesInput{},
+		func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+			for _, mapping := range page.ResourceTagMappingList {
+				for _, tag := range mapping.Tags {
+					key := aws.StringValue(tag.Key)
+					if strings.HasPrefix(key, clusterTagPrefix) && aws.StringValue(tag.Value) == "owned" {
+						logger.WithField("arn", aws.StringValue(mapping.ResourceARN)).Debug("Found cluster resource")
+						counts[strings.TrimPrefix(key, clusterTagPrefix)]++
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tagged resources")
+	}
+	return providers.OrphansFromCounts(counts), nil
+}
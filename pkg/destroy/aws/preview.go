@@ -0,0 +1,291 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// hostedZoneKind is the kind of the Route 53 hosted zones in the preview.
+const hostedZoneKind = "route53:hostedzone"
+
+// Preview lists the resources that Run would delete or untag, without deleting or untagging them.
+func (o *ClusterUninstaller) Preview() ([]providers.Resource, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	awsSession, err := o.session()
+	if err != nil {
+		return nil, err
+	}
+	tagClients := o.tagClients(awsSession)
+	iamClient := iam.New(awsSession)
+
+	// matches maps the ARNs of the resources to the filter that matched them. The filters are searched one at a time
+	// so that each resource can be attributed to a filter.
+	matches := map[string]string{}
+	record := func(arns sets.String, match string) {
+		for _, arnString := range arns.List() {
+			if _, ok := matches[arnString]; !ok {
+				matches[arnString] = match
+			}
+		}
+	}
+	for _, filter := range o.Filters {
+		single := *o
+		single.Filters = []Filter{filter}
+		match := filterString(filter)
+		for _, tagClient := range tagClients {
+			arns, err := single.findResourcesByTag(ctx, tagClient, sets.NewString())
+			if err != nil {
+				return nil, err
+			}
+			record(arns, match)
+		}
+		roles, err := single.findIAMRoles(ctx, &iamRoleSearch{client: iamClient, filters: single.Filters, logger: o.Logger}, sets.NewString())
+		if err != nil {
+			return nil, err
+		}
+		record(roles, match)
+		users, err := single.findIAMUsers(ctx, &iamUserSearch{client: iamClient, filters: single.Filters, logger: o.Logger}, sets.NewString())
+		if err != nil {
+			return nil, err
+		}
+		record(users, match)
+	}
+	untaggable, err := o.findUntaggableResources(ctx, iamClient, sets.NewString())
+	if err != nil {
+		return nil, err
+	}
+	record(untaggable, fmt.Sprintf("name=%s-*", o.ClusterID))
+
+	resources := make([]providers.Resource, 0, len(matches))
+	ownedZones := map[string]string{}
+	for arnString, match := range matches {
+		resource, err := resourceFromARN(arnString)
+		if err != nil {
+			return nil, err
+		}
+		resource.MatchedTag = match
+		resources = append(resources, resource)
+		if resource.Kind == hostedZoneKind {
+			ownedZones[resource.ID] = match
+		}
+	}
+
+	shared, sharedZones, err := o.previewSharedTags(ctx, tagClients, iamClient)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, shared...)
+
+	recordSets, err := o.previewRecordSets(ctx, route53.New(awsSession), ownedZones, sharedZones)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, recordSets...)
+
+	providers.SortResources(resources)
+	return resources, nil
+}
+
+// previewSharedTags lists the resources from which Run would remove the shared tags of the cluster, like the
+// subnets of a pre-existing VPC. It also returns the shared hosted zones, by ID, with the tag that matched them.
+func (o *ClusterUninstaller) previewSharedTags(ctx context.Context, tagClients []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, iamClient *iam.IAM) ([]providers.Resource, map[string]string, error) {
+	const sharedValue = "shared"
+
+	var resources []providers.Resource
+	seen := sets.NewString()
+	zones := map[string]string{}
+	for _, key := range o.clusterOwnedKeys() {
+		match := key + "=" + sharedValue
+		for _, tagClient := range tagClients {
+			var parseErr error
+			err := tagClient.GetResourcesPagesWithContext(
+				ctx,
+				&resourcegroupstaggingapi.GetResourcesInput{TagFilters: []*resourcegroupstaggingapi.TagFilter{{
+					Key:    aws.String(key),
+					Values: []*string{aws.String(sharedValue)},
+				}}},
+				func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+					for _, mapping := range results.ResourceTagMappingList {
+						arnString := aws.StringValue(mapping.ResourceARN)
+						if seen.Has(arnString) {
+							continue
+						}
+						seen.Insert(arnString)
+						resource, err := resourceFromARN(arnString)
+						if err != nil {
+							parseErr = err
+							return false
+						}
+						resource.MatchedTag = match
+						resource.Action = providers.ActionUntag
+						resources = append(resources, resource)
+						if resource.Kind == hostedZoneKind {
+							zones[resource.ID] = match
+						}
+					}
+					return !lastPage
+				},
+			)
+			if err == nil {
+				err = parseErr
+			}
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "get shared resources")
+			}
+		}
+
+		search := &iamRoleSearch{client: iamClient, filters: []Filter{{key: sharedValue}}, logger: o.Logger}
+		_, roles, err := search.find(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "get shared IAM roles")
+		}
+		for _, role := range roles {
+			resources = append(resources, providers.Resource{Kind: "iam:role", ID: role, MatchedTag: match, Action: providers.ActionUntag})
+		}
+	}
+	return resources, zones, nil
+}
+
+// previewRecordSets lists the record sets that Run would delete outside of the hosted zones that it deletes: the
+// records of the owned private zones in the public zone of the base domain, and the records of the cluster in the
+// shared private zones and in the public zone. The zones are given by ID, with the tag that matched them.
+func (o *ClusterUninstaller) previewRecordSets(ctx context.Context, client *route53.Route53, ownedZones, sharedZones map[string]string) ([]providers.Resource, error) {
+	var resources []providers.Resource
+	add := func(zoneID string, recordSet *route53.ResourceRecordSet, match string) {
+		resources = append(resources, providers.Resource{
+			Kind:       "route53:recordset",
+			ID:         fmt.Sprintf("%s/%s %s", zoneID, aws.StringValue(recordSet.Name), aws.StringValue(recordSet.Type)),
+			MatchedTag: match,
+		})
+	}
+
+	for id, match := range ownedZones {
+		publicZoneID, err := getPublicHostedZone(ctx, client, id, o.Logger)
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == route53.ErrCodeNoSuchHostedZone {
+				continue
+			}
+			return nil, err
+		}
+		if publicZoneID == "" {
+			continue
+		}
+		public, err := listRecordSets(ctx, client, publicZoneID)
+		if err != nil {
+			return nil, err
+		}
+		private, err := listRecordSets(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		for key, recordSet := range private {
+			if recordType := aws.StringValue(recordSet.Type); recordType == "SOA" || recordType == "NS" {
+				continue
+			}
+			if publicRecordSet, ok := public[key]; ok {
+				add(publicZoneID, publicRecordSet, match)
+			}
+		}
+	}
+
+	if len(sharedZones) == 0 {
+		return resources, nil
+	}
+	if o.ClusterDomain == "" {
+		o.Logger.Debug("No cluster domain specified in metadata; cannot preview the records of the shared hosted zones")
+		return resources, nil
+	}
+	dottedClusterDomain := o.ClusterDomain + "."
+	publicZoneID, err := findAncestorPublicRoute53(ctx, client, dottedClusterDomain, o.Logger)
+	if err != nil {
+		return nil, err
+	}
+	var public map[string]*route53.ResourceRecordSet
+	if publicZoneID != "" {
+		if public, err = listRecordSets(ctx, client, publicZoneID); err != nil {
+			return nil, err
+		}
+	}
+	for id, match := range sharedZones {
+		private, err := listRecordSets(ctx, client, id)
+		if err != nil {
+			return nil, err
+		}
+		for key, recordSet := range private {
+			name := aws.StringValue(recordSet.Name)
+			if !strings.HasSuffix(name, dottedClusterDomain) || len(name) == len(dottedClusterDomain) {
+				continue
+			}
+			add(id, recordSet, match)
+			if publicRecordSet, ok := public[key]; ok {
+				add(publicZoneID, publicRecordSet, match)
+			}
+		}
+	}
+	return resources, nil
+}
+
+// listRecordSets lists the record sets of a hosted zone by type and name.
+func listRecordSets(ctx context.Context, client *route53.Route53, zoneID string) (map[string]*route53.ResourceRecordSet, error) {
+	recordSets := map[string]*route53.ResourceRecordSet{}
+	err := client.ListResourceRecordSetsPagesWithContext(
+		ctx,
+		&route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(zoneID)},
+		func(results *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+			for _, recordSet := range results.ResourceRecordSets {
+				recordSets[fmt.Sprintf("%s %s", aws.StringValue(recordSet.Type), aws.StringValue(recordSet.Name))] = recordSet
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list record sets of hosted zone %s", zoneID)
+	}
+	return recordSets, nil
+}
+
+// resourceFromARN converts an ARN into a resource record. The kind is the service followed by the resource type, if
+// the ARN has one.
+func resourceFromARN(arnString string) (providers.Resource, error) {
+	parsed, err := arn.Parse(arnString)
+	if err != nil {
+		return providers.Resource{}, errors.Wrapf(err, "parse ARN %s", arnString)
+	}
+	resource := providers.Resource{
+		Kind:   parsed.Service,
+		ID:     parsed.Resource,
+		Region: parsed.Region,
+	}
+	if i := strings.IndexAny(parsed.Resource, "/:"); i >= 0 {
+		resource.Kind = parsed.Service + ":" + parsed.Resource[:i]
+		resource.ID = parsed.Resource[i+1:]
+	}
+	return resource, nil
+}
+
+// filterString formats a filter as comma-separated key=value pairs, sorted by key.
+func filterString(filter Filter) string {
+	pairs := make([]string, 0, len(filter))
+	for key, value := range filter {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
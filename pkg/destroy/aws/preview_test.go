@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+func TestResourceFromARN(t *testing.T) {
+	cases := []struct {
+		arn      string
+		expected providers.Resource
+	}{
+		{
+			arn:      "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+			expected: providers.Resource{Kind: "ec2:instance", ID: "i-0123456789abcdef0", Region: "us-east-1"},
+		},
+		{
+			arn:      "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mycluster-abcde-ext/0123456789abcdef",
+			expected: providers.Resource{Kind: "elasticloadbalancing:loadbalancer", ID: "net/mycluster-abcde-ext/0123456789abcdef", Region: "us-east-1"},
+		},
+		{
+			arn:      "arn:aws:iam::123456789012:role/mycluster-abcde-master-role",
+			expected: providers.Resource{Kind: "iam:role", ID: "mycluster-abcde-master-role"},
+		},
+		{
+			arn:      "arn:aws:route53:::hostedzone/Z0123456789",
+			expected: providers.Resource{Kind: "route53:hostedzone", ID: "Z0123456789"},
+		},
+		{
+			arn:      "arn:aws:s3:::mycluster-abcde-image-registry",
+			expected: providers.Resource{Kind: "s3", ID: "mycluster-abcde-image-registry"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.arn, func(t *testing.T) {
+			actual, err := resourceFromARN(tc.arn)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := resourceFromARN("not-an-arn")
+	assert.Error(t, err)
+}
+
+func TestFilterString(t *testing.T) {
+	assert.Equal(t, "a=b,c=d", filterString(Filter{"c": "d", "a": "b"}))
+}
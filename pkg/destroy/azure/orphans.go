@@ -0,0 +1,85 @@
+package azure
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	azuresession "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+// clusterTagPrefix is the prefix of the kubernetes.io_cluster.<infraID> tag
+// that the installer puts on the resource groups of a cluster.
+const clusterTagPrefix = "kubernetes.io_cluster."
+
+// ListOrphans lists the clusters with resource groups tagged as owned by them
+// in the subscription.
+func ListOrphans(logger logrus.FieldLogger, metadata *types.ClusterMetadata) ([]providers.OrphanedCluster, error) {
+	cloudName := metadata.Azure.CloudName
+	if cloudName == "" {
+		cloudName = azure.PublicCloud
+	}
+	session, err := azuresession.GetSession(cloudName, metadata.Azure.ARMEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if metadata.Azure.Region != "" {
+		if err := validateRegion(ctx, azuresession.NewClient(session), metadata.Azure.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	client := resources.NewGroupsClientWithBaseURI(session.Environment.ResourceManagerEndpoint, session.Credentials.SubscriptionID)
+	client.Authorizer = session.Authorizer
+
+	counts := map[string]int{}
+	page, err := client.List(ctx, "", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list resource groups")
+	}
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list resource groups")
+		}
+		for _, group := range page.Values() {
+			if metadata.Azure.Region != "" && !strings.EqualFold(to.String(group.Location), metadata.Azure.Region) {
+				continue
+			}
+			for key, value := range group.Tags {
+				if strings.HasPrefix(key, clusterTagPrefix) && to.String(value) == "owned" {
+					logger.WithField("resourceGroup", to.String(group.Name)).Debug("Found cluster resource group")
+					counts[strings.TrimPrefix(key, clusterTagPrefix)]++
+				}
+			}
+		}
+	}
+	return providers.OrphansFromCounts(counts), nil
+}
+
+// validateRegion checks that the region is one of the locations of the
+// subscription, so that a mistyped region is not reported as a region without
+// clusters.
+func validateRegion(ctx context.Context, client azuresession.API, region string) error {
+	locations, err := client.ListLocations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the regions of the subscription")
+	}
+	for _, location := range *locations {
+		if to.String(location.Name) == region {
+			return nil
+		}
+	}
+	return errors.Errorf("region %q is not valid or not available for this subscription", region)
+}
@@ -6,4 +6,5 @@ import (
 
 func init() {
 	providers.Registry["azure"] = New
+	providers.OrphanRegistry["azure"] = ListOrphans
 }
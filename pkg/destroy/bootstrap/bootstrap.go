@@ -11,6 +11,7 @@ import (
 
 	"github.com/openshift/installer/pkg/asset/cluster"
 	osp "github.com/openshift/installer/pkg/destroy/openstack"
+	"github.com/openshift/installer/pkg/events"
 	platformstages "github.com/openshift/installer/pkg/terraform/stages/platform"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -76,9 +77,12 @@ func Destroy(dir string) (err error) {
 			extraArgs[i] = fmt.Sprintf("-var-file=%s", filepath.Join(tempDir, filename))
 		}
 
+		events.Emit(events.Event{Type: events.TerraformStageStarted, Stage: stage.Name(), Action: "destroy"})
 		if err := stage.Destroy(tempDir, extraArgs); err != nil {
+			events.Emit(events.Event{Type: events.TerraformStageFinished, Stage: stage.Name(), Action: "destroy", Error: err.Error()})
 			return err
 		}
+		events.Emit(events.Event{Type: events.TerraformStageFinished, Stage: stage.Name(), Action: "destroy"})
 
 		tempStateFilePath := filepath.Join(dir, stage.StateFilename()+".new")
 		err = copy(filepath.Join(tempDir, stage.StateFilename()), tempStateFilePath)
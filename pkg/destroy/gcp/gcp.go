@@ -75,6 +75,24 @@ func (o *ClusterUninstaller) Run() error {
 	ctx, cancel := o.contextWithTimeout()
 	defer cancel()
 
+	if err := o.initServices(ctx); err != nil {
+		return err
+	}
+
+	err := wait.PollImmediateInfinite(
+		time.Second*10,
+		o.destroyCluster,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to destroy cluster")
+	}
+
+	return nil
+
+}
+
+// initServices creates the clients of the GCP services used by the uninstaller.
+func (o *ClusterUninstaller) initServices(ctx context.Context) error {
 	ssn, err := gcpconfig.GetSession(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get session")
@@ -109,17 +127,7 @@ func (o *ClusterUninstaller) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to create resourcemanager service")
 	}
-
-	err = wait.PollImmediateInfinite(
-		time.Second*10,
-		o.destroyCluster,
-	)
-	if err != nil {
-		return errors.Wrap(err, "failed to destroy cluster")
-	}
-
 	return nil
-
 }
 
 func (o *ClusterUninstaller) destroyCluster() (bool, error) {
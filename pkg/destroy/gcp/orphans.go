@@ -0,0 +1,87 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/version"
+)
+
+// clusterLabelPrefix is the prefix of the kubernetes-io-cluster-<infraID>
+// label that clusterLabelFilter matches.
+const clusterLabelPrefix = "kubernetes-io-cluster-"
+
+// ListOrphans lists the clusters with instances or disks labeled as owned by
+// them in the project of the metadata. When the metadata has a region, only
+// the zones of that region are considered.
+func ListOrphans(logger logrus.FieldLogger, metadata *types.ClusterMetadata) ([]providers.OrphanedCluster, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*defaultTimeout)
+	defer cancel()
+
+	ssn, err := gcpconfig.GetSession(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+	computeSvc, err := compute.NewService(ctx,
+		option.WithCredentials(ssn.Credentials),
+		option.WithUserAgent(fmt.Sprintf("OpenShift/4.x Destroyer/%s", version.Raw)),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create compute service")
+	}
+
+	projectID := metadata.ClusterPlatformMetadata.GCP.ProjectID
+	region := metadata.ClusterPlatformMetadata.GCP.Region
+	counts := map[string]int{}
+	count := func(kind, name, zone string, labels map[string]string) {
+		if region != "" && !strings.HasPrefix(zone[strings.LastIndex(zone, "/")+1:], region+"-") {
+			return
+		}
+		for key, value := range labels {
+			if strings.HasPrefix(key, clusterLabelPrefix) && value == "owned" {
+				logger.Debugf("Found cluster %s %s", kind, name)
+				counts[strings.TrimPrefix(key, clusterLabelPrefix)]++
+			}
+		}
+	}
+
+	err = computeSvc.Instances.AggregatedList(projectID).
+		Fields(googleapi.Field("items/*/instances(name,zone,labels),nextPageToken")).
+		Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+			for _, scopedList := range list.Items {
+				for _, item := range scopedList.Instances {
+					count("instance", item.Name, item.Zone, item.Labels)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list instances")
+	}
+
+	err = computeSvc.Disks.AggregatedList(projectID).
+		Fields(googleapi.Field("items/*/disks(name,zone,labels),nextPageToken")).
+		Pages(ctx, func(list *compute.DiskAggregatedList) error {
+			for _, scopedList := range list.Items {
+				for _, item := range scopedList.Disks {
+					count("disk", item.Name, item.Zone, item.Labels)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list disks")
+	}
+
+	return providers.OrphansFromCounts(counts), nil
+}
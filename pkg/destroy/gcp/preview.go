@@ -0,0 +1,91 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Preview lists the resources that Run would delete, without deleting them.
+func (o *ClusterUninstaller) Preview() ([]providers.Resource, error) {
+	ctx, cancel := o.contextWithTimeout()
+	defer cancel()
+
+	if err := o.initServices(ctx); err != nil {
+		return nil, err
+	}
+
+	// The cloud controller resources are found through the naming conventions of the cloud controller rather than
+	// the infrastructure ID, so they are recorded separately.
+	if err := o.discoverCloudControllerResources(); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var resources []providers.Resource
+	add := func(items []cloudResource, match string) {
+		for _, item := range items {
+			key := item.typeName + "/" + item.key
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			resource := providers.Resource{
+				Kind:       item.typeName,
+				ID:         item.name,
+				Region:     item.zone,
+				MatchedTag: match,
+			}
+			if resource.MatchedTag == "" {
+				resource.MatchedTag = o.matchedTag(item)
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	listers := []func() ([]cloudResource, error){
+		o.listInstances,
+		o.listDisks,
+		o.listServiceAccounts,
+		o.listImages,
+		o.listBuckets,
+		o.listRoutes,
+		o.listFirewalls,
+		o.listAddresses,
+		o.listTargetPools,
+		o.listInstanceGroups,
+		o.listForwardingRules,
+		o.listBackendServices,
+		o.listHealthChecks,
+		o.listHTTPHealthChecks,
+		o.listRouters,
+		o.listSubnetworks,
+		o.listNetworks,
+	}
+	for _, list := range listers {
+		items, err := list()
+		if err != nil {
+			return nil, err
+		}
+		add(items, "")
+	}
+	add(o.GetAllPendingItems(), fmt.Sprintf("cloud-controller-uid=%s", o.cloudControllerUID))
+
+	private, _, err := o.listDNSZones()
+	if err != nil {
+		return nil, err
+	}
+	if private != nil {
+		add([]cloudResource{{key: private.name, name: private.name, typeName: "dnszone"}}, "")
+	}
+
+	providers.SortResources(resources)
+	return resources, nil
+}
+
+// matchedTag returns the label or name prefix through which a resource was attributed to the cluster.
+func (o *ClusterUninstaller) matchedTag(item cloudResource) string {
+	if o.isClusterResource(item.name) || o.isClusterResource(item.url) {
+		return fmt.Sprintf("name=%s-*", o.ClusterID)
+	}
+	return fmt.Sprintf("kubernetes-io-cluster-%s=owned", o.ClusterID)
+}
@@ -4,4 +4,5 @@ import "github.com/openshift/installer/pkg/destroy/providers"
 
 func init() {
 	providers.Registry["gcp"] = New
+	providers.OrphanRegistry["gcp"] = ListOrphans
 }
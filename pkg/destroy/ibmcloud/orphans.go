@@ -0,0 +1,104 @@
+package ibmcloud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/platform-services-go-sdk/globaltaggingv1"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// clusterTagPrefix and clusterTagSuffix surround the infrastructure ID
+	// in the kubernetes.io_cluster_<infraID>:owned tag that the installer
+	// puts on the resources of a cluster.
+	clusterTagPrefix = "kubernetes.io_cluster_"
+	clusterTagSuffix = ":owned"
+)
+
+// ListOrphans lists the clusters with VPCs tagged as owned by them in the
+// region of the metadata.
+func ListOrphans(logger logrus.FieldLogger, metadata *types.ClusterMetadata) ([]providers.OrphanedCluster, error) {
+	authenticator := &core.IamAuthenticator{
+		ApiKey: os.Getenv("IC_API_KEY"),
+	}
+	if err := authenticator.Validate(); err != nil {
+		return nil, err
+	}
+
+	vpcSvc, err := vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
+		Authenticator: authenticator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	taggingSvc, err := globaltaggingv1.NewGlobalTaggingV1(&globaltaggingv1.GlobalTaggingV1Options{
+		Authenticator: authenticator,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	region, _, err := vpcSvc.GetRegionWithContext(ctx, vpcSvc.NewGetRegionOptions(metadata.ClusterPlatformMetadata.IBMCloud.Region))
+	if err != nil {
+		return nil, err
+	}
+	if err := vpcSvc.SetServiceURL(fmt.Sprintf("%s/v1", *region.Endpoint)); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	options := vpcSvc.NewListVpcsOptions()
+	for {
+		resources, _, err := vpcSvc.ListVpcsWithContext(ctx, options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list vpcs")
+		}
+		for _, vpc := range resources.Vpcs {
+			tags, _, err := taggingSvc.ListTagsWithContext(ctx, taggingSvc.NewListTagsOptions().SetAttachedTo(*vpc.CRN))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list the tags of vpc %q", *vpc.Name)
+			}
+			if infraID := clusterFromTags(tags.Items); infraID != "" {
+				logger.Debugf("Found cluster VPC %q", *vpc.Name)
+				counts[infraID]++
+			}
+		}
+
+		if resources.Next == nil {
+			break
+		}
+		start, err := core.GetQueryParam(resources.Next.Href, "start")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the next page of vpcs")
+		}
+		if start == nil {
+			break
+		}
+		options.SetStart(*start)
+	}
+	return providers.OrphansFromCounts(counts), nil
+}
+
+// clusterFromTags returns the infrastructure ID of the cluster that owns a
+// resource with the given tags, or an empty string.
+func clusterFromTags(tags []globaltaggingv1.Tag) string {
+	for _, tag := range tags {
+		name := core.StringNilMapper(tag.Name)
+		if strings.HasPrefix(name, clusterTagPrefix) && strings.HasSuffix(name, clusterTagSuffix) {
+			return strings.TrimSuffix(strings.TrimPrefix(name, clusterTagPrefix), clusterTagSuffix)
+		}
+	}
+	return ""
+}
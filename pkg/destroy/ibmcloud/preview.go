@@ -0,0 +1,63 @@
+package ibmcloud
+
+import (
+	"fmt"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Preview lists the resources that Run would delete, without deleting them.
+func (o *ClusterUninstaller) Preview() ([]providers.Resource, error) {
+	err := o.loadSDKServices()
+	if err != nil {
+		return nil, err
+	}
+
+	// The VPC resources are regional, the others are global to the account. The listers are skipped in the same
+	// cases as the corresponding destroy functions, so that user-provided resources are not reported.
+	listers := []struct {
+		list     func() (cloudResources, error)
+		regional bool
+		skip     bool
+	}{
+		{list: o.listInstances, regional: true},
+		{list: o.listIAMAuthorizations},
+		{list: o.listLoadBalancers, regional: true},
+		{list: o.listSubnets, regional: true, skip: len(o.UserProvidedSubnets) > 0},
+		{list: o.listImages, regional: true},
+		{list: o.listPublicGateways, regional: true, skip: len(o.UserProvidedSubnets) > 0},
+		{list: o.listSecurityGroups, regional: true, skip: o.UserProvidedVPC == ""},
+		{list: o.listFloatingIPs, regional: true},
+		{list: o.listVPCs, regional: true, skip: o.UserProvidedVPC != ""},
+		{list: o.listCOSInstances},
+		{list: o.listDNSRecords},
+		{list: o.listResourceGroups, skip: o.ResourceGroupName != o.InfraID},
+	}
+
+	var resources []providers.Resource
+	for _, lister := range listers {
+		if lister.skip {
+			continue
+		}
+		found, err := lister.list()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range found.list() {
+			resource := providers.Resource{
+				Kind:       item.typeName,
+				ID:         item.name,
+				MatchedTag: fmt.Sprintf("name=*%s*", o.InfraID),
+			}
+			if resource.ID == "" {
+				resource.ID = item.id
+			}
+			if lister.regional {
+				resource.Region = o.Region
+			}
+			resources = append(resources, resource)
+		}
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
@@ -0,0 +1,228 @@
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	icibmcloud "github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	azurevalidation "github.com/openshift/installer/pkg/types/azure/validation"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+)
+
+// ClusterIdentity identifies a cluster, or the place to look for clusters,
+// without the metadata.json of the cluster.
+type ClusterIdentity struct {
+	// InfraID is the infrastructure ID of the cluster. It is empty when
+	// listing orphaned clusters.
+	InfraID string
+	// ClusterName is the name of the cluster. It defaults to the
+	// infrastructure ID without its random suffix, when the name was not
+	// truncated to build the infrastructure ID.
+	ClusterName string
+	// Platform is the name of the platform of the cluster.
+	Platform string
+	// Region is the region of the cluster.
+	Region string
+	// CloudName is the Azure cloud environment of the cluster. It defaults
+	// to the public cloud.
+	CloudName string
+	// ARMEndpoint is the Azure Resource Manager endpoint of an Azure Stack
+	// Hub cluster.
+	ARMEndpoint string
+	// ProjectID is the GCP project of the cluster.
+	ProjectID string
+	// BaseDomain is the base domain of the cluster. It is used to remove
+	// the DNS records of the cluster.
+	BaseDomain string
+	// ResourceGroup is the Azure or IBM Cloud resource group of the
+	// cluster. It defaults to the one created by the installer.
+	ResourceGroup string
+	// BaseDomainResourceGroup is the Azure resource group of the base
+	// domain.
+	BaseDomainResourceGroup string
+}
+
+// Metadata synthesizes the metadata of a cluster from its identity, the way
+// the installer would have recorded it in metadata.json.
+func Metadata(id ClusterIdentity) (*types.ClusterMetadata, error) {
+	metadata := &types.ClusterMetadata{
+		ClusterName: id.ClusterName,
+		InfraID:     id.InfraID,
+	}
+	if metadata.ClusterName == "" && id.InfraID != "" {
+		name, ok := clusterNameFromInfraID(id.InfraID)
+		if !ok && (id.BaseDomain != "" || id.BaseDomainResourceGroup != "") {
+			return nil, errors.Errorf("the cluster name cannot be derived from the infrastructure ID %q, which may have been truncated; a cluster name is required to remove the DNS records of the cluster", id.InfraID)
+		}
+		metadata.ClusterName = name
+	}
+
+	switch id.Platform {
+	case awstypes.Name:
+		if id.Region == "" {
+			return nil, errors.New("a region is required for AWS")
+		}
+		metadata.AWS = &awstypes.Metadata{Region: id.Region}
+		if id.InfraID != "" {
+			metadata.AWS.Identifier = []map[string]string{{
+				fmt.Sprintf("kubernetes.io/cluster/%s", id.InfraID): "owned",
+			}}
+		}
+		if id.BaseDomain != "" {
+			metadata.AWS.ClusterDomain = fmt.Sprintf("%s.%s", metadata.ClusterName, strings.TrimSuffix(id.BaseDomain, "."))
+		}
+	case azuretypes.Name:
+		cloudName := azuretypes.CloudEnvironment(id.CloudName)
+		if cloudName == "" {
+			cloudName = azuretypes.PublicCloud
+		}
+		if err := azurevalidation.ValidateCloudName(cloudName, field.NewPath("cloudName")).ToAggregate(); err != nil {
+			return nil, err
+		}
+		if cloudName == azuretypes.StackCloud && id.ARMEndpoint == "" {
+			return nil, errors.New("an ARM endpoint is required for Azure Stack Hub")
+		}
+		if id.Region != "" && !azureRegionPattern.MatchString(id.Region) {
+			return nil, errors.Errorf("invalid Azure region %q; must be the name of the region (e.g. \"centralus\"), not its display name", id.Region)
+		}
+		metadata.Azure = &azuretypes.Metadata{
+			CloudName:                   cloudName,
+			ARMEndpoint:                 id.ARMEndpoint,
+			Region:                      id.Region,
+			ResourceGroupName:           id.ResourceGroup,
+			BaseDomainResourceGroupName: id.BaseDomainResourceGroup,
+			ClusterName:                 metadata.ClusterName,
+		}
+	case gcptypes.Name:
+		if id.ProjectID == "" {
+			return nil, errors.New("a project ID is required for GCP")
+		}
+		metadata.GCP = &gcptypes.Metadata{Region: id.Region, ProjectID: id.ProjectID}
+	case ibmcloudtypes.Name:
+		if id.Region == "" {
+			return nil, errors.New("a region is required for IBM Cloud")
+		}
+		metadata.IBMCloud = &ibmcloudtypes.Metadata{
+			BaseDomain:        id.BaseDomain,
+			Region:            id.Region,
+			ResourceGroupName: id.ResourceGroup,
+		}
+		if metadata.IBMCloud.ResourceGroupName == "" {
+			metadata.IBMCloud.ResourceGroupName = id.InfraID
+		}
+		if id.BaseDomain != "" {
+			meta := icibmcloud.NewMetadata(id.BaseDomain)
+			accountID, err := meta.AccountID(context.TODO())
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to look up the IBM Cloud account")
+			}
+			cisCRN, err := meta.CISInstanceCRN(context.TODO())
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to look up the IBM Cloud CIS instance of the base domain")
+			}
+			metadata.IBMCloud.AccountID = accountID
+			metadata.IBMCloud.CISInstanceCRN = cisCRN
+		}
+	case "":
+		return nil, errors.New("a platform is required")
+	default:
+		return nil, errors.Errorf("destroying clusters without metadata is not supported on %q; must be one of %s", id.Platform, strings.Join(OfflinePlatforms, ", "))
+	}
+	return metadata, nil
+}
+
+// OfflinePlatforms are the platforms on which clusters can be destroyed
+// without their metadata.json.
+var OfflinePlatforms = []string{awstypes.Name, azuretypes.Name, gcptypes.Name, ibmcloudtypes.Name}
+
+// maxInfraIDBaseLen is the length to which the installer truncates the
+// cluster name when it generates the infrastructure ID.
+const maxInfraIDBaseLen = 21
+
+// azureRegionPattern matches the names of Azure regions, as opposed to their
+// display names.
+var azureRegionPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// clusterNameFromInfraID strips the random suffix from an infrastructure ID.
+// It returns false when the name may have been truncated to build the
+// infrastructure ID, in which case the result is only a prefix of the name.
+func clusterNameFromInfraID(infraID string) (string, bool) {
+	i := strings.LastIndex(infraID, "-")
+	if i <= 0 {
+		return infraID, false
+	}
+	return infraID[:i], i < maxInfraIDBaseLen
+}
+
+// NewOffline returns a Destroyer for the cluster with the given identity.
+func NewOffline(logger logrus.FieldLogger, id ClusterIdentity) (providers.Destroyer, error) {
+	if id.InfraID == "" {
+		return nil, errors.New("an infrastructure ID is required")
+	}
+	metadata, err := Metadata(id)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromMetadata(logger, metadata)
+}
+
+// ListOrphans lists the clusters that have resources in the region (or
+// project) of the identity. The clusters recorded in the metadata.json of
+// any of the asset directories still have their assets, so they are not
+// orphaned and are left out.
+func ListOrphans(logger logrus.FieldLogger, id ClusterIdentity, assetDirs []string) ([]providers.OrphanedCluster, error) {
+	id.InfraID = ""
+	metadata, err := Metadata(id)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := providers.OrphanRegistry[id.Platform]
+	if !ok {
+		return nil, errors.Errorf("no orphan listers registered for %q", id.Platform)
+	}
+	orphans, err := lister(logger, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return excludeClusters(logger, orphans, assetDirs)
+}
+
+// excludeClusters removes the clusters recorded in the metadata.json of the
+// asset directories from orphans. Directories without a metadata.json are
+// skipped.
+func excludeClusters(logger logrus.FieldLogger, orphans []providers.OrphanedCluster, assetDirs []string) ([]providers.OrphanedCluster, error) {
+	known := make(map[string]string, len(assetDirs))
+	for _, dir := range assetDirs {
+		metadata, err := cluster.LoadMetadata(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to load the metadata of the cluster in %s", dir)
+		}
+		known[metadata.InfraID] = dir
+	}
+
+	filtered := make([]providers.OrphanedCluster, 0, len(orphans))
+	for _, orphan := range orphans {
+		if dir, ok := known[orphan.InfraID]; ok {
+			logger.Debugf("Skipping cluster %s, which has its assets in %s", orphan.InfraID, dir)
+			continue
+		}
+		filtered = append(filtered, orphan)
+	}
+	return filtered, nil
+}
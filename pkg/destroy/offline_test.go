@@ -0,0 +1,177 @@
+package destroy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+)
+
+func TestMetadata(t *testing.T) {
+	cases := []struct {
+		name          string
+		identity      ClusterIdentity
+		expected      *types.ClusterMetadata
+		expectedError string
+	}{
+		{
+			name:     "aws",
+			identity: ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "aws", Region: "us-east-1", BaseDomain: "example.com."},
+			expected: &types.ClusterMetadata{
+				ClusterName: "test-cluster",
+				InfraID:     "test-cluster-x7k2p",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{
+						Region:        "us-east-1",
+						Identifier:    []map[string]string{{"kubernetes.io/cluster/test-cluster-x7k2p": "owned"}},
+						ClusterDomain: "test-cluster.example.com",
+					},
+				},
+			},
+		},
+		{
+			name:     "aws without infra ID",
+			identity: ClusterIdentity{Platform: "aws", Region: "us-east-1"},
+			expected: &types.ClusterMetadata{
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{Region: "us-east-1"},
+				},
+			},
+		},
+		{
+			name:          "aws without region",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "aws"},
+			expectedError: `^a region is required for AWS$`,
+		},
+		{
+			name:     "azure",
+			identity: ClusterIdentity{InfraID: "test-cluster-x7k2p", ClusterName: "test-cluster-long-name", Platform: "azure", BaseDomainResourceGroup: "dns-rg"},
+			expected: &types.ClusterMetadata{
+				ClusterName: "test-cluster-long-name",
+				InfraID:     "test-cluster-x7k2p",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					Azure: &azuretypes.Metadata{
+						CloudName:                   azuretypes.PublicCloud,
+						BaseDomainResourceGroupName: "dns-rg",
+						ClusterName:                 "test-cluster-long-name",
+					},
+				},
+			},
+		},
+		{
+			name:          "aws with truncated infra ID",
+			identity:      ClusterIdentity{InfraID: "a-very-long-cluster-n-x7k2p", Platform: "aws", Region: "us-east-1", BaseDomain: "example.com"},
+			expectedError: `^the cluster name cannot be derived from the infrastructure ID "a-very-long-cluster-n-x7k2p", which may have been truncated; a cluster name is required to remove the DNS records of the cluster$`,
+		},
+		{
+			name:     "aws with truncated infra ID and cluster name",
+			identity: ClusterIdentity{InfraID: "a-very-long-cluster-n-x7k2p", ClusterName: "a-very-long-cluster-name", Platform: "aws", Region: "us-east-1", BaseDomain: "example.com"},
+			expected: &types.ClusterMetadata{
+				ClusterName: "a-very-long-cluster-name",
+				InfraID:     "a-very-long-cluster-n-x7k2p",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					AWS: &awstypes.Metadata{
+						Region:        "us-east-1",
+						Identifier:    []map[string]string{{"kubernetes.io/cluster/a-very-long-cluster-n-x7k2p": "owned"}},
+						ClusterDomain: "a-very-long-cluster-name.example.com",
+					},
+				},
+			},
+		},
+		{
+			name:     "azure government cloud",
+			identity: ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "azure", CloudName: "AzureUSGovernmentCloud", Region: "usgovvirginia"},
+			expected: &types.ClusterMetadata{
+				ClusterName: "test-cluster",
+				InfraID:     "test-cluster-x7k2p",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					Azure: &azuretypes.Metadata{
+						CloudName:   azuretypes.USGovernmentCloud,
+						Region:      "usgovvirginia",
+						ClusterName: "test-cluster",
+					},
+				},
+			},
+		},
+		{
+			name:          "azure with invalid cloud name",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "azure", CloudName: "AzureMoonCloud"},
+			expectedError: `^cloudName: Unsupported value: "AzureMoonCloud": supported values: `,
+		},
+		{
+			name:          "azure stack without ARM endpoint",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "azure", CloudName: "AzureStackCloud"},
+			expectedError: `^an ARM endpoint is required for Azure Stack Hub$`,
+		},
+		{
+			name:          "azure with region display name",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "azure", Region: "Central US"},
+			expectedError: `^invalid Azure region "Central US"; must be the name of the region \(e.g. "centralus"\), not its display name$`,
+		},
+		{
+			name:     "gcp",
+			identity: ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "gcp", Region: "us-central1", ProjectID: "my-project"},
+			expected: &types.ClusterMetadata{
+				ClusterName: "test-cluster",
+				InfraID:     "test-cluster-x7k2p",
+				ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+					GCP: &gcptypes.Metadata{Region: "us-central1", ProjectID: "my-project"},
+				},
+			},
+		},
+		{
+			name:          "gcp without project",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "gcp", Region: "us-central1"},
+			expectedError: `^a project ID is required for GCP$`,
+		},
+		{
+			name:          "unsupported platform",
+			identity:      ClusterIdentity{InfraID: "test-cluster-x7k2p", Platform: "openstack"},
+			expectedError: `^destroying clusters without metadata is not supported on "openstack"; must be one of aws, azure, gcp, ibmcloud$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := Metadata(tc.identity)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestExcludeClusters(t *testing.T) {
+	live, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(live)
+	if err := ioutil.WriteFile(filepath.Join(live, "metadata.json"), []byte(`{"clusterName":"live","infraID":"live-a1b2c"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	empty, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(empty)
+
+	orphans := []providers.OrphanedCluster{
+		{InfraID: "lost-x7k2p", Resources: 3},
+		{InfraID: "live-a1b2c", Resources: 12},
+	}
+	actual, err := excludeClusters(logrus.StandardLogger(), orphans, []string{live, empty})
+	assert.NoError(t, err)
+	assert.Equal(t, []providers.OrphanedCluster{{InfraID: "lost-x7k2p", Resources: 3}}, actual)
+}
@@ -2,3 +2,7 @@ package providers
 
 // Registry maps ClusterMetadata.Platform() to per-platform Destroyer creators.
 var Registry = make(map[string]NewFunc)
+
+// OrphanRegistry maps ClusterMetadata.Platform() to per-platform orphan
+// listers.
+var OrphanRegistry = make(map[string]ListOrphansFunc)
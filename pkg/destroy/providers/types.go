@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"sort"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/types"
@@ -12,5 +14,77 @@ type Destroyer interface {
 	Run() error
 }
 
+// Actions that a destroyer takes on the resources of a cluster.
+const (
+	// ActionDelete deletes the resource.
+	ActionDelete = "delete"
+	// ActionUntag removes the tags of the cluster from a resource shared
+	// with other clusters, and leaves the resource in place.
+	ActionUntag = "untag"
+)
+
+// Resource is a resource that a destroyer would delete or untag.
+type Resource struct {
+	// Kind is the platform-specific type of the resource, for example
+	// "ec2:instance" on AWS or "forwardingrule" on GCP.
+	Kind string `json:"kind"`
+	// ID identifies the resource within its kind.
+	ID string `json:"id"`
+	// Region is the region (or zone) of the resource. It is empty for
+	// global resources.
+	Region string `json:"region,omitempty"`
+	// MatchedTag is the tag, label or naming convention through which the
+	// resource was attributed to the cluster.
+	MatchedTag string `json:"matchedTag,omitempty"`
+	// Action is what the destroyer would do to the resource. It is empty
+	// for resources that would be deleted.
+	Action string `json:"action,omitempty"`
+}
+
+// Previewer is implemented by destroyers that can list the resources they
+// would delete without deleting anything.
+type Previewer interface {
+	Preview() ([]Resource, error)
+}
+
+// SortResources sorts resources by kind, region and ID.
+func SortResources(resources []Resource) {
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ID < b.ID
+	})
+}
+
 // NewFunc is an interface for creating platform-specific destroyers.
 type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error)
+
+// OrphanedCluster is a cluster found through the tags or labels that the
+// installer puts on the resources it creates.
+type OrphanedCluster struct {
+	// InfraID is the infrastructure ID of the cluster.
+	InfraID string `json:"infraID"`
+	// Resources is the number of resources found for the cluster.
+	Resources int `json:"resources"`
+}
+
+// ListOrphansFunc is an interface for listing the clusters that have
+// resources in the region (or project) of the metadata. The metadata has no
+// infrastructure ID.
+type ListOrphansFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) ([]OrphanedCluster, error)
+
+// OrphansFromCounts returns the clusters for the given resource counts by
+// infrastructure ID, sorted by infrastructure ID.
+func OrphansFromCounts(counts map[string]int) []OrphanedCluster {
+	orphans := make([]OrphanedCluster, 0, len(counts))
+	for infraID, resources := range counts {
+		orphans = append(orphans, OrphanedCluster{InfraID: infraID, Resources: resources})
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].InfraID < orphans[j].InfraID })
+	return orphans
+}
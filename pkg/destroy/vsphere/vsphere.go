@@ -31,9 +31,20 @@ type ClusterUninstaller struct {
 	Client     *vim25.Client
 	RestClient *rest.Client
 
+	// VCenters are the clients of the additional vCenters of the
+	// failure domains.
+	VCenters []VCenterClients
+
 	Logger logrus.FieldLogger
 }
 
+// VCenterClients are the clients of an additional vCenter.
+type VCenterClients struct {
+	Server     string
+	Client     *vim25.Client
+	RestClient *rest.Client
+}
+
 // New returns an VSphere destroyer from ClusterMetadata.
 func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (providers.Destroyer, error) {
 
@@ -46,11 +57,28 @@ func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (p
 		return nil, err
 	}
 
+	var vcenters []VCenterClients
+	for _, vcenter := range metadata.ClusterPlatformMetadata.VSphere.VCenters {
+		vcenterClient, vcenterRestClient, err := vspheretypes.CreateVSphereClients(context.TODO(),
+			vcenter.Server,
+			vcenter.Username,
+			vcenter.Password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to connect to vCenter %s", vcenter.Server)
+		}
+		vcenters = append(vcenters, VCenterClients{
+			Server:     vcenter.Server,
+			Client:     vcenterClient,
+			RestClient: vcenterRestClient,
+		})
+	}
+
 	return &ClusterUninstaller{
 		ClusterID:  metadata.ClusterID,
 		InfraID:    metadata.InfraID,
 		Client:     vim25Client,
 		RestClient: restClient,
+		VCenters:   vcenters,
 		Logger:     logger,
 	}, nil
 }
@@ -86,19 +114,20 @@ func deleteFolder(ctx context.Context, client *vim25.Client, folderMoList []mo.F
 	ctx, cancel := context.WithTimeout(ctx, time.Second*60)
 	defer cancel()
 
-	// If there are no children in the folder go ahead an remove it
-	if len(folderMoList[0].ChildEntity) == 0 {
-		folderLogger := logger.WithField("Folder", folderMoList[0].Name)
+	for _, folderMo := range folderMoList {
+		// If there are no children in the folder go ahead an remove it
+		if len(folderMo.ChildEntity) != 0 {
+			return errors.Errorf("Expected Folder %s to be empty", folderMo.Name)
+		}
+		folderLogger := logger.WithField("Folder", folderMo.Name)
 
-		folder := object.NewFolder(client, folderMoList[0].Reference())
+		folder := object.NewFolder(client, folderMo.Reference())
 		task, err := folder.Destroy(ctx)
 		if err != nil {
 			return err
 		}
 		task.Wait(ctx)
 		folderLogger.Info("Destroyed")
-	} else {
-		return errors.Errorf("Expected Folder %s to be empty", folderMoList[0].Name)
 	}
 
 	return nil
@@ -198,11 +227,31 @@ func deleteTagCategory(ctx context.Context, client *rest.Client, categoryID stri
 
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() error {
+	// The machine API creates the machines of the failure domains of the
+	// additional vCenters, in the tag category created by the installer
+	// before terraform. The vCenters without it have nothing to delete.
+	// They are cleaned up first, so that a failure leaves the tag of the
+	// vCenter of the platform, and the metadata, to try again.
+	for _, vcenter := range o.VCenters {
+		logger := o.Logger.WithField("vCenter", vcenter.Server)
+		if _, err := tags.NewManager(vcenter.RestClient).GetCategory(context.TODO(), "openshift-"+o.InfraID); err != nil {
+			logger.Debug("No tag category found")
+			continue
+		}
+		if err := o.destroyVCenter(vcenter.Client, vcenter.RestClient, logger); err != nil {
+			return err
+		}
+	}
+	return o.destroyVCenter(o.Client, o.RestClient, o.Logger)
+}
+
+// destroyVCenter deletes the objects of the cluster in a vCenter.
+func (o *ClusterUninstaller) destroyVCenter(client *vim25.Client, restClient *rest.Client, logger logrus.FieldLogger) error {
 	var folderList []types.ManagedObjectReference
 	var virtualMachineList []types.ManagedObjectReference
 
-	o.Logger.Debug("Find attached objects on tag")
-	tagAttachedObjects, err := getAttachedObjectsOnTag(context.TODO(), o.RestClient, o.InfraID)
+	logger.Debug("Find attached objects on tag")
+	tagAttachedObjects, err := getAttachedObjectsOnTag(context.TODO(), restClient, o.InfraID)
 	if err != nil {
 		return err
 	}
@@ -220,62 +269,55 @@ func (o *ClusterUninstaller) Run() error {
 		}
 	}
 
-	// The installer should create at most one parent,
-	// the parent to the VirtualMachines.
-	// If there are more or less fail with error message.
-	if len(folderList) > 1 {
-		return errors.Errorf("Expected 1 Folder per tag but got %d", len(folderList))
-	}
-
 	if len(virtualMachineList) > 0 {
-		o.Logger.Debug("Find VirtualMachine objects")
-		virtualMachineMoList, err := getVirtualMachineManagedObjects(context.TODO(), o.Client, virtualMachineList)
+		logger.Debug("Find VirtualMachine objects")
+		virtualMachineMoList, err := getVirtualMachineManagedObjects(context.TODO(), client, virtualMachineList)
 		if err != nil {
 			return err
 		}
-		o.Logger.Debug("Delete VirtualMachines")
-		err = deleteVirtualMachines(context.TODO(), o.Client, virtualMachineMoList, o.Logger)
+		logger.Debug("Delete VirtualMachines")
+		err = deleteVirtualMachines(context.TODO(), client, virtualMachineMoList, logger)
 		if err != nil {
 			return err
 		}
 	} else {
-		o.Logger.Debug("No VirtualMachines found")
+		logger.Debug("No VirtualMachines found")
 	}
 
 	if len(folderList) > 0 {
-		o.Logger.Debug("Find Folder objects")
-		folderMoList, err := getFolderManagedObjects(context.TODO(), o.Client, folderList)
+		logger.Debug("Find Folder objects")
+		folderMoList, err := getFolderManagedObjects(context.TODO(), client, folderList)
 		if err != nil {
-			o.Logger.Errorln(err)
+			logger.Errorln(err)
 			return err
 		}
 
-		o.Logger.Debug("Delete Folder")
-		err = deleteFolder(context.TODO(), o.Client, folderMoList, o.Logger)
+		logger.Debug("Delete Folder")
+		err = deleteFolder(context.TODO(), client, folderMoList, logger)
 		if err != nil {
-			o.Logger.Errorln(err)
+			logger.Errorln(err)
 			return err
 		}
 	} else {
-		o.Logger.Debug("No managed Folder found")
+		logger.Debug("No managed Folder found")
 	}
 
-	err = deleteStoragePolicy(context.TODO(), o.Client, o.InfraID, o.Logger)
+	err = deleteStoragePolicy(context.TODO(), client, o.InfraID, logger)
 	if err != nil {
 		return errors.Errorf("error deleting storage policy: %v", err)
 	}
 
-	o.Logger.Debug("Delete tag")
-	tagLogger := o.Logger.WithField("Tag", o.InfraID)
-	if err = deleteTag(context.TODO(), o.RestClient, o.InfraID); err != nil {
+	logger.Debug("Delete tag")
+	tagLogger := logger.WithField("Tag", o.InfraID)
+	if err = deleteTag(context.TODO(), restClient, o.InfraID); err != nil {
 		tagLogger.Errorln(err)
 		return err
 	}
 	tagLogger.Info("Destroyed")
 
-	o.Logger.Debug("Delete tag category")
-	tcLogger := o.Logger.WithField("TagCategory", "openshift-"+o.InfraID)
-	if err = deleteTagCategory(context.TODO(), o.RestClient, "openshift-"+o.InfraID); err != nil {
+	logger.Debug("Delete tag category")
+	tcLogger := logger.WithField("TagCategory", "openshift-"+o.InfraID)
+	if err = deleteTagCategory(context.TODO(), restClient, "openshift-"+o.InfraID); err != nil {
 		tcLogger.Errorln(err)
 		return err
 	}
@@ -29,6 +29,13 @@ type Err struct {
 	// diagnostics for the error. When writing messages, make sure to keep in mind
 	// that the audience for message is end-users who might not be experts.
 	Message string
+
+	// Remediation is free-form text that suggests how the user can fix
+	// the error.
+	Remediation string
+
+	// DocsURL links to documentation about the error.
+	DocsURL string
 }
 
 // Unwrap allows the error to be unwrapped.
@@ -64,6 +71,11 @@ func (e *Err) Error() string {
 // Message:
 // <Message>
 //
+// Remediation:
+// <Remediation>
+//
+// Documentation: <DocsURL>
+//
 // Original:
 // <Orig>
 func (e *Err) Print(w io.Writer) {
@@ -73,6 +85,13 @@ func (e *Err) Print(w io.Writer) {
 		fmt.Fprintf(w, "\nMessage:\n")
 		fmt.Fprintln(w, e.Message)
 	}
+	if len(e.Remediation) > 0 {
+		fmt.Fprintf(w, "\nRemediation:\n")
+		fmt.Fprintln(w, e.Remediation)
+	}
+	if len(e.DocsURL) > 0 {
+		fmt.Fprintf(w, "\nDocumentation: %s\n", e.DocsURL)
+	}
 	fmt.Fprintf(w, "\nOriginal error:\n")
 	fmt.Fprintln(w, e.Orig)
 }
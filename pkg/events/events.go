@@ -0,0 +1,186 @@
+// Package events emits a machine-readable stream of install progress
+// events as JSON lines.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Type is the type of an event.
+type Type string
+
+const (
+	// AssetGenerated is emitted after an asset has been generated.
+	AssetGenerated Type = "AssetGenerated"
+	// TerraformStageStarted is emitted before a terraform stage is applied or destroyed.
+	TerraformStageStarted Type = "TerraformStageStarted"
+	// TerraformStageFinished is emitted after a terraform stage was applied or destroyed.
+	TerraformStageFinished Type = "TerraformStageFinished"
+	// BootstrapComplete is emitted once the bootstrap control plane handed over to the cluster.
+	BootstrapComplete Type = "BootstrapComplete"
+	// BootstrapDestroyed is emitted once the bootstrap resources were removed.
+	BootstrapDestroyed Type = "BootstrapDestroyed"
+	// ClusterOperatorConditionChanged is emitted whenever a condition of a
+	// ClusterOperator changes its status.
+	ClusterOperatorConditionChanged Type = "ClusterOperatorConditionChanged"
+	// InstallComplete is emitted once the cluster is installed.
+	InstallComplete Type = "InstallComplete"
+	// ClusterDestroyed is emitted once the cluster resources were removed.
+	ClusterDestroyed Type = "ClusterDestroyed"
+	// GatherComplete is emitted once a log bundle was gathered.
+	GatherComplete Type = "GatherComplete"
+)
+
+// Event is a single install progress event. Only the fields relevant to the
+// event type are set.
+type Event struct {
+	Time  time.Time `json:"time"`
+	Type  Type      `json:"type"`
+	Error string    `json:"error,omitempty"`
+
+	// Asset is the name of the generated asset.
+	Asset string `json:"asset,omitempty"`
+
+	// Stage is the name of the terraform stage.
+	Stage string `json:"stage,omitempty"`
+	// Action is either "apply" or "destroy" for terraform stage events.
+	Action string `json:"action,omitempty"`
+
+	// Operator is the name of the ClusterOperator.
+	Operator string `json:"operator,omitempty"`
+	// Condition is the changed condition of the ClusterOperator.
+	Condition *Condition `json:"condition,omitempty"`
+
+	// ConsoleURL is the URL of the web console of the installed cluster.
+	ConsoleURL string `json:"consoleURL,omitempty"`
+	// Path is the location of a produced file, e.g. a log bundle.
+	Path string `json:"path,omitempty"`
+}
+
+// Condition is a ClusterOperator status condition.
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Emitter writes events as JSON lines.
+type Emitter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewEmitter returns an emitter that writes to out. A nil out discards all
+// events.
+func NewEmitter(out io.Writer) *Emitter {
+	return &Emitter{out: out}
+}
+
+// Emit writes the event, filling in the time if it is not set.
+func (e *Emitter) Emit(event Event) error {
+	if e == nil || e.out == nil {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.out.Write(append(data, '\n'))
+	return err
+}
+
+// Enabled returns true if the emitter writes events anywhere.
+func (e *Emitter) Enabled() bool {
+	return e != nil && e.out != nil
+}
+
+var (
+	// emitterLock guards emitter. Emit holds it for reading while writing
+	// an event so that the stream is not closed in the middle of a write.
+	emitterLock sync.RWMutex
+	emitter     = NewEmitter(nil)
+
+	// emitFailed is true once an event failed to be written.
+	emitFailed bool
+)
+
+// Open directs the process-wide event stream to target, which is either a
+// file path or "fd://<n>" for an already-open file descriptor. The returned
+// function closes the stream; it may be called more than once.
+func Open(target string) (func(), error) {
+	var f *os.File
+	if strings.HasPrefix(target, "fd://") {
+		fd, err := strconv.Atoi(strings.TrimPrefix(target, "fd://"))
+		if err != nil || fd < 0 {
+			return nil, errors.Errorf("invalid event stream file descriptor %q", target)
+		}
+		f = os.NewFile(uintptr(fd), target)
+	} else {
+		var err error
+		f, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open event stream")
+		}
+	}
+
+	emitterLock.Lock()
+	emitter = NewEmitter(f)
+	emitFailed = false
+	emitterLock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			emitterLock.Lock()
+			defer emitterLock.Unlock()
+			emitter = NewEmitter(nil)
+			if err := f.Close(); err != nil {
+				logrus.Debugf("Failed to close the event stream: %v", err)
+			}
+		})
+	}, nil
+}
+
+// Emit writes the event to the process-wide event stream. Failures to write
+// events never fail the install. The first failure is logged as a warning
+// and later ones at the debug level.
+func Emit(event Event) {
+	emitterLock.RLock()
+	err := emitter.Emit(event)
+	emitterLock.RUnlock()
+	if err == nil {
+		return
+	}
+
+	emitterLock.Lock()
+	first := !emitFailed
+	emitFailed = true
+	emitterLock.Unlock()
+	if first {
+		logrus.Warnf("Failed to write the %s event to the event stream: %v", event.Type, err)
+	} else {
+		logrus.Debugf("Failed to write the %s event to the event stream: %v", event.Type, err)
+	}
+}
+
+// Enabled returns true if the process-wide event stream was opened.
+func Enabled() bool {
+	emitterLock.RLock()
+	defer emitterLock.RUnlock()
+	return emitter.Enabled()
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitterWritesJSONLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewEmitter(buf)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, e.Emit(Event{Time: now, Type: TerraformStageStarted, Stage: "cluster", Action: "apply"}))
+	assert.NoError(t, e.Emit(Event{Time: now, Type: ClusterOperatorConditionChanged, Operator: "dns", Condition: &Condition{Type: "Available", Status: "True"}}))
+
+	assert.Equal(t, `{"time":"2021-06-01T12:00:00Z","type":"TerraformStageStarted","stage":"cluster","action":"apply"}
+{"time":"2021-06-01T12:00:00Z","type":"ClusterOperatorConditionChanged","operator":"dns","condition":{"type":"Available","status":"True"}}
+`, buf.String())
+}
+
+func TestEmitterFillsTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, NewEmitter(buf).Emit(Event{Type: InstallComplete}))
+
+	var event Event
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.False(t, event.Time.IsZero())
+}
+
+func TestDisabledEmitter(t *testing.T) {
+	assert.False(t, NewEmitter(nil).Enabled())
+	assert.NoError(t, NewEmitter(nil).Emit(Event{Type: InstallComplete}))
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestOpen")
+	if err != nil {
+		t.Fatalf("could not create the temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.json")
+	closer, err := Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, Enabled())
+	Emit(Event{Type: BootstrapComplete})
+	Emit(Event{Type: BootstrapDestroyed})
+	closer()
+	assert.False(t, Enabled())
+	closer()
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+
+	_, err = Open("fd://x")
+	assert.EqualError(t, err, `invalid event stream file descriptor "fd://x"`)
+}
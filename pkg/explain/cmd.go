@@ -1,11 +1,9 @@
 package explain
 
 import (
-	"io/ioutil"
 	"os"
 	"strings"
 
-	"github.com/openshift/installer/data"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -40,25 +38,14 @@ func runCmd(cmd *cobra.Command, args []string) error {
 		return errors.Errorf("We accept only this format: explain RESOURCE\n")
 	}
 
-	file, err := data.Assets.Open(installConfigCRDFileName)
-	if err != nil {
-		return errors.Wrap(err, "failed to load InstallConfig CRD")
-	}
-	defer file.Close()
-
-	raw, err := ioutil.ReadAll(file)
-	if err != nil {
-		return errors.Wrap(err, "failed to read InstallConfig CRD")
-	}
-
 	resource, path := splitDotNotation(args[0])
 	if resource != "installconfig" {
 		return errors.Errorf("only installconfig resource is supported")
 	}
 
-	schema, err := loadSchema(raw)
+	schema, err := InstallConfigSchema()
 	if err != nil {
-		return errors.Wrap(err, "failed to load schema")
+		return err
 	}
 
 	fschema, err := lookup(schema, path)
@@ -1,12 +1,37 @@
 package explain
 
 import (
+	"io/ioutil"
+
 	"github.com/pkg/errors"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"github.com/openshift/installer/data"
 )
 
+// InstallConfigSchema returns the OpenAPI schema of the InstallConfig from
+// its CRD.
+func InstallConfigSchema() (*apiextv1.JSONSchemaProps, error) {
+	file, err := data.Assets.Open(installConfigCRDFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load InstallConfig CRD")
+	}
+	defer file.Close()
+
+	raw, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read InstallConfig CRD")
+	}
+
+	schema, err := loadSchema(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load schema")
+	}
+	return schema, nil
+}
+
 func loadSchema(b []byte) (*apiextv1.JSONSchemaProps, error) {
 	scheme := runtime.NewScheme()
 	codecs := serializer.NewCodecFactory(scheme)
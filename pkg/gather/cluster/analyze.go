@@ -0,0 +1,207 @@
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/gather/service"
+)
+
+// clusterVersionFailing is the condition that the cluster-version operator sets on the ClusterVersion when it cannot
+// apply the desired release.
+const clusterVersionFailing configv1.ClusterStatusConditionType = "Failing"
+
+// bundleFilePathRegex matches the path of a resources file in the cluster bundle. The captured group is the path of
+// the file relative to the root of the bundle.
+var bundleFilePathRegex = regexp.MustCompile(`^[^\/]+\/(resources\/[^\/]+\.json)$`)
+
+// Bundle is the content of a cluster bundle that is relevant to the checks.
+type Bundle struct {
+	// files are the contents of the resources files, by path relative to the root of the bundle.
+	files map[string][]byte
+}
+
+// Check inspects a cluster bundle for one class of problems.
+type Check struct {
+	// Name identifies the check in the report.
+	Name string
+	// Run inspects the bundle. The Check field of the returned finding is filled in by Analyze.
+	Run func(*Bundle) service.Finding
+}
+
+// DefaultChecks are the checks run by AnalyzeBundle, in order.
+var DefaultChecks = []Check{
+	{Name: "cluster-version", Run: checkClusterVersion},
+	{Name: "cluster-operators", Run: checkClusterOperators},
+}
+
+// AnalyzeBundle will analyze the cluster bundle at the specified path.
+// Analysis will be logged.
+// Returns an error if there was a problem reading the bundle.
+func AnalyzeBundle(bundlePath string) error {
+	bundle, err := LoadBundle(bundlePath)
+	if err != nil {
+		return err
+	}
+	Analyze(bundle, DefaultChecks).Log()
+	return nil
+}
+
+// LoadBundle reads the cluster bundle at the specified path.
+func LoadBundle(bundlePath string) (*Bundle, error) {
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the cluster bundle")
+	}
+	defer bundleFile.Close()
+	return loadBundle(bundleFile)
+}
+
+func loadBundle(bundleFile io.Reader) (*Bundle, error) {
+	uncompressedStream, err := gzip.NewReader(bundleFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress the cluster bundle")
+	}
+	defer uncompressedStream.Close()
+
+	tarReader := tar.NewReader(uncompressedStream)
+	bundle := &Bundle{files: map[string][]byte{}}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "encountered an error reading from the cluster bundle")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		submatch := bundleFilePathRegex.FindStringSubmatch(header.Name)
+		if submatch == nil {
+			continue
+		}
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s from the cluster bundle", submatch[1])
+		}
+		bundle.files[submatch[1]] = data
+	}
+	return bundle, nil
+}
+
+// Analyze runs the checks against the bundle.
+func Analyze(bundle *Bundle, checks []Check) *service.Report {
+	report := &service.Report{}
+	for _, check := range checks {
+		finding := check.Run(bundle)
+		finding.Check = check.Name
+		report.Findings = append(report.Findings, finding)
+	}
+	return report
+}
+
+// decode decodes the resources file at the given path relative to the root of the bundle into obj. It returns a
+// skip finding if the file is missing or cannot be decoded.
+func (b *Bundle) decode(path string, obj interface{}) *service.Finding {
+	data, ok := b.files[path]
+	if !ok {
+		return &service.Finding{Status: service.StatusSkip, Summary: fmt.Sprintf("the bundle has no %s", path)}
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return &service.Finding{Status: service.StatusSkip, Summary: fmt.Sprintf("could not decode %s: %v", path, err)}
+	}
+	return nil
+}
+
+func checkClusterVersion(b *Bundle) service.Finding {
+	versions := &configv1.ClusterVersionList{}
+	if skip := b.decode(ClusterVersionFile, versions); skip != nil {
+		return *skip
+	}
+	for _, version := range versions.Items {
+		if failing := findCondition(version.Status.Conditions, clusterVersionFailing); failing != nil && failing.Status == configv1.ConditionTrue {
+			return service.Finding{
+				Status:  service.StatusFail,
+				Summary: "The cluster version is failing",
+				Details: []string{formatCondition(failing)},
+			}
+		}
+		if available := findCondition(version.Status.Conditions, configv1.OperatorAvailable); available == nil || available.Status != configv1.ConditionTrue {
+			var details []string
+			if progressing := findCondition(version.Status.Conditions, configv1.OperatorProgressing); progressing != nil {
+				details = append(details, formatCondition(progressing))
+			}
+			return service.Finding{
+				Status:  service.StatusFail,
+				Summary: fmt.Sprintf("The cluster has not finished installing version %s", version.Status.Desired.Version),
+				Details: details,
+			}
+		}
+	}
+	return service.Finding{Status: service.StatusPass}
+}
+
+func checkClusterOperators(b *Bundle) service.Finding {
+	operators := &configv1.ClusterOperatorList{}
+	if skip := b.decode(ClusterOperatorsFile, operators); skip != nil {
+		return *skip
+	}
+	sort.Slice(operators.Items, func(i, j int) bool { return operators.Items[i].Name < operators.Items[j].Name })
+
+	var degraded, unavailable int
+	var details []string
+	for _, operator := range operators.Items {
+		conditions := operator.Status.Conditions
+		if condition := findCondition(conditions, configv1.OperatorDegraded); condition != nil && condition.Status == configv1.ConditionTrue {
+			degraded++
+			details = append(details, fmt.Sprintf("%s: %s", operator.Name, formatCondition(condition)))
+		}
+		if condition := findCondition(conditions, configv1.OperatorAvailable); condition == nil || condition.Status != configv1.ConditionTrue {
+			unavailable++
+			if condition == nil {
+				details = append(details, fmt.Sprintf("%s: Available condition is missing", operator.Name))
+			} else {
+				details = append(details, fmt.Sprintf("%s: %s", operator.Name, formatCondition(condition)))
+			}
+		}
+	}
+	if len(details) == 0 {
+		return service.Finding{Status: service.StatusPass}
+	}
+	return service.Finding{
+		Status:  service.StatusFail,
+		Summary: fmt.Sprintf("%d of %d cluster operators are degraded and %d are not available", degraded, len(operators.Items), unavailable),
+		Details: details,
+	}
+}
+
+func findCondition(conditions []configv1.ClusterOperatorStatusCondition, conditionType configv1.ClusterStatusConditionType) *configv1.ClusterOperatorStatusCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func formatCondition(condition *configv1.ClusterOperatorStatusCondition) string {
+	s := fmt.Sprintf("%s is %s", condition.Type, condition.Status)
+	if condition.Reason != "" {
+		s += fmt.Sprintf(" with %s", condition.Reason)
+	}
+	if condition.Message != "" {
+		s += ": " + condition.Message
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/gather/service"
+)
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    map[string]string
+		expected []service.Finding
+	}{
+		{
+			name: "no files",
+			expected: []service.Finding{
+				{Check: "cluster-version", Status: service.StatusSkip, Summary: "the bundle has no resources/clusterversion.json"},
+				{Check: "cluster-operators", Status: service.StatusSkip, Summary: "the bundle has no resources/clusteroperators.json"},
+			},
+		},
+		{
+			name: "installed",
+			files: map[string]string{
+				ClusterVersionFile: `{"items":[{"status":{"desired":{"version":"4.8.0"},"conditions":[
+{"type":"Available","status":"True"},
+{"type":"Failing","status":"False"}
+]}}]}`,
+				ClusterOperatorsFile: `{"items":[{"metadata":{"name":"dns"},"status":{"conditions":[
+{"type":"Available","status":"True"},
+{"type":"Degraded","status":"False"}
+]}}]}`,
+			},
+			expected: []service.Finding{
+				{Check: "cluster-version", Status: service.StatusPass},
+				{Check: "cluster-operators", Status: service.StatusPass},
+			},
+		},
+		{
+			name: "degraded operators",
+			files: map[string]string{
+				ClusterVersionFile: `{"items":[{"status":{"desired":{"version":"4.8.0"},"conditions":[
+{"type":"Available","status":"False"},
+{"type":"Failing","status":"True","reason":"ClusterOperatorDegraded","message":"Cluster operator authentication is degraded"}
+]}}]}`,
+				ClusterOperatorsFile: `{"items":[
+{"metadata":{"name":"ingress"},"status":{"conditions":[
+{"type":"Available","status":"False","reason":"IngressUnavailable","message":"no router pods are ready"},
+{"type":"Degraded","status":"False"}
+]}},
+{"metadata":{"name":"authentication"},"status":{"conditions":[
+{"type":"Available","status":"True"},
+{"type":"Degraded","status":"True","reason":"OAuthRouteCheckEndpointAccessibleController_SyncError","message":"route not reachable"}
+]}},
+{"metadata":{"name":"console"},"status":{}}
+]}`,
+			},
+			expected: []service.Finding{
+				{
+					Check:   "cluster-version",
+					Status:  service.StatusFail,
+					Summary: "The cluster version is failing",
+					Details: []string{"Failing is True with ClusterOperatorDegraded: Cluster operator authentication is degraded"},
+				},
+				{
+					Check:   "cluster-operators",
+					Status:  service.StatusFail,
+					Summary: "1 of 3 cluster operators are degraded and 2 are not available",
+					Details: []string{
+						"authentication: Degraded is True with OAuthRouteCheckEndpointAccessibleController_SyncError: route not reachable",
+						"console: Available condition is missing",
+						"ingress: Available is False with IngressUnavailable: no router pods are ready",
+					},
+				},
+			},
+		},
+		{
+			name: "progressing",
+			files: map[string]string{
+				ClusterVersionFile: `{"items":[{"status":{"desired":{"version":"4.8.0"},"conditions":[
+{"type":"Available","status":"False"},
+{"type":"Progressing","status":"True","message":"Working towards 4.8.0: 650 of 676 done (96% complete)"}
+]}}]}`,
+			},
+			expected: []service.Finding{
+				{
+					Check:   "cluster-version",
+					Status:  service.StatusFail,
+					Summary: "The cluster has not finished installing version 4.8.0",
+					Details: []string{"Progressing is True: Working towards 4.8.0: 650 of 676 done (96% complete)"},
+				},
+				{Check: "cluster-operators", Status: service.StatusSkip, Summary: "the bundle has no resources/clusteroperators.json"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cluster-bundle")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			bundlePath := filepath.Join(dir, "cluster-bundle.tar.gz")
+			if err := writeBundle(bundlePath, "cluster-bundle", func(g *gatherer) {
+				for name, data := range tc.files {
+					g.write(name, []byte(data))
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			bundle, err := LoadBundle(bundlePath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, Analyze(bundle, DefaultChecks).Findings)
+		})
+	}
+}
+
+func TestPodFailing(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   corev1.PodStatus
+		expected bool
+	}{
+		{
+			name:   "succeeded",
+			status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+		{
+			name: "running and ready",
+			status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "a", Ready: true}},
+			},
+		},
+		{
+			name: "running and not ready",
+			status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "a", Ready: true}, {Name: "b"}},
+			},
+			expected: true,
+		},
+		{
+			name:     "pending",
+			status:   corev1.PodStatus{Phase: corev1.PodPending},
+			expected: true,
+		},
+		{
+			name:     "failed",
+			status:   corev1.PodStatus{Phase: corev1.PodFailed},
+			expected: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, podFailing(&corev1.Pod{Status: tc.status}))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package cluster contains utilities that help gather logs on failures after the bootstrap resources have been
+// destroyed, by collecting the state of the cluster through its API.
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// ClusterVersionFile is the path of the ClusterVersion list in the bundle, relative to its root.
+	ClusterVersionFile = "resources/clusterversion.json"
+	// ClusterOperatorsFile is the path of the ClusterOperator list in the bundle, relative to its root.
+	ClusterOperatorsFile = "resources/clusteroperators.json"
+
+	// podLogTailLines is the number of lines gathered from the end of the log of each container of a failing pod.
+	podLogTailLines = 1000
+)
+
+var machineConfigPoolsResource = schema.GroupVersionResource{
+	Group:    "machineconfiguration.openshift.io",
+	Version:  "v1",
+	Resource: "machineconfigpools",
+}
+
+// gatherer writes the files of a cluster bundle as they are collected.
+type gatherer struct {
+	root string
+	tar  *tar.Writer
+	now  time.Time
+	// err is the first error writing to the bundle, after which nothing more is written.
+	err error
+}
+
+// Gather collects the ClusterVersion, the ClusterOperators, the nodes, the certificate signing requests, the
+// MachineConfigPools, the events and the logs of the failing pods of the cluster and writes them to
+// cluster-bundle-<gatherID>.tar.gz in directory. It returns the path of the bundle. Resources that cannot be
+// collected are logged and left out of the bundle, so that a partially available API still yields a bundle.
+func Gather(ctx context.Context, config *rest.Config, gatherID, directory string) (string, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", errors.Wrap(err, "creating a Kubernetes client")
+	}
+	configClient, err := configclient.NewForConfig(config)
+	if err != nil {
+		return "", errors.Wrap(err, "creating a config client")
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return "", errors.Wrap(err, "creating a dynamic client")
+	}
+
+	file := filepath.Join(directory, fmt.Sprintf("cluster-bundle-%s.tar.gz", gatherID))
+	if err := writeBundle(file, fmt.Sprintf("cluster-bundle-%s", gatherID), func(g *gatherer) {
+		g.gatherResources(ctx, kubeClient, configClient, dynamicClient)
+		g.gatherPodLogs(ctx, kubeClient)
+	}); err != nil {
+		return "", errors.Wrap(err, "failed to write the cluster bundle")
+	}
+	return file, nil
+}
+
+func writeBundle(filename, root string, gather func(*gatherer)) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	g := &gatherer{root: root, tar: tar.NewWriter(gz), now: time.Now()}
+	gather(g)
+	if g.err != nil {
+		return g.err
+	}
+	if err := g.tar.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (g *gatherer) gatherResources(ctx context.Context, kubeClient kubernetes.Interface, configClient configclient.Interface, dynamicClient dynamic.Interface) {
+	g.writeList(ClusterVersionFile, func() (interface{}, error) {
+		return configClient.ConfigV1().ClusterVersions().List(ctx, metav1.ListOptions{})
+	})
+	g.writeList(ClusterOperatorsFile, func() (interface{}, error) {
+		return configClient.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	})
+	g.writeList("resources/nodes.json", func() (interface{}, error) {
+		return kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	g.writeList("resources/csr.json", func() (interface{}, error) {
+		return kubeClient.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	})
+	g.writeList("resources/machineconfigpools.json", func() (interface{}, error) {
+		return dynamicClient.Resource(machineConfigPoolsResource).List(ctx, metav1.ListOptions{})
+	})
+	g.writeList("resources/events.json", func() (interface{}, error) {
+		return kubeClient.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	})
+}
+
+// gatherPodLogs writes the logs of the containers of the failing pods, and the logs of the previous instances of
+// the containers that restarted.
+func (g *gatherer) gatherPodLogs(ctx context.Context, kubeClient kubernetes.Interface) {
+	pods, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logrus.Warnf("Failed to list the pods: %v", err)
+		return
+	}
+	g.writeList("resources/pods.json", func() (interface{}, error) { return pods, nil })
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podFailing(pod) {
+			continue
+		}
+		dir := path.Join("pods", pod.Namespace, pod.Name)
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			container := status.Name
+			g.writeLog(path.Join(dir, container+".log"), func() ([]byte, error) {
+				return podLog(ctx, kubeClient, pod, container, false)
+			})
+			if status.RestartCount > 0 {
+				g.writeLog(path.Join(dir, container+".previous.log"), func() ([]byte, error) {
+					return podLog(ctx, kubeClient, pod, container, true)
+				})
+			}
+		}
+	}
+}
+
+// podFailing returns true if the pod did not run to completion and is not running with all of its containers ready.
+func podFailing(pod *corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return false
+	case corev1.PodRunning:
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func podLog(ctx context.Context, kubeClient kubernetes.Interface, pod *corev1.Pod, container string, previous bool) ([]byte, error) {
+	tailLines := int64(podLogTailLines)
+	return kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+}
+
+func (g *gatherer) writeList(name string, list func() (interface{}, error)) {
+	obj, err := list()
+	if err != nil {
+		logrus.Warnf("Failed to gather %s: %v", name, err)
+		return
+	}
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		logrus.Warnf("Failed to encode %s: %v", name, err)
+		return
+	}
+	g.write(name, data)
+}
+
+func (g *gatherer) writeLog(name string, log func() ([]byte, error)) {
+	data, err := log()
+	if err != nil {
+		logrus.Debugf("Failed to gather %s: %v", name, err)
+		return
+	}
+	g.write(name, data)
+}
+
+func (g *gatherer) write(name string, data []byte) {
+	if g.err != nil {
+		return
+	}
+	if err := g.tar.WriteHeader(&tar.Header{
+		Name:     path.Join(g.root, name),
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  g.now,
+	}); err != nil {
+		g.err = err
+		return
+	}
+	_, g.err = g.tar.Write(data)
+}
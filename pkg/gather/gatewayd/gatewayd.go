@@ -0,0 +1,241 @@
+// Package gatewayd contains utilities that help gather logs on failures using systemd-journal-gatewayd, for
+// networks where the bootstrap machine cannot be reached over SSH.
+package gatewayd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Port is the port on which systemd-journal-gatewayd listens on the bootstrap machine.
+	Port = 19531
+
+	// serviceRecordIdentifier is the syslog identifier of the journal entries in which bootstrap-service-record.sh
+	// records the service records.
+	serviceRecordIdentifier = "bootstrap-service-record"
+)
+
+// Units are the systemd units whose journals are gathered from the bootstrap machine. They are the units of the
+// journals gathered by installer-gather.sh.
+var Units = []string{"release-image", "crio-configure", "bootkube", "kubelet", "crio", "approve-csr", "ironic", "master-bmh-update"}
+
+// Client is a client of the systemd-journal-gatewayd of a host.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new client of the systemd-journal-gatewayd at address, which authenticates with the PEM-encoded
+// client certificate and key and trusts the PEM-encoded CA certificate.
+func NewClient(address string, cert, key, ca []byte) (*Client, error) {
+	clientCert, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the client certificate")
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to load the CA certificate")
+	}
+
+	return &Client{
+		baseURL: fmt.Sprintf("https://%s", address),
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{clientCert},
+					// The certificate of journal-gatewayd is not issued for the addresses of the bootstrap machine,
+					// so the chain is verified without the host name.
+					InsecureSkipVerify:    true,
+					VerifyPeerCertificate: verifyChain(roots),
+				},
+			},
+			Timeout: 5 * time.Minute,
+		},
+	}, nil
+}
+
+func verifyChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no server certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse the server certificate")
+			}
+			certs[i] = cert
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		return err
+	}
+}
+
+// entries fetches the journal entries of the current boot that match the field, in the format given by accept.
+func (c *Client) entries(ctx context.Context, field, value, accept string) ([]byte, error) {
+	// journal-gatewayd expects the boot argument without a value.
+	query := fmt.Sprintf("boot&%s=%s", field, url.QueryEscape(value))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/entries?%s", c.baseURL, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Journal returns the journal of the current boot of the systemd unit, in the short output format of journalctl.
+func (c *Client) Journal(ctx context.Context, unit string) ([]byte, error) {
+	data, err := c.entries(ctx, "_SYSTEMD_UNIT", unit+".service", "text/plain")
+	return data, errors.Wrapf(err, "failed to fetch the journal of %s", unit)
+}
+
+// ServiceRecords returns the service records of the current boot, by service name, as the JSON arrays that
+// bootstrap-service-record.sh writes to /var/log/openshift.
+func (c *Client) ServiceRecords(ctx context.Context) (map[string][]byte, error) {
+	data, err := c.entries(ctx, "SYSLOG_IDENTIFIER", serviceRecordIdentifier, "application/json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the service records")
+	}
+
+	entries := map[string][]json.RawMessage{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fields struct {
+			Message string `json:"MESSAGE"`
+			Service string `json:"SERVICE_RECORD_NAME"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil {
+			logrus.Debugf("Skipping a service record journal entry that could not be decoded: %v", err)
+			continue
+		}
+		if fields.Service == "" || !json.Valid([]byte(fields.Message)) {
+			logrus.Debugf("Skipping an invalid service record journal entry: %s", scanner.Text())
+			continue
+		}
+		entries[fields.Service] = append(entries[fields.Service], json.RawMessage(fields.Message))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the service records")
+	}
+
+	records := make(map[string][]byte, len(entries))
+	for service, serviceEntries := range entries {
+		data, err := json.Marshal(serviceEntries)
+		if err != nil {
+			return nil, err
+		}
+		records[service] = data
+	}
+	return records, nil
+}
+
+// Gather pulls the journals and the service records of the bootstrap machine and writes them to
+// log-bundle-<gatherID>.tar.gz in directory, with the layout of the bundles of installer-gather.sh. It returns the
+// path of the bundle.
+func Gather(ctx context.Context, client *Client, gatherID, directory string) (string, error) {
+	root := fmt.Sprintf("log-bundle-%s", gatherID)
+	files := map[string][]byte{}
+
+	records, err := client.ServiceRecords(ctx)
+	if err != nil {
+		return "", err
+	}
+	for service, data := range records {
+		files[path.Join(root, "bootstrap", "services", service+".json")] = data
+	}
+	for _, unit := range Units {
+		data, err := client.Journal(ctx, unit)
+		if err != nil {
+			return "", err
+		}
+		files[path.Join(root, "bootstrap", "journals", unit+".log")] = data
+	}
+
+	file := filepath.Join(directory, root+".tar.gz")
+	if err := writeBundle(file, files); err != nil {
+		return "", errors.Wrap(err, "failed to write the log bundle")
+	}
+	return file, nil
+}
+
+func writeBundle(filename string, files map[string][]byte) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	for _, name := range names {
+		data := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  now,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,114 @@
+package gatewayd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	assettls "github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/gather/service"
+)
+
+// fakeGatewayd serves the journal entries of a bootstrap machine the way
+// systemd-journal-gatewayd does.
+func fakeGatewayd(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/entries" || !strings.HasPrefix(r.URL.RawQuery, "boot&") {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		query := r.URL.Query()
+		switch {
+		case query.Get("SYSLOG_IDENTIFIER") == serviceRecordIdentifier:
+			assert.Equal(t, "application/json", r.Header.Get("Accept"))
+			fmt.Fprintln(w, `{"MESSAGE":"{\"timestamp\":\"2021-03-29T19:04:53Z\",\"phase\":\"service start\"}","SERVICE_RECORD_NAME":"release-image"}`)
+			fmt.Fprintln(w, `{"MESSAGE":"{\"timestamp\":\"2021-03-29T19:05:03Z\",\"phase\":\"service end\",\"result\":\"success\"}","SERVICE_RECORD_NAME":"release-image"}`)
+			fmt.Fprintln(w, `{"MESSAGE":"{\"timestamp\":\"2021-03-29T19:05:53Z\",\"phase\":\"service start\"}","SERVICE_RECORD_NAME":"bootkube"}`)
+			fmt.Fprintln(w, `{"MESSAGE":"{\"timestamp\":\"2021-03-29T19:06:53Z\",\"phase\":\"service end\",\"result\":\"failure\",\"errorMessage\":\"etcd is not healthy\"}","SERVICE_RECORD_NAME":"bootkube"}`)
+			fmt.Fprintln(w, `{"MESSAGE":"not a record","SERVICE_RECORD_NAME":"release-image"}`)
+		case query.Get("_SYSTEMD_UNIT") != "":
+			assert.Equal(t, "text/plain", r.Header.Get("Accept"))
+			fmt.Fprintf(w, "Mar 29 19:05:53 bootstrap %s[1]: started\n", strings.TrimSuffix(query.Get("_SYSTEMD_UNIT"), ".service"))
+		default:
+			http.Error(w, "unexpected filter", http.StatusBadRequest)
+		}
+	})
+}
+
+func TestGather(t *testing.T) {
+	parents := asset.Parents{}
+	parents.Add(&installconfig.InstallConfig{}, &assettls.UserCA{})
+	rootCA := &assettls.RootCA{}
+	if err := rootCA.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+	parents.Add(rootCA)
+	journalCertKey := &assettls.JournalCertKey{}
+	if err := journalCertKey.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+
+	serverCert, err := tls.X509KeyPair(journalCertKey.Cert(), journalCertKey.Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(rootCA.Cert())
+	server := httptest.NewUnstartedServer(fakeGatewayd(t))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	dir, err := ioutil.TempDir("", "gatewayd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := NewClient(address, journalCertKey.Cert(), journalCertKey.Key(), rootCA.Cert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundlePath, err := Gather(context.Background(), client, "20210329190553", dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "log-bundle-20210329190553.tar.gz", strings.TrimPrefix(bundlePath, dir+string(os.PathSeparator)))
+
+	bundle, err := service.LoadGatherBundle(bundlePath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	journal, ok := bundle.Journal("bootkube")
+	assert.True(t, ok)
+	assert.Equal(t, "Mar 29 19:05:53 bootstrap bootkube[1]: started\n", journal)
+	report := service.Analyze(bundle, service.DefaultChecks)
+	assert.Contains(t, fmt.Sprint(report), "etcd is not healthy")
+
+	otherCA := &assettls.RootCA{}
+	if err := otherCA.Generate(parents); err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := NewClient(address, journalCertKey.Cert(), journalCertKey.Key(), otherCA.Cert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = untrusted.Journal(context.Background(), "bootkube")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"os"
 	"regexp"
 	"strings"
@@ -13,91 +14,126 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// regex matching the path of a service entries file. The captured group is the name of the service.
+// regex matching the path of a file in the bootstrap gather bundle. The captured group is the path of the file relative
+// to the root of the bundle.
 // For example, if the filename is "log-bundle-20210329190553/bootstrap/services/release-image.json",
-// then the name of the service is "release-image".
+// then the relative path is "bootstrap/services/release-image.json".
 // In case the log-bundle is from bootstrap-in-place installation the file name is:
-//"log-bundle-20210329190553/log-bundle-bootstrap/bootstrap/services/release-image.json"
-var serviceEntriesFilePathRegex = regexp.MustCompile(`^[^\/]+(?:\/log-bundle-bootstrap)?\/bootstrap\/services\/([^.]+)\.json$`)
+// "log-bundle-20210329190553/log-bundle-bootstrap/bootstrap/services/release-image.json"
+var bundleFilePathRegex = regexp.MustCompile(`^[^\/]+(?:\/log-bundle-bootstrap)?\/((?:bootstrap|resources)\/.+)$`)
+
+// regex matching the relative path of a service entries file. The captured group is the name of the service.
+var serviceEntriesFilePathRegex = regexp.MustCompile(`^bootstrap\/services\/([^.]+)\.json$`)
+
+// collectedFilePathRegex matches the relative paths of the files that are kept in the Bundle for the checks.
+var collectedFilePathRegex = regexp.MustCompile(`^(?:bootstrap\/journals\/[^\/]+\.log|resources\/csr\.json)$`)
+
+// Bundle is the content of a bootstrap gather bundle that is relevant to the checks.
+type Bundle struct {
+	// services are the analyses of the service entries files, by service name.
+	services map[string]analysis
+	// files are the contents of the collected files, by path relative to the root of the bundle.
+	files map[string][]byte
+}
+
+// File returns the contents of the file at the given path relative to the root of the bundle, for example
+// "resources/csr.json".
+func (b *Bundle) File(path string) ([]byte, bool) {
+	data, ok := b.files[path]
+	return data, ok
+}
+
+// Journal returns the journal of the given systemd unit on the bootstrap machine.
+func (b *Bundle) Journal(unit string) (string, bool) {
+	data, ok := b.File("bootstrap/journals/" + unit + ".log")
+	return string(data), ok
+}
 
 // AnalyzeGatherBundle will analyze the bootstrap gather bundle at the specified path.
 // Analysis will be logged.
 // Returns an error if there was a problem reading the bundle.
 func AnalyzeGatherBundle(bundlePath string) error {
+	bundle, err := LoadGatherBundle(bundlePath)
+	if err != nil {
+		return err
+	}
+	Analyze(bundle, DefaultChecks).Log()
+	return nil
+}
+
+// LoadGatherBundle reads the bootstrap gather bundle at the specified path.
+func LoadGatherBundle(bundlePath string) (*Bundle, error) {
 	// open the bundle file for reading
 	bundleFile, err := os.Open(bundlePath)
 	if err != nil {
-		return errors.Wrap(err, "could not open the gather bundle")
+		return nil, errors.Wrap(err, "could not open the gather bundle")
 	}
 	defer bundleFile.Close()
-	return analyzeGatherBundle(bundleFile)
+	return loadGatherBundle(bundleFile)
 }
 
 func analyzeGatherBundle(bundleFile io.Reader) error {
+	bundle, err := loadGatherBundle(bundleFile)
+	if err != nil {
+		return err
+	}
+	Analyze(bundle, DefaultChecks).Log()
+	return nil
+}
+
+func loadGatherBundle(bundleFile io.Reader) (*Bundle, error) {
 	// decompress the bundle
 	uncompressedStream, err := gzip.NewReader(bundleFile)
 	if err != nil {
-		return errors.Wrap(err, "could not decompress the gather bundle")
+		return nil, errors.Wrap(err, "could not decompress the gather bundle")
 	}
 	defer uncompressedStream.Close()
 
 	// read through the tar for relevant files
 	tarReader := tar.NewReader(uncompressedStream)
-	serviceAnalyses := make(map[string]analysis)
+	bundle := &Bundle{
+		services: map[string]analysis{},
+		files:    map[string][]byte{},
+	}
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return errors.Wrap(err, "encountered an error reading from the gather bundle")
+			return nil, errors.Wrap(err, "encountered an error reading from the gather bundle")
 		}
 		if header.Typeflag != tar.TypeReg {
 			continue
 		}
 
-		serviceEntriesFileSubmatch := serviceEntriesFilePathRegex.FindStringSubmatch(header.Name)
-		if serviceEntriesFileSubmatch == nil {
+		bundleFileSubmatch := bundleFilePathRegex.FindStringSubmatch(header.Name)
+		if bundleFileSubmatch == nil {
 			continue
 		}
-		serviceName := serviceEntriesFileSubmatch[1]
-
-		serviceAnalysis, err := analyzeService(tarReader)
-		if err != nil {
-			logrus.Infof("Could not analyze the %s.service: %v", serviceName, err)
+		path := bundleFileSubmatch[1]
+
+		if serviceEntriesFileSubmatch := serviceEntriesFilePathRegex.FindStringSubmatch(path); serviceEntriesFileSubmatch != nil {
+			serviceName := serviceEntriesFileSubmatch[1]
+			serviceAnalysis, err := analyzeService(tarReader)
+			if err != nil {
+				logrus.Infof("Could not analyze the %s.service: %v", serviceName, err)
+				continue
+			}
+			bundle.services[serviceName] = serviceAnalysis
 			continue
 		}
 
-		serviceAnalyses[serviceName] = serviceAnalysis
-	}
-
-	analysisChecks := []struct {
-		name  string
-		check func(analysis) bool
-	}{
-		{name: "release-image", check: checkReleaseImageDownload},
-	}
-	for _, check := range analysisChecks {
-		a := serviceAnalyses[check.name]
-		if a.starts == 0 {
-			logrus.Errorf("The bootstrap machine did not execute the %s.service systemd unit", check.name)
-			break
-		}
-		if !check.check(a) {
-			break
+		if collectedFilePathRegex.MatchString(path) {
+			data, err := ioutil.ReadAll(tarReader)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not read %s from the gather bundle", path)
+			}
+			bundle.files[path] = data
 		}
 	}
 
-	return nil
-}
-
-func checkReleaseImageDownload(a analysis) bool {
-	if a.successful {
-		return true
-	}
-	logrus.Error("The bootstrap machine failed to download the release image")
-	a.logLastError()
-	return false
+	return bundle, nil
 }
 
 type analysis struct {
@@ -105,6 +141,10 @@ type analysis struct {
 	starts int
 	// successful is true if the last invocation of the service ended in success
 	successful bool
+	// failed is true if the last entry of the service is a failure
+	failed bool
+	// currentStage is the stage that the last invocation of the service started but did not end
+	currentStage string
 	// failingStage is the stage that failed in the last unsuccessful invocation of the service
 	failingStage string
 	// lastError is the last error recorded in the last failure of the service
@@ -135,11 +175,21 @@ func analyzeService(r io.Reader) (analysis, error) {
 		// record a new start of the service
 		if entry.Phase == ServiceStart {
 			a.starts++
+			a.currentStage = ""
+		}
+
+		// keep track of the stage that is in progress
+		switch entry.Phase {
+		case StageStart:
+			a.currentStage = entry.Stage
+		case StageEnd:
+			a.currentStage = ""
 		}
 
 		// the service is only considered successful if the last entry is either the service ending successfully or a
 		// post-command ending successfully.
 		a.successful = entry.Result == Success && (entry.Phase == ServiceEnd || entry.Phase == PostCommandEnd)
+		a.failed = entry.Result == Failure
 
 		// save the last error
 		if entry.Result == Failure {
@@ -155,8 +205,10 @@ func analyzeService(r io.Reader) (analysis, error) {
 	return a, nil
 }
 
-func (a analysis) logLastError() {
-	for _, l := range strings.Split(a.lastError, "\n") {
-		logrus.Info(l)
+// lastErrorLines returns the lines of the last error of the service.
+func (a analysis) lastErrorLines() []string {
+	if a.lastError == "" {
+		return nil
 	}
+	return strings.Split(a.lastError, "\n")
 }
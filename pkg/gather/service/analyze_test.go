@@ -4,6 +4,10 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -126,3 +130,107 @@ func TestAnalyzeGatherBundle(t *testing.T) {
 		})
 	}
 }
+
+// TestAnalyzeGoldenBundles analyzes the bundles in testdata/bundles and compares the reports with the golden
+// testdata/bundles/<name>.json files.
+func TestAnalyzeGoldenBundles(t *testing.T) {
+	bundles, err := ioutil.ReadDir(filepath.Join("testdata", "bundles"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bundle := range bundles {
+		if !bundle.IsDir() {
+			continue
+		}
+		t.Run(bundle.Name(), func(t *testing.T) {
+			dir := filepath.Join("testdata", "bundles", bundle.Name())
+			b, err := loadGatherBundle(tarDirectory(t, dir, "log-bundle-20210329190553"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.MarshalIndent(Analyze(b, DefaultChecks), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := ioutil.ReadFile(dir + ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+// tarDirectory returns a gzipped tarball of the files in dir, placed under prefix.
+func tarDirectory(t *testing.T, dir string, prefix string) *bytes.Buffer {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     prefix + "/" + filepath.ToSlash(rel),
+			Size:     int64(len(data)),
+		}); err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestCheckImagePull(t *testing.T) {
+	cases := []struct {
+		name     string
+		journal  string
+		expected Status
+	}{
+		{
+			name:     "kubelet pull failure",
+			journal:  `Mar 29 18:50:01 bootstrap kubelet[2001]: E0329 18:50:01.123 pod_workers.go:191] Error syncing pod: failed to "StartContainer" with ErrImagePull: "rpc error: Failed to pull image \"quay.io/ocp/release@sha256:8c5a\": manifest unknown"`,
+			expected: StatusFail,
+		},
+		{
+			name:     "podman unauthorized",
+			journal:  `Mar 29 18:45:32 bootstrap release-image-download.sh[1611]: Error: initializing source docker://registry.example.com/ocp/release@sha256:8c5a: reading manifest sha256:8c5a in registry.example.com/ocp/release: unauthorized: access to the requested resource is not authorized`,
+			expected: StatusFail,
+		},
+		{
+			name:     "mirror skipped",
+			journal:  `Mar 29 18:45:32 bootstrap crio[1500]: level=warning msg="Error pinging docker registry mirror.example.com:5000, trying the next mirror"`,
+			expected: StatusPass,
+		},
+		{
+			name:     "unrelated error",
+			journal:  `Mar 29 18:45:32 bootstrap crio[1500]: level=error msg="Failed to write the signature cache after initializing source docker://quay.io/ocp/release"`,
+			expected: StatusPass,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Bundle{files: map[string][]byte{"bootstrap/journals/kubelet.log": []byte(tc.journal)}}
+			assert.Equal(t, tc.expected, checkImagePull(b).Status)
+		})
+	}
+}
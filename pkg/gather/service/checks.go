@@ -0,0 +1,311 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Check inspects a bootstrap gather bundle for one class of problems.
+type Check struct {
+	// Name identifies the check in the report.
+	Name string
+	// Requires are the names of the checks that must pass before this check is run. Checks that follow from an
+	// earlier failure are skipped rather than reported again.
+	Requires []string
+	// Run inspects the bundle. The Check field of the returned finding is filled in by Analyze.
+	Run func(*Bundle) Finding
+}
+
+// DefaultChecks are the checks run by AnalyzeGatherBundle, in order.
+var DefaultChecks = []Check{
+	{Name: "release-image", Run: checkReleaseImageDownload},
+	{Name: "image-pull", Run: checkImagePull},
+	{Name: "bootkube", Requires: []string{"release-image"}, Run: checkBootkube},
+	{Name: "etcd", Requires: []string{"release-image"}, Run: checkEtcd},
+	{Name: "api-server", Requires: []string{"release-image"}, Run: checkAPIServer},
+	{Name: "csr-approval", Requires: []string{"release-image"}, Run: checkCSRApproval},
+}
+
+// Analyze runs the checks against the bundle.
+func Analyze(bundle *Bundle, checks []Check) *Report {
+	report := &Report{}
+	statuses := make(map[string]Status, len(checks))
+	for _, check := range checks {
+		var finding Finding
+		if unmet := unmetRequirement(check, statuses); unmet != "" {
+			finding = skip("the %s check did not pass", unmet)
+		} else {
+			finding = check.Run(bundle)
+		}
+		finding.Check = check.Name
+		statuses[check.Name] = finding.Status
+		report.Findings = append(report.Findings, finding)
+	}
+	return report
+}
+
+func unmetRequirement(check Check, statuses map[string]Status) string {
+	for _, name := range check.Requires {
+		if statuses[name] != StatusPass {
+			return name
+		}
+	}
+	return ""
+}
+
+func pass() Finding {
+	return Finding{Status: StatusPass}
+}
+
+func fail(details []string, format string, args ...interface{}) Finding {
+	return Finding{Status: StatusFail, Summary: fmt.Sprintf(format, args...), Details: details}
+}
+
+func skip(format string, args ...interface{}) Finding {
+	return Finding{Status: StatusSkip, Summary: fmt.Sprintf(format, args...)}
+}
+
+func notExecuted(service string) Finding {
+	return fail(nil, "The bootstrap machine did not execute the %s.service systemd unit", service)
+}
+
+func checkReleaseImageDownload(b *Bundle) Finding {
+	a := b.services["release-image"]
+	if a.starts == 0 {
+		return notExecuted("release-image")
+	}
+	if a.successful {
+		return pass()
+	}
+	return fail(a.lastErrorLines(), "The bootstrap machine failed to download the release image")
+}
+
+func checkBootkube(b *Bundle) Finding {
+	a, ok := b.services["bootkube"]
+	if !ok {
+		return skip("the bundle has no bootkube.service records")
+	}
+	if a.starts == 0 {
+		return notExecuted("bootkube")
+	}
+	switch {
+	case a.successful:
+		return pass()
+	case a.failed && a.failingStage != "":
+		return fail(a.lastErrorLines(), "The bootstrap machine failed to run bootkube.service in the %s stage", a.failingStage)
+	case a.failed:
+		return fail(a.lastErrorLines(), "The bootstrap machine failed to run bootkube.service")
+	case a.currentStage != "":
+		return fail(nil, "The bootkube.service systemd unit was still in the %s stage when the bundle was gathered", a.currentStage)
+	default:
+		return fail(nil, "The bootkube.service systemd unit did not complete")
+	}
+}
+
+var (
+	// etcdHealthRegex matches the output of etcdctl endpoint health.
+	etcdHealthRegex = regexp.MustCompile(`(\S+) is (healthy|unhealthy): (.*)$`)
+	// etcdRetryRegex matches the retries of wait_for_etcd_cluster in bootkube.sh.
+	etcdRetryRegex = regexp.MustCompile(`etcdctl failed\. Retrying`)
+)
+
+func checkEtcd(b *Bundle) Finding {
+	journal, ok := b.Journal("bootkube")
+	if !ok {
+		return skip("the bundle has no bootkube journal")
+	}
+
+	var endpoints []string
+	health := map[string][]string{}
+	retries := 0
+	for _, line := range journalLines(journal) {
+		if etcdRetryRegex.MatchString(line) {
+			retries++
+			continue
+		}
+		if m := etcdHealthRegex.FindStringSubmatch(line); m != nil {
+			if _, seen := health[m[1]]; !seen {
+				endpoints = append(endpoints, m[1])
+			}
+			health[m[1]] = m[2:]
+		}
+	}
+
+	var details []string
+	unhealthy := false
+	for _, endpoint := range endpoints {
+		if health[endpoint][0] == "unhealthy" {
+			unhealthy = true
+			details = append(details, fmt.Sprintf("%s: %s", endpoint, health[endpoint][1]))
+		}
+	}
+	if retries > 0 {
+		details = append(details, fmt.Sprintf("etcdctl endpoint health failed %d times", retries))
+	}
+
+	a := b.services["bootkube"]
+	waiting := a.currentStage == "wait-for-etcd" || (a.failed && a.failingStage == "wait-for-etcd")
+	switch {
+	case unhealthy:
+		return fail(details, "etcd on the bootstrap machine is unhealthy")
+	case waiting:
+		return fail(details, "The bootstrap machine did not observe a healthy etcd cluster")
+	case len(endpoints) == 0:
+		return skip("the bootkube journal has no etcd health checks")
+	default:
+		return pass()
+	}
+}
+
+var (
+	// apiServerRefusedRegex matches failed connections to the API server port.
+	apiServerRefusedRegex = regexp.MustCompile(`(?:dial tcp \S+:6443: connect: connection refused|The connection to the server \S+:6443 was refused)`)
+	// apiServerUpRegex matches cluster-bootstrap reporting that the API server is up.
+	apiServerUpRegex = regexp.MustCompile(`API is up`)
+)
+
+func checkAPIServer(b *Bundle) Finding {
+	var details []string
+	found, up := false, false
+	for _, unit := range []string{"bootkube", "kubelet", "approve-csr"} {
+		journal, ok := b.Journal(unit)
+		if !ok {
+			continue
+		}
+		found = true
+
+		refused, last := 0, ""
+		for _, line := range journalLines(journal) {
+			if apiServerUpRegex.MatchString(line) {
+				up = true
+			}
+			if apiServerRefusedRegex.MatchString(line) {
+				refused++
+				last = line
+			}
+		}
+		if refused > 0 {
+			details = append(details, fmt.Sprintf("%s: %d refused connections, the last was: %s", unit, refused, last))
+		}
+	}
+	switch {
+	case !found:
+		return skip("the bundle has no bootkube, kubelet or approve-csr journals")
+	case len(details) > 0 && !up:
+		return fail(details, "The API server on the bootstrap machine is not listening")
+	default:
+		return pass()
+	}
+}
+
+// csrList is the part of a CertificateSigningRequestList that the CSR approval check looks at.
+type csrList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Username string `json:"username"`
+		} `json:"spec"`
+		Status struct {
+			Conditions []struct {
+				Type string `json:"type"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+func checkCSRApproval(b *Bundle) Finding {
+	data, ok := b.File("resources/csr.json")
+	if !ok {
+		return skip("the bundle has no certificate signing requests")
+	}
+	var csrs csrList
+	if err := json.Unmarshal(data, &csrs); err != nil {
+		return skip("could not parse the certificate signing requests: %v", err)
+	}
+
+	var pending []string
+	for _, csr := range csrs.Items {
+		if len(csr.Status.Conditions) == 0 {
+			pending = append(pending, fmt.Sprintf("%s requested by %s", csr.Metadata.Name, csr.Spec.Username))
+		}
+	}
+	if len(pending) == 0 {
+		return pass()
+	}
+	summary := fmt.Sprintf("%d certificate signing requests are pending approval", len(pending))
+	if journal, ok := b.Journal("approve-csr"); ok {
+		if lines := journalLines(journal); len(lines) > 0 {
+			pending = append(pending, "approve-csr.service last logged: "+lines[len(lines)-1])
+		}
+	}
+	return fail(pending, summary)
+}
+
+var (
+	// imagePullFailureRegex matches the failures of image pulls by podman, CRI-O and the kubelet. Errors that
+	// merely mention a registry, like a mirror that was skipped for the next one, are not pull failures.
+	imagePullFailureRegex = regexp.MustCompile(`ErrImagePull|ImagePullBackOff|Failed to pull image|[Ee]rror pulling image|manifest unknown|unauthorized`)
+	// imagePullRegistryRegexes capture the registry of a failed image pull.
+	imagePullRegistryRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`pinging (?:container|docker) registry ([^/\s:"\\]+(?::\d+)?)`),
+		regexp.MustCompile(`initializing source docker://([^/\s"\\]+)/`),
+		regexp.MustCompile(`reading manifest \S+ in ([^/\s"\\]+)/`),
+		regexp.MustCompile(`(?:Failed to pull image|Error pulling image) \\?"([^/\s"\\]+)/`),
+	}
+)
+
+func checkImagePull(b *Bundle) Finding {
+	var registries []string
+	lastFailure := map[string]string{}
+	found := false
+	for _, unit := range []string{"release-image", "crio", "kubelet", "bootkube"} {
+		journal, ok := b.Journal(unit)
+		if !ok {
+			continue
+		}
+		found = true
+		for _, line := range journalLines(journal) {
+			if !imagePullFailureRegex.MatchString(line) {
+				continue
+			}
+			for _, re := range imagePullRegistryRegexes {
+				m := re.FindStringSubmatch(line)
+				if m == nil {
+					continue
+				}
+				if _, seen := lastFailure[m[1]]; !seen {
+					registries = append(registries, m[1])
+				}
+				lastFailure[m[1]] = line
+				break
+			}
+		}
+	}
+	if !found {
+		return skip("the bundle has no release-image, crio, kubelet or bootkube journals")
+	}
+	if len(registries) == 0 {
+		return pass()
+	}
+	details := make([]string, 0, len(registries))
+	for _, registry := range registries {
+		details = append(details, fmt.Sprintf("%s: %s", registry, lastFailure[registry]))
+	}
+	details = append(details, "Check that the registries, including any imageContentSources mirrors, are reachable from the bootstrap machine and that the pull secret has credentials for them")
+	return fail(details, "The bootstrap machine failed to pull images from %s", strings.Join(registries, ", "))
+}
+
+// journalLines returns the non-empty lines of a journal.
+func journalLines(journal string) []string {
+	var lines []string
+	for _, line := range strings.Split(journal, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
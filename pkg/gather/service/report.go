@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass indicates that the check found no problems.
+	StatusPass Status = "pass"
+	// StatusFail indicates that the check found a problem.
+	StatusFail Status = "fail"
+	// StatusSkip indicates that the check did not run, either because the bundle lacks the data for it or because a
+	// check that it requires did not pass.
+	StatusSkip Status = "skip"
+)
+
+// Finding is the outcome of a single check.
+type Finding struct {
+	// Check is the name of the check.
+	Check string `json:"check"`
+	// Status is the outcome of the check.
+	Status Status `json:"status"`
+	// Summary is a one-line description of the outcome.
+	Summary string `json:"summary,omitempty"`
+	// Details are supporting lines, such as the last error output of a failing service.
+	Details []string `json:"details,omitempty"`
+}
+
+// Report is the analysis of a bootstrap gather bundle.
+type Report struct {
+	// Findings are the outcomes of the checks, in the order the checks were run.
+	Findings []Finding `json:"findings"`
+}
+
+// Failed returns true if any check found a problem.
+func (r *Report) Failed() bool {
+	for _, f := range r.Findings {
+		if f.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes a human-readable form of the report.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, f := range r.Findings {
+		line := fmt.Sprintf("[%s] %s", f.Status, f.Check)
+		if f.Summary != "" {
+			line += ": " + f.Summary
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, d := range f.Details {
+			if _, err := fmt.Fprintf(w, "    %s\n", d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Log logs the report. Failures are logged as errors followed by their details, and skipped checks are only logged
+// at debug level.
+func (r *Report) Log() {
+	for _, f := range r.Findings {
+		switch f.Status {
+		case StatusFail:
+			logrus.Error(f.Summary)
+			for _, d := range f.Details {
+				logrus.Info(d)
+			}
+		case StatusSkip:
+			logrus.Debugf("Skipped the %s check: %s", f.Check, f.Summary)
+		default:
+			logrus.Debugf("Passed the %s check", f.Check)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/conversion"
+)
+
+// unknownFields returns an issue for each field of the node that is not in
+// the schema.
+func unknownFields(node *yamlv3.Node, schema *apiextv1.JSONSchemaProps, path *field.Path) []Issue {
+	if node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	var issues []Issue
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		if len(schema.Properties) == 0 {
+			if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema == nil {
+				return nil
+			}
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				issues = append(issues, unknownFields(node.Content[i+1], schema.AdditionalProperties.Schema, path.Key(node.Content[i].Value))...)
+			}
+			return issues
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			childPath := field.NewPath(key.Value)
+			if path != nil {
+				childPath = path.Child(key.Value)
+			}
+			property, ok := schema.Properties[key.Value]
+			if !ok {
+				issue := Issue{
+					Rule:     RuleUnknownField,
+					Severity: SeverityWarning,
+					Field:    childPath.String(),
+					Line:     key.Line,
+					Column:   key.Column,
+					Message:  "the installer ignores unknown fields",
+				}
+				names := make([]string, 0, len(schema.Properties))
+				for name := range schema.Properties {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				if closest := closest(key.Value, names); closest != "" {
+					issue.Suggestion = fmt.Sprintf("did you mean %q?", closest)
+				}
+				issues = append(issues, issue)
+				continue
+			}
+			issues = append(issues, unknownFields(node.Content[i+1], &property, childPath)...)
+		}
+	case yamlv3.SequenceNode:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range node.Content {
+			issues = append(issues, unknownFields(item, schema.Items.Schema, path.Index(i))...)
+		}
+	}
+	return issues
+}
+
+// deprecatedFields returns an issue for each deprecated field of the
+// document.
+func deprecatedFields(root *yamlv3.Node) []Issue {
+	var issues []Issue
+	for _, deprecated := range conversion.DeprecatedFields {
+		for _, m := range find(root, deprecated.Path) {
+			issues = append(issues, Issue{
+				Rule:       RuleDeprecatedField,
+				Severity:   SeverityWarning,
+				Field:      m.path,
+				Line:       m.key.Line,
+				Column:     m.key.Column,
+				Message:    "the field is deprecated",
+				Suggestion: fmt.Sprintf("use %s instead", strings.Replace(deprecated.Replacement, "[*]", indexOf(m.path), 1)),
+			})
+		}
+	}
+	return issues
+}
+
+// indexOf returns the first list index of the path, like "[0]", or "[*]" if
+// there is none.
+func indexOf(path string) string {
+	for _, segment := range pathSegments(path) {
+		if segment[0] == '[' {
+			if _, err := strconv.Atoi(segment[1 : len(segment)-1]); err == nil {
+				return segment
+			}
+		}
+	}
+	return "[*]"
+}
+
+// closest returns the candidate that is the most likely intended in place of
+// value, or "" if none is close enough.
+func closest(value string, candidates []string) string {
+	best, bestDistance := "", len(value)/3+1
+	for _, candidate := range candidates {
+		if strings.EqualFold(value, candidate) {
+			return candidate
+		}
+		if distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate)); distance <= bestDistance && (best == "" || distance < bestDistance) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,213 @@
+// Package lint checks install configs without contacting any cloud, and
+// reports all of their problems with the positions of the fields in the file.
+// It also migrates the deprecated fields of install configs.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	icopenstack "github.com/openshift/installer/pkg/asset/installconfig/openstack"
+	"github.com/openshift/installer/pkg/explain"
+	"github.com/openshift/installer/pkg/types"
+	baremetalvalidation "github.com/openshift/installer/pkg/types/baremetal/validation"
+	"github.com/openshift/installer/pkg/types/conversion"
+	"github.com/openshift/installer/pkg/types/defaults"
+	"github.com/openshift/installer/pkg/types/validation"
+	vspherevalidation "github.com/openshift/installer/pkg/types/vsphere/validation"
+)
+
+// Severity is how serious an issue is.
+type Severity string
+
+const (
+	// SeverityError is the severity of issues that make the installer
+	// reject the install config.
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of issues that the installer
+	// tolerates, but that are likely mistakes or will stop being tolerated.
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	// RuleSyntax is the rule of issues with the YAML syntax of the file.
+	RuleSyntax = "YAMLSyntax"
+	// RuleDecode is the rule of issues decoding the file into an install
+	// config, like a string where a list is expected.
+	RuleDecode = "DecodeError"
+	// RuleUnknownField is the rule of fields that the install config does
+	// not have, which the installer ignores.
+	RuleUnknownField = "UnknownField"
+	// RuleDeprecatedField is the rule of deprecated fields that the
+	// installer upconverts.
+	RuleDeprecatedField = "DeprecatedField"
+)
+
+// Issue is a problem of an install config.
+type Issue struct {
+	// Rule identifies the kind of problem. Validation errors use the type of
+	// the field error, for example "FieldValueInvalid".
+	Rule string `json:"rule"`
+	// Severity is how serious the problem is.
+	Severity Severity `json:"severity"`
+	// Field is the path of the field, for example "compute[0].replicas".
+	Field string `json:"field,omitempty"`
+	// Line is the line of the field in the file, starting at 1. It is 0 if
+	// the position is unknown. When the field is not in the file, it is the
+	// position of its closest parent that is.
+	Line int `json:"line,omitempty"`
+	// Column is the column of the field in the file, starting at 1.
+	Column int `json:"column,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Suggestion describes a likely fix, if there is one.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Report is the result of checking an install config.
+type Report struct {
+	// File is the name of the checked file.
+	File string `json:"file"`
+	// Issues are the problems found, in the order of their positions.
+	Issues []Issue `json:"issues"`
+}
+
+// Errors returns the number of issues with the error severity.
+func (r *Report) Errors() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+var yamlErrorLineRegex = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// InstallConfig checks the install config in data, read from filename.
+// It runs the same conversion, defaulting and validation as the installer,
+// as well as the offline validations of the platforms for provisioning the
+// infrastructure, and also flags unknown and deprecated fields.
+func InstallConfig(filename string, data []byte) (*Report, error) {
+	report := &Report{File: filename, Issues: []Issue{}}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		issue := Issue{Rule: RuleSyntax, Severity: SeverityError, Message: err.Error()}
+		if submatch := yamlErrorLineRegex.FindStringSubmatch(err.Error()); submatch != nil {
+			issue.Line, _ = strconv.Atoi(submatch[1])
+			issue.Message = strings.TrimPrefix(err.Error(), submatch[0])
+		}
+		report.Issues = append(report.Issues, issue)
+		return report, nil
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yamlv3.MappingNode {
+		report.Issues = append(report.Issues, Issue{Rule: RuleSyntax, Severity: SeverityError, Message: "the file is not a YAML mapping"})
+		return report, nil
+	}
+	root := document.Content[0]
+
+	schema, err := explain.InstallConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+	report.Issues = append(report.Issues, unknownFields(root, schema, nil)...)
+	report.Issues = append(report.Issues, deprecatedFields(root)...)
+
+	config := &types.InstallConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		report.Issues = append(report.Issues, Issue{Rule: RuleDecode, Severity: SeverityError, Message: err.Error()})
+		return report.sorted(), nil
+	}
+
+	if err := conversion.ConvertInstallConfig(config); err != nil {
+		report.Issues = append(report.Issues, errorIssues(root, err, SeverityError)...)
+		return report.sorted(), nil
+	}
+	defaults.SetInstallConfigDefaults(config)
+	for _, err := range validation.ValidateInstallConfig(config) {
+		report.Issues = append(report.Issues, fieldErrorIssue(root, err, SeverityError))
+	}
+	for _, err := range provisioningErrors(config) {
+		report.Issues = append(report.Issues, fieldErrorIssue(root, err, SeverityWarning))
+	}
+	return report.sorted(), nil
+}
+
+// provisioningErrors runs the offline validations that the installer only
+// runs when it provisions the infrastructure of the cluster.
+func provisioningErrors(config *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	case config.Platform.BareMetal != nil:
+		allErrs = append(allErrs, baremetalvalidation.ValidateProvisioning(config.Platform.BareMetal, config.Networking, field.NewPath("platform", "baremetal"))...)
+	case config.Platform.OpenStack != nil:
+		if err, ok := icopenstack.ValidateForProvisioning(config).(*field.Error); ok {
+			allErrs = append(allErrs, err)
+		}
+	case config.Platform.VSphere != nil:
+		allErrs = append(allErrs, vspherevalidation.ValidateForProvisioning(config.Platform.VSphere, field.NewPath("platform", "vsphere"))...)
+	}
+	return allErrs
+}
+
+// errorIssues converts an error, usually a field error, to issues.
+func errorIssues(root *yamlv3.Node, err error, severity Severity) []Issue {
+	var fieldErr *field.Error
+	if errors.As(err, &fieldErr) {
+		return []Issue{fieldErrorIssue(root, fieldErr, severity)}
+	}
+	return []Issue{{Rule: RuleDecode, Severity: severity, Message: err.Error()}}
+}
+
+func fieldErrorIssue(root *yamlv3.Node, err *field.Error, severity Severity) Issue {
+	issue := Issue{
+		Rule:     string(err.Type),
+		Severity: severity,
+		Field:    err.Field,
+		Message:  err.ErrorBody(),
+	}
+	issue.Line, issue.Column = locate(root, err.Field)
+	switch err.Type {
+	case field.ErrorTypeRequired:
+		issue.Suggestion = fmt.Sprintf("set %s", err.Field)
+	case field.ErrorTypeNotSupported:
+		if closest := closest(fmt.Sprint(err.BadValue), quotedValues(err.Detail)); closest != "" {
+			issue.Suggestion = fmt.Sprintf("did you mean %q?", closest)
+		}
+	case field.ErrorTypeDuplicate:
+		issue.Suggestion = "remove the duplicate"
+	}
+	return issue
+}
+
+var quotedValueRegex = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// quotedValues returns the quoted values of the detail of a field error,
+// like the supported values of a NotSupported error.
+func quotedValues(detail string) []string {
+	var values []string
+	for _, submatch := range quotedValueRegex.FindAllStringSubmatch(detail, -1) {
+		values = append(values, submatch[1])
+	}
+	return values
+}
+
+func (r *Report) sorted() *Report {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		if r.Issues[i].Line != r.Issues[j].Line {
+			return r.Issues[i].Line < r.Issues[j].Line
+		}
+		return r.Issues[i].Column < r.Issues[j].Column
+	})
+	return r
+}
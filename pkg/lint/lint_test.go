@@ -0,0 +1,201 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/data"
+)
+
+const validInstallConfig = `apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: test-cluster
+compute:
+- name: worker
+  replicas: 3
+controlPlane:
+  name: master
+  replicas: 3
+networking:
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+  networkType: OpenShiftSDN
+platform:
+  aws:
+    region: us-east-1
+pullSecret: '{"auths":{"example.com":{"auth":"YQ=="}}}'
+`
+
+func TestInstallConfig(t *testing.T) {
+	data.Assets = http.Dir("../../data/data")
+
+	cases := []struct {
+		name     string
+		config   string
+		expected []Issue
+	}{{
+		name:     "valid",
+		config:   validInstallConfig,
+		expected: []Issue{},
+	}, {
+		name:   "syntax error",
+		config: "apiVersion: v1\nbaseDomain: example.com\n  name: test-cluster\n",
+		expected: []Issue{{
+			Rule:     RuleSyntax,
+			Severity: SeverityError,
+			Line:     3,
+			Message:  "mapping values are not allowed in this context",
+		}},
+	}, {
+		name:   "not a mapping",
+		config: "- apiVersion: v1\n",
+		expected: []Issue{{
+			Rule:     RuleSyntax,
+			Severity: SeverityError,
+			Message:  "the file is not a YAML mapping",
+		}},
+	}, {
+		name:   "unknown field",
+		config: strings.Replace(validInstallConfig, "- name: worker\n", "- name: worker\n  hyperthreding: Enabled\n", 1),
+		expected: []Issue{{
+			Rule:       RuleUnknownField,
+			Severity:   SeverityWarning,
+			Field:      "compute[0].hyperthreding",
+			Line:       7,
+			Column:     3,
+			Message:    "the installer ignores unknown fields",
+			Suggestion: `did you mean "hyperthreading"?`,
+		}},
+	}, {
+		name: "deprecated field",
+		config: `apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: test-cluster
+networking:
+  machineCIDR: 10.0.0.0/16
+platform:
+  aws:
+    region: us-east-1
+pullSecret: '{"auths":{"example.com":{"auth":"YQ=="}}}'
+`,
+		expected: []Issue{{
+			Rule:       RuleDeprecatedField,
+			Severity:   SeverityWarning,
+			Field:      "networking.machineCIDR",
+			Line:       6,
+			Column:     3,
+			Message:    "the field is deprecated",
+			Suggestion: "use networking.machineNetwork instead",
+		}},
+	}, {
+		name:   "unsupported value",
+		config: validInstallConfig + "publish: Extrnal\n",
+		expected: []Issue{{
+			Rule:       "FieldValueNotSupported",
+			Severity:   SeverityError,
+			Field:      "publish",
+			Line:       19,
+			Column:     1,
+			Message:    `Unsupported value: "Extrnal": supported values: "External", "Internal"`,
+			Suggestion: `did you mean "External"?`,
+		}},
+	}, {
+		name:   "missing field",
+		config: strings.Replace(validInstallConfig, "apiVersion: v1\n", "", 1),
+		expected: []Issue{{
+			Rule:       "FieldValueRequired",
+			Severity:   SeverityError,
+			Field:      "apiVersion",
+			Message:    "Required value: no version was provided",
+			Suggestion: "set apiVersion",
+		}},
+	}, {
+		name:   "invalid nested value",
+		config: strings.Replace(validInstallConfig, "name: master\n  replicas: 3", "name: master\n  replicas: 0", 1),
+		expected: []Issue{{
+			Rule:     "FieldValueInvalid",
+			Severity: SeverityError,
+			Field:    "controlPlane.replicas",
+			Line:     10,
+			Column:   3,
+			Message:  "Invalid value: 0: number of control plane replicas must be positive",
+		}},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := InstallConfig("install-config.yaml", []byte(tc.config))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "install-config.yaml", report.File)
+			assert.Equal(t, tc.expected, report.Issues)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{
+		File: "install-config.yaml",
+		Issues: []Issue{{
+			Rule:       RuleUnknownField,
+			Severity:   SeverityWarning,
+			Field:      "compute[0].hyperthreding",
+			Line:       8,
+			Column:     3,
+			Message:    "the installer ignores unknown fields",
+			Suggestion: `did you mean "hyperthreading"?`,
+		}, {
+			Rule:     "FieldValueRequired",
+			Severity: SeverityError,
+			Field:    "pullSecret",
+			Message:  "Required value: cluster pull secret is required",
+		}},
+	}
+	assert.Equal(t, 1, report.Errors())
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, report.WriteText(&buf))
+		assert.Equal(t, `install-config.yaml:8:3: warning: compute[0].hyperthreding: the installer ignores unknown fields [UnknownField]
+    suggestion: did you mean "hyperthreading"?
+install-config.yaml: error: pullSecret: Required value: cluster pull secret is required [FieldValueRequired]
+`, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, report.WriteJSON(&buf))
+		decoded := &Report{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+		assert.Equal(t, report, decoded)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, report.WriteSARIF(&buf))
+		decoded := &sarifLog{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+		assert.Equal(t, "2.1.0", decoded.Version)
+		assert.Len(t, decoded.Runs, 1)
+		run := decoded.Runs[0]
+		assert.Equal(t, []sarifRule{{ID: "FieldValueRequired"}, {ID: RuleUnknownField}}, run.Tool.Driver.Rules)
+		assert.Len(t, run.Results, 2)
+		assert.Equal(t, sarifResult{
+			RuleID:  RuleUnknownField,
+			Level:   "warning",
+			Message: sarifMessage{Text: `compute[0].hyperthreding: the installer ignores unknown fields (suggestion: did you mean "hyperthreading"?)`},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "install-config.yaml"},
+				Region:           &sarifRegion{StartLine: 8, StartColumn: 3},
+			}}},
+		}, run.Results[0])
+		assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+	})
+}
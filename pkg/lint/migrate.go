@@ -0,0 +1,491 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	operv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/baremetal"
+	"github.com/openshift/installer/pkg/types/conversion"
+)
+
+// Change is a change made by migrating an install config.
+type Change struct {
+	// Field is the path of the changed field in the original install
+	// config.
+	Field string
+	// Message describes the change.
+	Message string
+}
+
+// migrations are the rewrites of the fields that ConvertInstallConfig
+// upconverts, in the order they are applied. They include every field of
+// conversion.DeprecatedFields.
+var migrations = []struct {
+	path    string
+	migrate func(*migration, match)
+}{
+	{path: "apiVersion", migrate: migrateAPIVersion},
+	{path: "networking.clusterNetworks", migrate: migrateClusterNetworks},
+	{path: "networking.clusterNetworks[*].hostSubnetLength", migrate: migrateHostSubnetLength},
+	{path: "networking.clusterNetwork[*].hostSubnetLength", migrate: migrateHostSubnetLength},
+	{path: "networking.machineCIDR", migrate: migrateMachineCIDR},
+	{path: "networking.serviceCIDR", migrate: migrateServiceCIDR},
+	{path: "networking.type", migrate: migrateNetworkingType},
+	{path: "networking.networkType", migrate: migrateNetworkType},
+	{path: "platform.baremetal.provisioningDHCPExternal", migrate: migrateProvisioningDHCPExternal},
+	{path: "platform.baremetal.provisioningHostIP", migrate: renameTo("clusterProvisioningIP")},
+	{path: "platform.openstack.computeFlavor", migrate: migrateComputeFlavor},
+	{path: "platform.openstack.lbFloatingIP", migrate: renameTo("apiFloatingIP")},
+}
+
+// MigrateInstallConfig rewrites the deprecated fields of the install config
+// in data to the fields that replace them, the same way ConvertInstallConfig
+// upconverts them when the installer loads the install config. Only the
+// lines of the deprecated fields are rewritten, so the comments, the order
+// of the fields and the formatting of the rest of the file are kept.
+// It returns the migrated install config and the changes made, which are
+// none when the install config is up to date.
+func MigrateInstallConfig(data []byte) ([]byte, []Change, error) {
+	// Converting first reports the conflicts between the deprecated fields
+	// and the fields that replace them, like the installer does, so the
+	// migrations below can assume there are none.
+	config := &types.InstallConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unmarshal install config")
+	}
+	if err := conversion.ConvertInstallConfig(config); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to upconvert install config")
+	}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse install config")
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yamlv3.MappingNode {
+		return nil, nil, errors.New("the install config is not a YAML mapping")
+	}
+
+	m := &migration{root: document.Content[0], lines: strings.Split(string(data), "\n")}
+	for _, migration := range migrations {
+		for _, field := range find(m.root, migration.path) {
+			migration.migrate(m, field)
+		}
+	}
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	if len(m.changes) == 0 {
+		return data, nil, nil
+	}
+
+	migrated := []byte(m.apply())
+	if err := yamlv3.Unmarshal(migrated, &yamlv3.Node{}); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to rewrite install config")
+	}
+	return migrated, m.changes, nil
+}
+
+// migration collects the edits of the lines of an install config.
+type migration struct {
+	root    *yamlv3.Node
+	lines   []string
+	inline  []inlineEdit
+	block   []blockEdit
+	changes []Change
+	// err is the first error of the edits. The migrations stop editing once
+	// it is set.
+	err error
+}
+
+// inlineEdit replaces the text of a scalar in its line.
+type inlineEdit struct {
+	line, column, length int
+	text                 string
+}
+
+// blockEdit replaces the lines from start up to end, excluded.
+type blockEdit struct {
+	start, end int
+	lines      []string
+}
+
+func (m *migration) change(field match, format string, args ...interface{}) {
+	m.changes = append(m.changes, Change{Field: field.path, Message: fmt.Sprintf(format, args...)})
+}
+
+// setText replaces the text of the scalar node, a key or a value.
+func (m *migration) setText(node *yamlv3.Node, text string) {
+	if m.err != nil {
+		return
+	}
+	raw := node.Value
+	switch node.Style {
+	case 0, yamlv3.TaggedStyle:
+	case yamlv3.DoubleQuotedStyle:
+		raw = strconv.Quote(node.Value)
+	case yamlv3.SingleQuotedStyle:
+		raw = "'" + strings.Replace(node.Value, "'", "''", -1) + "'"
+	default:
+		m.err = errors.Errorf("cannot rewrite %q at line %d", node.Value, node.Line)
+		return
+	}
+	line, column := node.Line-1, node.Column-1
+	if !strings.HasPrefix(m.lines[line][column:], raw) {
+		m.err = errors.Errorf("cannot rewrite %q at line %d", node.Value, node.Line)
+		return
+	}
+	if node.Style == yamlv3.DoubleQuotedStyle || node.Style == yamlv3.SingleQuotedStyle {
+		text = raw[:1] + text + raw[:1]
+	}
+	m.inline = append(m.inline, inlineEdit{line: line, column: column, length: len(raw), text: text})
+}
+
+// replaceField replaces the field of key, with its value, by the fields of
+// the mapping node, or removes it if fields is nil.
+func (m *migration) replaceField(parent, key *yamlv3.Node, fields *yamlv3.Node) {
+	if m.err != nil {
+		return
+	}
+	if parent.Style&yamlv3.FlowStyle != 0 {
+		m.err = errors.Errorf("cannot rewrite %q at line %d in a flow mapping", key.Value, key.Line)
+		return
+	}
+	start, column := key.Line-1, key.Column-1
+	end := m.fieldEnd(key, valueOf(parent, key))
+	prefix := m.lines[start][:column]
+
+	if fields == nil {
+		if strings.TrimSpace(prefix) == "" {
+			m.block = append(m.block, blockEdit{start: start, end: end})
+			return
+		}
+		// The field starts a list item, like "- hostSubnetLength: 9", so
+		// the next field of the item takes its place.
+		if end < len(m.lines) && indentation(m.lines[end]) == column {
+			m.block = append(m.block, blockEdit{start: start, end: end + 1, lines: []string{prefix + strings.TrimLeft(m.lines[end], " ")}})
+		} else {
+			m.block = append(m.block, blockEdit{start: start, end: end, lines: []string{prefix + "{}"}})
+		}
+		return
+	}
+
+	lines, err := render(fields)
+	if err != nil {
+		m.err = err
+		return
+	}
+	for i := range lines {
+		if i == 0 {
+			lines[i] = prefix + lines[i]
+		} else {
+			lines[i] = strings.Repeat(" ", column) + lines[i]
+		}
+	}
+	m.block = append(m.block, blockEdit{start: start, end: end, lines: lines})
+}
+
+// addFields adds the fields of the mapping node after the last field of
+// the mapping parent.
+func (m *migration) addFields(parent *yamlv3.Node, fields *yamlv3.Node) {
+	if m.err != nil {
+		return
+	}
+	if parent.Style&yamlv3.FlowStyle != 0 || len(parent.Content) == 0 {
+		m.err = errors.Errorf("cannot add fields to the mapping at line %d", parent.Line)
+		return
+	}
+	last := parent.Content[len(parent.Content)-2]
+	end := m.fieldEnd(last, parent.Content[len(parent.Content)-1])
+	lines, err := render(fields)
+	if err != nil {
+		m.err = err
+		return
+	}
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", last.Column-1) + lines[i]
+	}
+	m.block = append(m.block, blockEdit{start: end, end: end, lines: lines})
+}
+
+// fieldEnd returns the index of the line after the last line of the field
+// of key.
+func (m *migration) fieldEnd(key, value *yamlv3.Node) int {
+	column := key.Column - 1
+	end := key.Line
+	for i := key.Line; i < len(m.lines); i++ {
+		trimmed := strings.TrimSpace(m.lines[i])
+		if trimmed == "" {
+			continue
+		}
+		indent := indentation(m.lines[i])
+		// Lists may be indented as much as the key of their field.
+		if indent > column || indent == column && value.Kind == yamlv3.SequenceNode && (trimmed == "-" || strings.HasPrefix(trimmed, "- ")) {
+			end = i + 1
+			continue
+		}
+		break
+	}
+	return end
+}
+
+// apply returns the lines of the install config with the edits applied.
+func (m *migration) apply() string {
+	lines := append([]string(nil), m.lines...)
+
+	// The inline edits do not change the lines, so they are applied first,
+	// from the end of each line.
+	sort.Slice(m.inline, func(i, j int) bool {
+		if m.inline[i].line != m.inline[j].line {
+			return m.inline[i].line > m.inline[j].line
+		}
+		return m.inline[i].column > m.inline[j].column
+	})
+	for _, edit := range m.inline {
+		line := lines[edit.line]
+		lines[edit.line] = line[:edit.column] + edit.text + line[edit.column+edit.length:]
+	}
+
+	// The block edits are applied from the end of the file, and lines
+	// inserted where others are replaced go after them.
+	sort.SliceStable(m.block, func(i, j int) bool {
+		if m.block[i].start != m.block[j].start {
+			return m.block[i].start > m.block[j].start
+		}
+		return m.block[i].end > m.block[j].end
+	})
+	for _, edit := range m.block {
+		lines = append(append(append([]string(nil), lines[:edit.start]...), edit.lines...), lines[edit.end:]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func migrateAPIVersion(m *migration, field match) {
+	if field.value.Value != types.InstallConfigVersion {
+		m.setText(field.value, types.InstallConfigVersion)
+		m.change(field, "upgraded from %s to %s", field.value.Value, types.InstallConfigVersion)
+	}
+}
+
+func migrateClusterNetworks(m *migration, field match) {
+	if key, value := child(field.parent, "clusterNetwork"); key != nil {
+		if !isEmpty(value) {
+			m.replaceField(field.parent, field.key, nil)
+			m.change(field, "removed, networking.clusterNetwork is set")
+			return
+		}
+		m.replaceField(field.parent, key, nil)
+	}
+	m.setText(field.key, "clusterNetwork")
+	m.change(field, "renamed to networking.clusterNetwork")
+}
+
+func migrateHostSubnetLength(m *migration, field match) {
+	if strings.HasPrefix(field.path, "networking.clusterNetworks[") {
+		_, networking := child(m.root, "networking")
+		if _, value := child(networking, "clusterNetwork"); value != nil && !isEmpty(value) {
+			// The deprecated cluster networks are removed.
+			return
+		}
+	}
+
+	if key, value := child(field.parent, "hostPrefix"); key != nil && value.Value != "0" {
+		m.replaceField(field.parent, field.key, nil)
+		m.change(field, "removed, hostPrefix is set")
+		return
+	}
+	var hostSubnetLength int32
+	if err := field.value.Decode(&hostSubnetLength); err != nil {
+		m.err = errors.Wrapf(err, "invalid %s", field.path)
+		return
+	}
+	_, cidrValue := child(field.parent, "cidr")
+	if cidrValue == nil {
+		m.err = errors.Errorf("%s is set without a cidr", field.path)
+		return
+	}
+	_, cidr, err := net.ParseCIDR(cidrValue.Value)
+	if err != nil {
+		m.err = errors.Wrapf(err, "invalid cidr for %s", field.path)
+		return
+	}
+	_, size := cidr.Mask.Size()
+	hostPrefix := strconv.Itoa(size - int(hostSubnetLength))
+
+	if key, value := child(field.parent, "hostPrefix"); key != nil {
+		m.setText(value, hostPrefix)
+		m.replaceField(field.parent, field.key, nil)
+	} else {
+		m.setText(field.value, hostPrefix)
+		m.setText(field.key, "hostPrefix")
+	}
+	m.change(field, "replaced by hostPrefix %s", hostPrefix)
+}
+
+func migrateMachineCIDR(m *migration, field match) {
+	migrateCIDR(m, field, "machineNetwork", &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: []*yamlv3.Node{
+		mapping("cidr", copyValue(field.value)),
+	}})
+}
+
+func migrateServiceCIDR(m *migration, field match) {
+	migrateCIDR(m, field, "serviceNetwork", &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: []*yamlv3.Node{
+		copyValue(field.value),
+	}})
+}
+
+// migrateCIDR replaces the deprecated CIDR of the field by the network list
+// named replacement with the value, unless that list is set.
+func migrateCIDR(m *migration, field match, replacement string, value *yamlv3.Node) {
+	if key, existing := child(field.parent, replacement); key != nil {
+		if !isEmpty(existing) {
+			m.replaceField(field.parent, field.key, nil)
+			m.change(field, "removed, networking.%s is set", replacement)
+			return
+		}
+		m.replaceField(field.parent, key, nil)
+	}
+	m.replaceField(field.parent, field.key, mapping(replacement, value))
+	m.change(field, "replaced by networking.%s", replacement)
+}
+
+func migrateNetworkingType(m *migration, field match) {
+	if key, value := child(field.parent, "networkType"); key != nil {
+		if value.Value != "" {
+			m.replaceField(field.parent, field.key, nil)
+			m.change(field, "removed, networking.networkType is set")
+			return
+		}
+		m.replaceField(field.parent, key, nil)
+	}
+	m.setText(field.key, "networkType")
+	m.change(field, "renamed to networking.networkType")
+	migrateNetworkType(m, field)
+}
+
+func migrateNetworkType(m *migration, field match) {
+	sdn := string(operv1.NetworkTypeOpenShiftSDN)
+	if field.value.Value != sdn && strings.EqualFold(field.value.Value, sdn) {
+		m.setText(field.value, sdn)
+		m.change(field, "set to %s", sdn)
+	}
+}
+
+func migrateProvisioningDHCPExternal(m *migration, field match) {
+	var external bool
+	if err := field.value.Decode(&external); err != nil {
+		m.err = errors.Wrapf(err, "invalid %s", field.path)
+		return
+	}
+	if key, value := child(field.parent, "provisioningNetwork"); !external || key != nil && value.Value != "" {
+		m.replaceField(field.parent, field.key, nil)
+		m.change(field, "removed")
+		return
+	} else if key != nil {
+		m.replaceField(field.parent, key, nil)
+	}
+	network := string(baremetal.UnmanagedProvisioningNetwork)
+	m.replaceField(field.parent, field.key, mapping("provisioningNetwork", &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: network}))
+	m.change(field, "replaced by provisioningNetwork %s", network)
+}
+
+// renameTo returns a migration that renames a deprecated string field to
+// replacement, or removes it if replacement is set.
+func renameTo(replacement string) func(*migration, match) {
+	return func(m *migration, field match) {
+		if key, value := child(field.parent, replacement); key != nil {
+			if value.Value != "" {
+				m.replaceField(field.parent, field.key, nil)
+				m.change(field, "removed, %s is set", replacement)
+				return
+			}
+			m.replaceField(field.parent, key, nil)
+		}
+		m.setText(field.key, replacement)
+		m.change(field, "renamed to %s", replacement)
+	}
+}
+
+func migrateComputeFlavor(m *migration, field match) {
+	key, value := child(field.parent, "defaultMachinePlatform")
+	switch {
+	case key == nil:
+	case value.Kind != yamlv3.MappingNode || len(value.Content) == 0:
+		m.replaceField(field.parent, key, nil)
+	default:
+		if typeKey, _ := child(value, "type"); typeKey != nil {
+			m.replaceField(field.parent, field.key, nil)
+			m.change(field, "removed, defaultMachinePlatform.type is set")
+			return
+		}
+		m.addFields(value, mapping("type", copyValue(field.value)))
+		m.replaceField(field.parent, field.key, nil)
+		m.change(field, "replaced by defaultMachinePlatform.type")
+		return
+	}
+	m.replaceField(field.parent, field.key, mapping("defaultMachinePlatform", mapping("type", copyValue(field.value))))
+	m.change(field, "replaced by defaultMachinePlatform.type")
+}
+
+// valueOf returns the value of key in the mapping.
+func valueOf(mapping, key *yamlv3.Node) *yamlv3.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isEmpty returns whether the node is null or an empty list or mapping.
+func isEmpty(node *yamlv3.Node) bool {
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		return node.Tag == "!!null"
+	case yamlv3.SequenceNode, yamlv3.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// mapping returns a mapping node with a single field.
+func mapping(key string, value *yamlv3.Node) *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.MappingNode, Content: []*yamlv3.Node{
+		{Kind: yamlv3.ScalarNode, Value: key},
+		value,
+	}}
+}
+
+// copyValue returns a copy of the scalar node without its position and the
+// comments around it, keeping the comment on its line.
+func copyValue(node *yamlv3.Node) *yamlv3.Node {
+	return &yamlv3.Node{Kind: node.Kind, Style: node.Style, Tag: node.Tag, Value: node.Value, LineComment: node.LineComment}
+}
+
+// render returns the lines of the YAML of node.
+func render(node *yamlv3.Node) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, errors.Wrap(err, "failed to render migrated fields")
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to render migrated fields")
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// indentation returns the number of spaces at the start of the line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
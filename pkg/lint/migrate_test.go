@@ -0,0 +1,233 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/conversion"
+)
+
+func TestMigrateInstallConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		expected string
+		changed  []string
+		err      string
+	}{{
+		name: "up to date",
+		config: `apiVersion: v1
+networking:
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+`,
+		expected: `apiVersion: v1
+networking:
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+`,
+	}, {
+		name: "networking",
+		config: `apiVersion: v1beta4
+baseDomain: example.com
+networking:
+  # The pod networks.
+  clusterNetworks:
+  - cidr: 10.128.0.0/14
+    hostSubnetLength: 9
+  machineCIDR: 10.0.0.0/16 # the machines
+  serviceCIDR: "172.30.0.0/16"
+  type: openshiftSDN
+pullSecret: '{}'
+`,
+		expected: `apiVersion: v1
+baseDomain: example.com
+networking:
+  # The pod networks.
+  clusterNetwork:
+  - cidr: 10.128.0.0/14
+    hostPrefix: 23
+  machineNetwork:
+    - cidr: 10.0.0.0/16 # the machines
+  serviceNetwork:
+    - "172.30.0.0/16"
+  networkType: OpenShiftSDN
+pullSecret: '{}'
+`,
+		changed: []string{
+			"apiVersion",
+			"networking.clusterNetworks",
+			"networking.clusterNetworks[0].hostSubnetLength",
+			"networking.machineCIDR",
+			"networking.serviceCIDR",
+			"networking.type",
+			"networking.type",
+		},
+	}, {
+		name: "replacements already set",
+		config: `apiVersion: v1
+networking:
+  clusterNetwork:
+  - hostSubnetLength: 9
+    cidr: 10.128.0.0/14
+    hostPrefix: 23
+  clusterNetworks:
+  - cidr: 10.128.0.0/14
+    hostSubnetLength: 9
+  machineCIDR: 10.0.0.0/16
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+  networkType: OVNKubernetes
+  type: OpenShiftSDN
+`,
+		expected: `apiVersion: v1
+networking:
+  clusterNetwork:
+  - cidr: 10.128.0.0/14
+    hostPrefix: 23
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+  networkType: OVNKubernetes
+`,
+		changed: []string{
+			"networking.clusterNetworks",
+			"networking.clusterNetwork[0].hostSubnetLength",
+			"networking.machineCIDR",
+			"networking.type",
+		},
+	}, {
+		name: "baremetal",
+		config: `apiVersion: v1
+platform:
+  baremetal:
+    provisioningDHCPExternal: true
+    provisioningHostIP: 172.22.0.3
+    hosts: []
+`,
+		expected: `apiVersion: v1
+platform:
+  baremetal:
+    provisioningNetwork: Unmanaged
+    clusterProvisioningIP: 172.22.0.3
+    hosts: []
+`,
+		changed: []string{
+			"platform.baremetal.provisioningDHCPExternal",
+			"platform.baremetal.provisioningHostIP",
+		},
+	}, {
+		name: "openstack",
+		config: `apiVersion: v1
+platform:
+  openstack:
+    cloud: mycloud
+    computeFlavor: m1.large
+    defaultMachinePlatform:
+      zones: [a, b]
+    lbFloatingIP: 192.0.2.10
+`,
+		expected: `apiVersion: v1
+platform:
+  openstack:
+    cloud: mycloud
+    defaultMachinePlatform:
+      zones: [a, b]
+      type: m1.large
+    apiFloatingIP: 192.0.2.10
+`,
+		changed: []string{
+			"platform.openstack.computeFlavor",
+			"platform.openstack.lbFloatingIP",
+		},
+	}, {
+		name: "openstack without default machine platform",
+		config: `apiVersion: v1
+platform:
+  openstack:
+    computeFlavor: m1.large
+    cloud: mycloud
+`,
+		expected: `apiVersion: v1
+platform:
+  openstack:
+    defaultMachinePlatform:
+      type: m1.large
+    cloud: mycloud
+`,
+		changed: []string{"platform.openstack.computeFlavor"},
+	}, {
+		name: "conflict",
+		config: `apiVersion: v1
+platform:
+  openstack:
+    apiFloatingIP: 192.0.2.10
+    lbFloatingIP: 192.0.2.11
+`,
+		err: `^failed to upconvert install config: platform\.openstack\.lbFloatingIP: Forbidden: cannot specify lbFloatingIP and apiFloatingIP together$`,
+	}, {
+		name: "flow mapping",
+		config: `apiVersion: v1
+networking: {machineCIDR: 10.0.0.0/16}
+`,
+		err: `^cannot rewrite "machineCIDR" at line 2 in a flow mapping$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrated, changes, err := MigrateInstallConfig([]byte(tc.config))
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, string(migrated))
+			var changed []string
+			for _, change := range changes {
+				changed = append(changed, change.Field)
+			}
+			assert.Equal(t, tc.changed, changed)
+			assert.Equal(t, convert(t, tc.config), convert(t, string(migrated)), "the migration changed the converted install config")
+		})
+	}
+}
+
+// convert returns the install config converted by the installer, without
+// its deprecated fields.
+func convert(t *testing.T, data string) *types.InstallConfig {
+	config := &types.InstallConfig{}
+	if !assert.NoError(t, yaml.Unmarshal([]byte(data), config)) || !assert.NoError(t, conversion.ConvertInstallConfig(config)) {
+		return nil
+	}
+	if networking := config.Networking; networking != nil {
+		networking.DeprecatedClusterNetworks = nil
+		networking.DeprecatedMachineCIDR = nil
+		networking.DeprecatedServiceCIDR = nil
+		networking.DeprecatedType = ""
+		for i := range networking.ClusterNetwork {
+			networking.ClusterNetwork[i].DeprecatedHostSubnetLength = 0
+		}
+	}
+	if baremetal := config.Platform.BareMetal; baremetal != nil {
+		baremetal.DeprecatedProvisioningDHCPExternal = false
+		baremetal.DeprecatedProvisioningHostIP = ""
+	}
+	if openstack := config.Platform.OpenStack; openstack != nil {
+		openstack.DeprecatedFlavorName = ""
+		openstack.DeprecatedLbFloatingIP = ""
+	}
+	return config
+}
+
+func TestMigrationsCoverDeprecatedFields(t *testing.T) {
+	paths := map[string]bool{}
+	for _, migration := range migrations {
+		paths[migration.path] = true
+	}
+	for _, field := range conversion.DeprecatedFields {
+		assert.True(t, paths[field.Path], "no migration for %s", field.Path)
+	}
+}
@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/openshift/installer/pkg/version"
+)
+
+// WriteText writes the issues of the report one per line, prefixed with
+// their position like compiler errors.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		position := r.File
+		if issue.Line > 0 {
+			position = fmt.Sprintf("%s:%d:%d", r.File, issue.Line, issue.Column)
+		}
+		message := issue.Message
+		if issue.Field != "" {
+			message = fmt.Sprintf("%s: %s", issue.Field, issue.Message)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", position, issue.Severity, message, issue.Rule); err != nil {
+			return err
+		}
+		if issue.Suggestion != "" {
+			if _, err := fmt.Fprintf(w, "    suggestion: %s\n", issue.Suggestion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// The types below are the subset of the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0 that the report uses.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes the report in the Static Analysis Results Interchange
+// Format 2.1.0, for code scanning and review tools.
+func (r *Report) WriteSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "openshift-install",
+			InformationURI: "https://github.com/openshift/installer",
+			Version:        version.Raw,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, issue := range r.Issues {
+		rules[issue.Rule] = true
+
+		text := issue.Message
+		if issue.Field != "" {
+			text = fmt.Sprintf("%s: %s", issue.Field, issue.Message)
+		}
+		if issue.Suggestion != "" {
+			text = fmt.Sprintf("%s (suggestion: %s)", text, issue.Suggestion)
+		}
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.File}}}
+		if issue.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    issue.Rule,
+			Level:     string(issue.Severity),
+			Message:   sarifMessage{Text: text},
+			Locations: []sarifLocation{location},
+		})
+	}
+	for rule := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool { return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID })
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
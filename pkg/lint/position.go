@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"regexp"
+	"strconv"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// pathSegmentRegex matches the segments of a field path, like "compute",
+// "[0]" and "replicas" in "compute[0].replicas".
+var pathSegmentRegex = regexp.MustCompile(`[^.\[\]]+|\[[^\]]*\]`)
+
+// pathSegments splits a field path into its segments. The segments of list
+// indexes and map keys keep their brackets.
+func pathSegments(path string) []string {
+	return pathSegmentRegex.FindAllString(path, -1)
+}
+
+// child returns the key and the value of the child of node for a path
+// segment, or nils if there is no such child.
+func child(node *yamlv3.Node, segment string) (*yamlv3.Node, *yamlv3.Node) {
+	if node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+	key := segment
+	if len(segment) > 1 && segment[0] == '[' {
+		key = segment[1 : len(segment)-1]
+		if node.Kind == yamlv3.SequenceNode {
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, nil
+			}
+			return nil, node.Content[index]
+		}
+	}
+	if node.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// locate returns the line and column of the field at the path, or of its
+// closest parent in the document if the field is not in it.
+func locate(root *yamlv3.Node, path string) (int, int) {
+	line, column := 0, 0
+	node := root
+	for _, segment := range pathSegments(path) {
+		key, value := child(node, segment)
+		if value == nil {
+			break
+		}
+		if key != nil {
+			line, column = key.Line, key.Column
+		} else {
+			line, column = value.Line, value.Column
+		}
+		node = value
+	}
+	return line, column
+}
+
+// match is a field of a document matched by a path pattern.
+type match struct {
+	// path is the path of the field.
+	path string
+	// key is the key of the field in its mapping.
+	key *yamlv3.Node
+	// value is the value of the field.
+	value *yamlv3.Node
+	// parent is the mapping or the list that contains the field.
+	parent *yamlv3.Node
+}
+
+// find returns the fields of the document matched by the path, in which [*]
+// matches every item of a list.
+func find(root *yamlv3.Node, path string) []match {
+	matches := []match{{value: root}}
+	for _, segment := range pathSegments(path) {
+		var next []match
+		for _, m := range matches {
+			node := m.value
+			if node.Kind == yamlv3.AliasNode {
+				node = node.Alias
+			}
+			if segment == "[*]" {
+				if node.Kind != yamlv3.SequenceNode {
+					continue
+				}
+				for i, item := range node.Content {
+					next = append(next, match{path: m.path + "[" + strconv.Itoa(i) + "]", value: item, parent: node})
+				}
+				continue
+			}
+			key, value := child(node, segment)
+			if value == nil {
+				continue
+			}
+			childPath := m.path + "." + segment
+			if m.path == "" || segment[0] == '[' {
+				childPath = m.path + segment
+			}
+			next = append(next, match{path: childPath, key: key, value: value, parent: node})
+		}
+		matches = next
+	}
+	return matches
+}
@@ -57,6 +57,8 @@ const (
 	Histogram MetricType = "Histogram"
 	// Counter denotes that the type of the collector object should be a Prometheus Counter.
 	Counter MetricType = "Counter"
+	// Gauge denotes that the type of the collector object should be a Prometheus Gauge.
+	Gauge MetricType = "Gauge"
 )
 
 // PromCollector function creates the required prometheus collector object with the values
@@ -65,6 +67,8 @@ func (m MetricBuilder) PromCollector() (prometheus.Collector, error) {
 	switch m.metricType {
 	case Counter:
 		return m.buildCounter(), nil
+	case Gauge:
+		return m.buildGauge(), nil
 	case Histogram:
 		return m.buildHistogram(), nil
 	default:
@@ -86,6 +90,20 @@ func (m *MetricBuilder) buildCounter() prometheus.Collector {
 	return collector
 }
 
+// buildGauge returns a prometheus gauge object with the value and labels set
+// in the MetricBuilder object.
+func (m *MetricBuilder) buildGauge() prometheus.Collector {
+	collector := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        m.name,
+			Help:        m.desc,
+			ConstLabels: m.labelKeyValues,
+		},
+	)
+	collector.Set(m.value)
+	return collector
+}
+
 // buildHistogram returns a prometheus Histogram object with the value, labels and buckets set
 // in the MetricBuilder object.
 func (m *MetricBuilder) buildHistogram() prometheus.Collector {
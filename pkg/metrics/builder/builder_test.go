@@ -27,6 +27,8 @@ func getCollectorDescription(collector prometheus.Collector) string {
 		return collector.(prometheus.Histogram).Desc().String()
 	case "counter":
 		return collector.(prometheus.Counter).Desc().String()
+	case "gauge":
+		return collector.(prometheus.Gauge).Desc().String()
 	default:
 		return ""
 	}
@@ -145,6 +147,19 @@ func TestMetricBuilderCollector(t *testing.T) {
 			labelKeyValues:       map[string]string{"test1": "test1", "test2": "test2"},
 			expectedErrorMessage: "",
 		},
+		{
+			name:                  "Test gauge creation",
+			expectedCollectorType: "gauge",
+			opts: MetricOpts{
+				Labels:     []string{"test1", "test2"},
+				Desc:       "test duration metric",
+				Name:       "test_duration",
+				MetricType: Gauge,
+			},
+			labelKeyValues:       map[string]string{"test1": "test1", "test2": "test2"},
+			value:                12.5,
+			expectedErrorMessage: "",
+		},
 		{
 			name:                  "Test empty label values",
 			expectedCollectorType: "counter",
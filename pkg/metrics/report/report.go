@@ -0,0 +1,136 @@
+// Package report turns the outcome of an installer command into Prometheus
+// metrics and pushes them to a Pushgateway.
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/metrics/builder"
+	"github.com/openshift/installer/pkg/metrics/pushclient"
+)
+
+const (
+	// ResultSuccess is the result label value of a command that succeeded.
+	ResultSuccess = "success"
+	// ResultFailure is the result label value of a command that failed.
+	ResultFailure = "failure"
+
+	// unknownReason is the reason reported for failures that do not carry
+	// a diagnostics.Err.
+	unknownReason = "Unknown"
+)
+
+var (
+	commandLabels = []string{"command", "platform", "release_image", "result", "reason"}
+
+	stageDurationOpts = builder.MetricOpts{
+		Name:       "cluster_installation_stage_duration_seconds",
+		Desc:       "Time spent in each stage of an openshift-install command.",
+		Labels:     append([]string{"stage"}, commandLabels...),
+		MetricType: builder.Gauge,
+	}
+
+	// runOpts is a gauge rather than a counter: the Pushgateway replaces
+	// the metrics of a job on each push, so a count would never exceed 1.
+	// The runs are counted by changes() of the timestamp instead.
+	runOpts = builder.MetricOpts{
+		Name:       "cluster_installation_run_timestamp_seconds",
+		Desc:       "Unix time at which the last openshift-install command run finished, by result.",
+		Labels:     commandLabels,
+		MetricType: builder.Gauge,
+	}
+)
+
+// Report is the outcome of a single installer command.
+type Report struct {
+	// Command is the installer command, e.g. "create cluster".
+	Command string
+	// Platform is the name of the platform of the cluster.
+	Platform string
+	// ReleaseImage is the pull spec of the release image of the cluster.
+	ReleaseImage string
+	// StageTimes are the durations recorded by the timer package.
+	StageTimes map[string]time.Duration
+	// Finished is the time at which the command finished.
+	Finished time.Time
+	// Err is the error the command failed with, or nil on success.
+	Err error
+}
+
+// Result returns the result label value of the report.
+func (r *Report) Result() string {
+	if r.Err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// Reason returns the diagnostics.Err reason of a failed command. Failures
+// without diagnostics report "Unknown", and successes report no reason.
+func (r *Report) Reason() string {
+	if r.Err == nil {
+		return ""
+	}
+	var diagErr *diagnostics.Err
+	if errors.As(r.Err, &diagErr) && diagErr.Reason != "" {
+		return diagErr.Reason
+	}
+	return unknownReason
+}
+
+// Collectors returns the Prometheus collectors for the report.
+func (r *Report) Collectors() ([]prometheus.Collector, error) {
+	labels := map[string]string{
+		"command":       r.Command,
+		"platform":      r.Platform,
+		"release_image": r.ReleaseImage,
+		"result":        r.Result(),
+		"reason":        r.Reason(),
+	}
+
+	run, err := builder.NewMetricBuilder(runOpts, float64(r.Finished.Unix()), labels)
+	if err != nil {
+		return nil, err
+	}
+	collector, err := run.PromCollector()
+	if err != nil {
+		return nil, err
+	}
+	collectors := []prometheus.Collector{collector}
+
+	stages := make([]string, 0, len(r.StageTimes))
+	for stage := range r.StageTimes {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		stageLabels := map[string]string{"stage": stage}
+		for k, v := range labels {
+			stageLabels[k] = v
+		}
+		duration, err := builder.NewMetricBuilder(stageDurationOpts, r.StageTimes[stage].Seconds(), stageLabels)
+		if err != nil {
+			return nil, err
+		}
+		collector, err := duration.PromCollector()
+		if err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, collector)
+	}
+	return collectors, nil
+}
+
+// Push sends the report to the Pushgateway configured in client.
+func Push(client *pushclient.PushClient, r *Report) error {
+	collectors, err := r.Collectors()
+	if err != nil {
+		return errors.Wrap(err, "failed to build metrics")
+	}
+	return client.Push(collectors...)
+}
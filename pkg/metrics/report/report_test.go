@@ -0,0 +1,82 @@
+package report
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/metrics/pushclient"
+)
+
+func TestReason(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		result string
+		reason string
+	}{
+		{
+			name:   "success",
+			result: ResultSuccess,
+		},
+		{
+			name:   "plain error",
+			err:    errors.New("boom"),
+			result: ResultFailure,
+			reason: "Unknown",
+		},
+		{
+			name:   "wrapped diagnostics error",
+			err:    errors.Wrap(&diagnostics.Err{Reason: "MissingQuota"}, "failed to fetch Cluster"),
+			result: ResultFailure,
+			reason: "MissingQuota",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Report{Err: tc.err}
+			assert.Equal(t, tc.result, r.Result())
+			assert.Equal(t, tc.reason, r.Reason())
+		})
+	}
+}
+
+func TestPushToGateway(t *testing.T) {
+	var (
+		path string
+		body string
+	)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		data, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		body = string(data)
+	}))
+	defer gateway.Close()
+
+	r := &Report{
+		Command:      "create cluster",
+		Platform:     "aws",
+		ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.8.0-x86_64",
+		StageTimes: map[string]time.Duration{
+			"Total":              90 * time.Minute,
+			"Bootstrap Complete": 20 * time.Minute,
+		},
+		Finished: time.Unix(1600000000, 0),
+		Err:      &diagnostics.Err{Reason: "Timeout"},
+	}
+	client := &pushclient.PushClient{URL: gateway.URL, Client: &http.Client{}, JobName: "installer"}
+	assert.NoError(t, Push(client, r))
+
+	assert.Equal(t, "/metrics/job/installer", path)
+	labels := `command="create cluster",platform="aws",reason="Timeout",release_image="quay.io/openshift-release-dev/ocp-release:4.8.0-x86_64",result="failure"`
+	assert.Contains(t, body, `cluster_installation_run_timestamp_seconds{`+labels+`} 1.6e+09`)
+	assert.Contains(t, body, `cluster_installation_stage_duration_seconds{`+labels+`,stage="Bootstrap Complete"} 1200`)
+	assert.Contains(t, body, `cluster_installation_stage_duration_seconds{`+labels+`,stage="Total"} 5400`)
+}
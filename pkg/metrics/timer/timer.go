@@ -37,6 +37,11 @@ func LogSummary() {
 	timer.LogSummary(logrus.StandardLogger())
 }
 
+// StageTimes returns the durations recorded so far, keyed by stage.
+func StageTimes() map[string]time.Duration {
+	return timer.StageTimes()
+}
+
 // NewTimer returns a new timer that can be used to track sections and
 func NewTimer() Timer {
 	return Timer{
@@ -61,6 +66,16 @@ func (t *Timer) StopTimer(key string) time.Duration {
 	return time.Since(time.Now())
 }
 
+// StageTimes returns a copy of the durations recorded so far, keyed by stage.
+// Stages that were started but not stopped are not included.
+func (t *Timer) StageTimes() map[string]time.Duration {
+	times := make(map[string]time.Duration, len(t.stageTimes))
+	for stage, duration := range t.stageTimes {
+		times[stage] = duration
+	}
+	return times
+}
+
 // LogSummary prints the summary of all the times collected so far into the INFO section.
 // The format of printing will be the following:
 // If there are no stages except the total time stage, then it only prints the following
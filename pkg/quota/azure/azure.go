@@ -0,0 +1,87 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	azcompute "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/compute/mgmt/compute"
+	aznetwork "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/network/mgmt/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/quota"
+)
+
+const (
+	// ServiceCompute is the service of the quotas of virtual machines,
+	// like the cores of each VM family.
+	ServiceCompute = "compute"
+	// ServiceNetwork is the service of the quotas of network resources,
+	// like public IP addresses and load balancers.
+	ServiceNetwork = "network"
+)
+
+// UsageLister lists the usages of the resources of a subscription in a
+// region, with their limits.
+type UsageLister interface {
+	ListComputeUsages(ctx context.Context, region string) ([]azcompute.Usage, error)
+	ListNetworkUsages(ctx context.Context, region string) ([]aznetwork.Usage, error)
+}
+
+// Load loads the quotas of the compute and network resources of the
+// subscription in the region. The name of each quota is the name of its
+// usage prefixed with its service, for example "compute/standardDSv3Family"
+// or "network/PublicIPAddresses".
+func Load(ctx context.Context, client UsageLister, region string) ([]quota.Quota, error) {
+	computeUsages, err := client.ListComputeUsages(ctx, region)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list compute usages")
+	}
+	networkUsages, err := client.ListNetworkUsages(ctx, region)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network usages")
+	}
+
+	quotas := make([]quota.Quota, 0, len(computeUsages)+len(networkUsages))
+	for _, u := range computeUsages {
+		if u.Name == nil || u.Name.Value == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		quotas = append(quotas, newQuota(ServiceCompute, *u.Name.Value, region, int64(*u.CurrentValue), *u.Limit))
+	}
+	for _, u := range networkUsages {
+		if u.Name == nil || u.Name.Value == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		quotas = append(quotas, newQuota(ServiceNetwork, *u.Name.Value, region, *u.CurrentValue, *u.Limit))
+	}
+	return quotas, nil
+}
+
+func newQuota(service, name, region string, inUse, limit int64) quota.Quota {
+	return quota.Quota{
+		Service: service,
+		Name:    fmt.Sprintf("%s/%s", service, name),
+		Region:  region,
+		InUse:   inUse,
+		Limit:   limit,
+		// Azure reports the limits of the resources without a quota as
+		// the largest 32-bit integer.
+		Unlimited: limit < 0 || limit >= 1<<31-1,
+	}
+}
+
+// IsUnauthorized checks if the error is un authorized.
+func IsUnauthorized(err error) bool {
+	if err == nil {
+		return false
+	}
+	var detailedErr autorest.DetailedError
+	if errors.As(err, &detailedErr) {
+		if code, ok := detailedErr.StatusCode.(int); ok {
+			return code == http.StatusUnauthorized || code == http.StatusForbidden
+		}
+	}
+	return false
+}
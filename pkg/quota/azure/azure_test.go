@@ -0,0 +1,126 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	azcompute "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/compute/mgmt/compute"
+	aznetwork "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/network/mgmt/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/installer/pkg/quota"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name   string
+		client *fakeUsageLister
+
+		expected []quota.Quota
+		err      string
+	}{{
+		name: "usages",
+		client: &fakeUsageLister{
+			compute: []azcompute.Usage{
+				computeUsage("cores", 12, 100),
+				computeUsage("standardDSv3Family", 8, 50),
+				{Name: &azcompute.UsageName{Value: pointer.StringPtr("missing limit")}, CurrentValue: pointer.Int32Ptr(1)},
+			},
+			network: []aznetwork.Usage{
+				networkUsage("PublicIPAddresses", 3, 1000),
+				networkUsage("LoadBalancers", 0, 1<<31-1),
+				{CurrentValue: pointer.Int64Ptr(1), Limit: pointer.Int64Ptr(1)},
+			},
+		},
+		expected: []quota.Quota{
+			{Service: "compute", Name: "compute/cores", Region: "centralus", InUse: 12, Limit: 100},
+			{Service: "compute", Name: "compute/standardDSv3Family", Region: "centralus", InUse: 8, Limit: 50},
+			{Service: "network", Name: "network/PublicIPAddresses", Region: "centralus", InUse: 3, Limit: 1000},
+			{Service: "network", Name: "network/LoadBalancers", Region: "centralus", InUse: 0, Limit: 1<<31 - 1, Unlimited: true},
+		},
+	}, {
+		name:   "compute error",
+		client: &fakeUsageLister{computeErr: errors.New("boom")},
+		err:    "failed to list compute usages: boom",
+	}, {
+		name:   "network error",
+		client: &fakeUsageLister{networkErr: errors.New("boom")},
+		err:    "failed to list network usages: boom",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Load(context.TODO(), test.client, "centralus")
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{{
+		name: "nil",
+	}, {
+		name: "other error",
+		err:  errors.New("boom"),
+	}, {
+		name:     "unauthorized",
+		err:      fmt.Errorf("failed to list compute usages: %w", autorest.DetailedError{StatusCode: http.StatusUnauthorized}),
+		expected: true,
+	}, {
+		name:     "forbidden",
+		err:      autorest.DetailedError{StatusCode: http.StatusForbidden},
+		expected: true,
+	}, {
+		name: "not found",
+		err:  autorest.DetailedError{StatusCode: http.StatusNotFound},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsUnauthorized(test.err))
+		})
+	}
+}
+
+func computeUsage(name string, current int32, limit int64) azcompute.Usage {
+	return azcompute.Usage{
+		Name:         &azcompute.UsageName{Value: pointer.StringPtr(name)},
+		CurrentValue: pointer.Int32Ptr(current),
+		Limit:        pointer.Int64Ptr(limit),
+	}
+}
+
+func networkUsage(name string, current, limit int64) aznetwork.Usage {
+	return aznetwork.Usage{
+		Name:         &aznetwork.UsageName{Value: pointer.StringPtr(name)},
+		CurrentValue: pointer.Int64Ptr(current),
+		Limit:        pointer.Int64Ptr(limit),
+	}
+}
+
+type fakeUsageLister struct {
+	compute    []azcompute.Usage
+	computeErr error
+	network    []aznetwork.Usage
+	networkErr error
+}
+
+func (fake *fakeUsageLister) ListComputeUsages(ctx context.Context, region string) ([]azcompute.Usage, error) {
+	return fake.compute, fake.computeErr
+}
+
+func (fake *fakeUsageLister) ListNetworkUsages(ctx context.Context, region string) ([]aznetwork.Usage, error) {
+	return fake.network, fake.networkErr
+}
@@ -0,0 +1,67 @@
+package azure
+
+import (
+	"context"
+	"time"
+
+	azcompute "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/compute/mgmt/compute"
+	aznetwork "github.com/Azure/azure-sdk-for-go/profiles/2018-03-01/network/mgmt/network"
+	"github.com/pkg/errors"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+)
+
+// Client lists the usages of the resources of a subscription using the
+// Azure API.
+type Client struct {
+	ssn *azureconfig.Session
+}
+
+var _ UsageLister = (*Client)(nil)
+
+// NewClient returns a Client using the session.
+func NewClient(ssn *azureconfig.Session) *Client {
+	return &Client{ssn: ssn}
+}
+
+// ListComputeUsages lists the usages of the compute resources in the region.
+func (c *Client) ListComputeUsages(ctx context.Context, region string) ([]azcompute.Usage, error) {
+	client := azcompute.NewUsageClientWithBaseURI(c.ssn.Environment.ResourceManagerEndpoint, c.ssn.Credentials.SubscriptionID)
+	client.Authorizer = c.ssn.Authorizer
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var usages []azcompute.Usage
+	page, err := client.List(ctx, region)
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching compute usage pages")
+		}
+		usages = append(usages, page.Values()...)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching compute usage pages")
+	}
+	return usages, nil
+}
+
+// ListNetworkUsages lists the usages of the network resources in the region.
+func (c *Client) ListNetworkUsages(ctx context.Context, region string) ([]aznetwork.Usage, error) {
+	client := aznetwork.NewUsagesClientWithBaseURI(c.ssn.Environment.ResourceManagerEndpoint, c.ssn.Credentials.SubscriptionID)
+	client.Authorizer = c.ssn.Authorizer
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var usages []aznetwork.Usage
+	page, err := client.List(ctx, region)
+	for ; page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching network usage pages")
+		}
+		usages = append(usages, page.Values()...)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching network usage pages")
+	}
+	return usages, nil
+}
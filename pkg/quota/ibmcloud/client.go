@@ -0,0 +1,111 @@
+package ibmcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	"github.com/pkg/errors"
+)
+
+// Client lists the VPC resources of an account in a region using the IBM
+// Cloud API.
+type Client struct {
+	vpcAPI *vpcv1.VpcV1
+}
+
+var _ UsageLister = (*Client)(nil)
+
+// NewClient returns a Client for the region using the authenticator.
+func NewClient(authenticator core.Authenticator, region string) (*Client, error) {
+	vpcAPI, err := vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
+		Authenticator: authenticator,
+		URL:           fmt.Sprintf("https://%s.iaas.cloud.ibm.com/v1", region),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create VPC client")
+	}
+	return &Client{vpcAPI: vpcAPI}, nil
+}
+
+// ListInstances lists the virtual server instances.
+func (c *Client) ListInstances(ctx context.Context) ([]vpcv1.Instance, error) {
+	var instances []vpcv1.Instance
+	options := c.vpcAPI.NewListInstancesOptions()
+	for {
+		page, response, err := c.vpcAPI.ListInstancesWithContext(ctx, options)
+		if err != nil {
+			return nil, wrapStatus(response, err)
+		}
+		instances = append(instances, page.Instances...)
+		if page.Next == nil {
+			return instances, nil
+		}
+		if options.Start, err = nextStart(page.Next.Href); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ListVPCs lists the VPCs.
+func (c *Client) ListVPCs(ctx context.Context) ([]vpcv1.VPC, error) {
+	var vpcs []vpcv1.VPC
+	options := c.vpcAPI.NewListVpcsOptions()
+	for {
+		page, response, err := c.vpcAPI.ListVpcsWithContext(ctx, options)
+		if err != nil {
+			return nil, wrapStatus(response, err)
+		}
+		vpcs = append(vpcs, page.Vpcs...)
+		if page.Next == nil {
+			return vpcs, nil
+		}
+		if options.Start, err = nextStart(page.Next.Href); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ListLoadBalancers lists the load balancers.
+func (c *Client) ListLoadBalancers(ctx context.Context) ([]vpcv1.LoadBalancer, error) {
+	var loadBalancers []vpcv1.LoadBalancer
+	options := c.vpcAPI.NewListLoadBalancersOptions()
+	for {
+		page, response, err := c.vpcAPI.ListLoadBalancersWithContext(ctx, options)
+		if err != nil {
+			return nil, wrapStatus(response, err)
+		}
+		loadBalancers = append(loadBalancers, page.LoadBalancers...)
+		if page.Next == nil {
+			return loadBalancers, nil
+		}
+		if options.Start, err = nextStart(page.Next.Href); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextStart returns the start token of the page at href.
+func nextStart(href *string) (*string, error) {
+	if href == nil {
+		return nil, errors.New("missing link to the next page")
+	}
+	next, err := url.Parse(*href)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the link to the next page")
+	}
+	start := next.Query().Get("start")
+	if start == "" {
+		return nil, errors.Errorf("missing start of the next page in %s", *href)
+	}
+	return &start, nil
+}
+
+func wrapStatus(response *core.DetailedResponse, err error) error {
+	if response == nil {
+		return err
+	}
+	return &statusError{code: response.GetStatusCode(), err: err}
+}
@@ -0,0 +1,109 @@
+package ibmcloud
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/quota"
+)
+
+const (
+	// QuotaVCPU is the quota of the vCPUs of the virtual server instances.
+	QuotaVCPU = "vpc/vcpu"
+	// QuotaVPCs is the quota of the VPCs.
+	QuotaVPCs = "vpc/vpcs"
+	// QuotaLoadBalancers is the quota of the load balancers.
+	QuotaLoadBalancers = "vpc/load-balancers"
+)
+
+// DefaultLimits are the default quotas of the VPC resources of an account in
+// a region. IBM Cloud has no API to read the quotas of an account, which can
+// be increased with a support case, so the usages are checked against the
+// defaults documented at https://cloud.ibm.com/docs/vpc?topic=vpc-quotas.
+var DefaultLimits = map[string]int64{
+	QuotaVCPU:          200,
+	QuotaVPCs:          10,
+	QuotaLoadBalancers: 50,
+}
+
+// UsageLister lists the VPC resources of an account in a region.
+type UsageLister interface {
+	ListInstances(ctx context.Context) ([]vpcv1.Instance, error)
+	ListVPCs(ctx context.Context) ([]vpcv1.VPC, error)
+	ListLoadBalancers(ctx context.Context) ([]vpcv1.LoadBalancer, error)
+}
+
+// Load loads the quotas of the VPC resources of the account in the region.
+// The usages are counted from the resources of the account, and the limits
+// are the DefaultLimits.
+func Load(ctx context.Context, client UsageLister, region string) ([]quota.Quota, error) {
+	instances, err := client.ListInstances(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list instances")
+	}
+	vpcs, err := client.ListVPCs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list VPCs")
+	}
+	loadBalancers, err := client.ListLoadBalancers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list load balancers")
+	}
+
+	var vcpus int64
+	for _, instance := range instances {
+		if instance.Vcpu != nil && instance.Vcpu.Count != nil {
+			vcpus += *instance.Vcpu.Count
+		}
+	}
+	return []quota.Quota{
+		newQuota(QuotaVCPU, region, vcpus),
+		newQuota(QuotaVPCs, region, int64(len(vpcs))),
+		newQuota(QuotaLoadBalancers, region, int64(len(loadBalancers))),
+	}, nil
+}
+
+func newQuota(name, region string, inUse int64) quota.Quota {
+	return quota.Quota{
+		Service: "vpc",
+		Name:    name,
+		Region:  region,
+		InUse:   inUse,
+		Limit:   DefaultLimits[name],
+	}
+}
+
+// IsUnauthorized checks if the error is un authorized.
+func IsUnauthorized(err error) bool {
+	if err == nil {
+		return false
+	}
+	var authErr *core.AuthenticationError
+	if errors.As(err, &authErr) {
+		return true
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code == http.StatusUnauthorized || statusErr.code == http.StatusForbidden
+	}
+	return false
+}
+
+// statusError is an error of a request to the VPC API with its HTTP status
+// code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,138 @@
+package ibmcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/quota"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name   string
+		client *fakeUsageLister
+
+		expected []quota.Quota
+		err      string
+	}{{
+		name: "usages",
+		client: &fakeUsageLister{
+			instances: []vpcv1.Instance{
+				{Vcpu: &vpcv1.InstanceVcpu{Count: core.Int64Ptr(4)}},
+				{Vcpu: &vpcv1.InstanceVcpu{Count: core.Int64Ptr(16)}},
+				{},
+			},
+			vpcs:          []vpcv1.VPC{{}, {}},
+			loadBalancers: []vpcv1.LoadBalancer{{}},
+		},
+		expected: []quota.Quota{
+			{Service: "vpc", Name: "vpc/vcpu", Region: "us-south", InUse: 20, Limit: 200},
+			{Service: "vpc", Name: "vpc/vpcs", Region: "us-south", InUse: 2, Limit: 10},
+			{Service: "vpc", Name: "vpc/load-balancers", Region: "us-south", InUse: 1, Limit: 50},
+		},
+	}, {
+		name:   "instances error",
+		client: &fakeUsageLister{instancesErr: errors.New("boom")},
+		err:    "failed to list instances: boom",
+	}, {
+		name:   "load balancers error",
+		client: &fakeUsageLister{loadBalancersErr: errors.New("boom")},
+		err:    "failed to list load balancers: boom",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Load(context.TODO(), test.client, "us-south")
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, test.expected, got)
+		})
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{{
+		name: "nil",
+	}, {
+		name: "other error",
+		err:  errors.New("boom"),
+	}, {
+		name:     "authentication",
+		err:      fmt.Errorf("failed to list instances: %w", core.NewAuthenticationError(&core.DetailedResponse{StatusCode: http.StatusBadRequest}, errors.New("bad API key"))),
+		expected: true,
+	}, {
+		name:     "forbidden",
+		err:      fmt.Errorf("failed to list instances: %w", &statusError{code: http.StatusForbidden, err: errors.New("forbidden")}),
+		expected: true,
+	}, {
+		name: "not found",
+		err:  &statusError{code: http.StatusNotFound, err: errors.New("not found")},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsUnauthorized(test.err))
+		})
+	}
+}
+
+func Test_nextStart(t *testing.T) {
+	cases := []struct {
+		href     *string
+		expected string
+		err      string
+	}{{
+		href:     core.StringPtr("https://us-south.iaas.cloud.ibm.com/v1/instances?limit=50&start=r006-1234"),
+		expected: "r006-1234",
+	}, {
+		href: core.StringPtr("https://us-south.iaas.cloud.ibm.com/v1/instances?limit=50"),
+		err:  "missing start of the next page in https://us-south.iaas.cloud.ibm.com/v1/instances?limit=50",
+	}, {
+		err: "missing link to the next page",
+	}}
+	for idx, test := range cases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			got, err := nextStart(test.href)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, *got)
+			}
+		})
+	}
+}
+
+type fakeUsageLister struct {
+	instances        []vpcv1.Instance
+	instancesErr     error
+	vpcs             []vpcv1.VPC
+	vpcsErr          error
+	loadBalancers    []vpcv1.LoadBalancer
+	loadBalancersErr error
+}
+
+func (fake *fakeUsageLister) ListInstances(ctx context.Context) ([]vpcv1.Instance, error) {
+	return fake.instances, fake.instancesErr
+}
+
+func (fake *fakeUsageLister) ListVPCs(ctx context.Context) ([]vpcv1.VPC, error) {
+	return fake.vpcs, fake.vpcsErr
+}
+
+func (fake *fakeUsageLister) ListLoadBalancers(ctx context.Context) ([]vpcv1.LoadBalancer, error) {
+	return fake.loadBalancers, fake.loadBalancersErr
+}
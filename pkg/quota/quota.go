@@ -22,12 +22,12 @@ type Quota struct {
 // Constraint defines a check against availablity
 // for a resource quota.
 type Constraint struct {
-	Name string
+	Name string `json:"name"`
 	// This should be global or specific region.
-	Region string
+	Region string `json:"region,omitempty"`
 	// Count is the number of the resource that is required
 	// to be free for use.
-	Count int64
+	Count int64 `json:"count"`
 }
 
 // ConstraintReportResult provide one word result for the constraint
@@ -46,9 +46,9 @@ const (
 
 // ConstraintReport provides result for a given constraint.
 type ConstraintReport struct {
-	For     *Constraint
-	Result  ConstraintReportResult
-	Message string
+	For     *Constraint            `json:"for"`
+	Result  ConstraintReportResult `json:"result"`
+	Message string                 `json:"message,omitempty"`
 }
 
 // Check returns whether the checks constraints are possible gives the quotas.
@@ -0,0 +1,318 @@
+package reachability
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// DefaultTimeout is the default timeout of the check of an endpoint.
+const DefaultTimeout = 15 * time.Second
+
+// Checker checks that endpoints can be reached like the cluster will reach
+// them: through its proxy, trusting its additional trust bundle and
+// authenticating to registries with its pull secret.
+type Checker struct {
+	// RootCAs are the CAs trusted to sign the TLS certificates of the
+	// endpoints.
+	RootCAs *x509.CertPool
+
+	// Proxy is the proxy of the cluster, or nil.
+	Proxy *types.Proxy
+
+	// Auths are the credentials of the pull secret, keyed by registry or
+	// repository, as base64-encoded "user:password".
+	Auths map[string]string
+
+	// Timeout is the timeout of the check of each endpoint.
+	Timeout time.Duration
+
+	// LookupHost resolves the name of a host.
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+}
+
+// NewChecker returns a checker for the cluster of the install config. It
+// trusts the CAs of the system and of the additional trust bundle.
+func NewChecker(config *types.InstallConfig) (*Checker, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if config.AdditionalTrustBundle != "" && !roots.AppendCertsFromPEM([]byte(config.AdditionalTrustBundle)) {
+		return nil, errors.New("additionalTrustBundle has no valid certificates")
+	}
+	auths, err := parseAuths(config.PullSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the pull secret")
+	}
+	return &Checker{
+		RootCAs:    roots,
+		Proxy:      config.Proxy,
+		Auths:      auths,
+		Timeout:    DefaultTimeout,
+		LookupHost: net.DefaultResolver.LookupHost,
+	}, nil
+}
+
+// Check checks the endpoints concurrently, and returns their results in the
+// order of the endpoints.
+func (c *Checker) Check(ctx context.Context, endpoints []Endpoint) []Result {
+	results := make([]Result, len(endpoints))
+	var wg sync.WaitGroup
+	for i := range endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+			defer cancel()
+			results[i] = Result{Endpoint: endpoints[i], Err: c.check(ctx, endpoints[i])}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Checker) check(ctx context.Context, endpoint Endpoint) error {
+	switch endpoint.Kind {
+	case KindProxy:
+		return c.checkProxy(ctx, endpoint.Address)
+	case KindRegistry:
+		return c.checkRegistry(ctx, endpoint.Address, endpoint.Reference)
+	default:
+		return errors.Errorf("unknown kind of endpoint %q", endpoint.Kind)
+	}
+}
+
+// checkProxy resolves the host of the proxy and connects to it, with TLS
+// when it is an HTTPS proxy. The requests to the registries go through the
+// proxy, which checks that it forwards them.
+func (c *Checker) checkProxy(ctx context.Context, address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return errors.Wrap(err, "invalid URL")
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	if err := c.resolve(ctx, u.Hostname()); err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", u.Host)
+	}
+	defer conn.Close()
+	if u.Scheme != "https" {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	tlsConn := tls.Client(conn, &tls.Config{RootCAs: c.RootCAs, ServerName: u.Hostname()})
+	return tlsError(u.Host, tlsConn.Handshake())
+}
+
+// checkRegistry checks that the repository, or the image of the reference
+// in it when there is one, can be pulled with the credentials of the pull
+// secret.
+func (c *Checker) checkRegistry(ctx context.Context, repository, reference string) error {
+	named, err := dockerref.ParseNormalizedNamed(repository)
+	if err != nil {
+		return errors.Wrap(err, "invalid repository")
+	}
+	domain, path := dockerref.Domain(named), dockerref.Path(named)
+	host := domain
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+
+	proxy := c.proxyFor(host)
+	if proxy == nil {
+		// The proxy resolves the hosts it forwards to.
+		hostname, _, err := net.SplitHostPort(host)
+		if err != nil {
+			hostname = host
+		}
+		if err := c.resolve(ctx, hostname); err != nil {
+			return err
+		}
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxy),
+			TLSClientConfig: &tls.Config{RootCAs: c.RootCAs},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	method, target := http.MethodGet, fmt.Sprintf("https://%s/v2/%s/tags/list?n=1", host, path)
+	if reference != "" {
+		method, target = http.MethodHead, fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, path, reference)
+	}
+	request := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Accept", "application/vnd.docker.distribution.manifest.list.v2+json")
+		req.Header.Add("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+		req.Header.Add("Accept", "application/vnd.oci.image.index.v1+json")
+		req.Header.Add("Accept", "application/vnd.oci.image.manifest.v1+json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, tlsError(host, err)
+		}
+		resp.Body.Close()
+		return resp, nil
+	}
+
+	resp, err := request("")
+	if err != nil {
+		return err
+	}
+	auth := c.auth(domain, path)
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(ctx, client, resp.Header.Get("WWW-Authenticate"), auth, path)
+		if err != nil {
+			return err
+		}
+		if resp, err = request(authorization); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		if auth == "" {
+			return errors.Errorf("access to %s was denied and the pull secret has no credentials for %s", named.Name(), domain)
+		}
+		return errors.Errorf("access to %s was denied with the credentials of the pull secret", named.Name())
+	case resp.StatusCode == http.StatusNotFound && reference != "":
+		return errors.Errorf("image %s is not in %s", reference, named.Name())
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.Errorf("repository %s was not found", named.Name())
+	default:
+		return errors.Errorf("unexpected response from %s: %s", host, resp.Status)
+	}
+}
+
+// authorize returns the authorization of the requests to a registry for the
+// challenge of its response, like a bearer token for the pull of the
+// repository from its token service.
+func (c *Checker) authorize(ctx context.Context, client *http.Client, challenge, auth, path string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == "" {
+			return "", nil
+		}
+		return "Basic " + auth, nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", errors.Errorf("invalid realm in the challenge %q", challenge)
+		}
+		query := realm.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", path))
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", "Basic "+auth)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", tlsError(realm.Host, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			if auth == "" {
+				// The registry denies the anonymous request again.
+				return "", nil
+			}
+			return "", errors.Errorf("the token service %s rejected the credentials of the pull secret", realm.Host)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.Errorf("unexpected response from the token service %s: %s", realm.Host, resp.Status)
+		}
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", errors.Wrapf(err, "failed to decode the token from %s", realm.Host)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	default:
+		return "", errors.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+func (c *Checker) resolve(ctx context.Context, host string) error {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	addrs, err := c.LookupHost(ctx, host)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve %s", host)
+	}
+	if len(addrs) == 0 {
+		return errors.Errorf("failed to resolve %s: no addresses", host)
+	}
+	return nil
+}
+
+// tlsError explains the errors of TLS certificates that are not trusted.
+func tlsError(host string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return errors.Errorf("the TLS certificate of %s is not signed by a CA of the system or of additionalTrustBundle: %v", host, unknownAuthority)
+	case errors.As(err, &invalid):
+		return errors.Errorf("the TLS certificate of %s is invalid: %v", host, invalid)
+	case errors.As(err, &hostname):
+		return errors.Errorf("the TLS certificate of %s is not valid for its name: %v", host, hostname)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,118 @@
+package reachability
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// proxyFor returns the URL of the proxy of the cluster for HTTPS requests to
+// the host, or nil when they are not proxied.
+func (c *Checker) proxyFor(host string) *url.URL {
+	if c.Proxy == nil || c.Proxy.HTTPSProxy == "" {
+		return nil
+	}
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	if noProxy(c.Proxy.NoProxy, hostname) {
+		return nil
+	}
+	proxy, err := url.Parse(c.Proxy.HTTPSProxy)
+	if err != nil {
+		return nil
+	}
+	return proxy
+}
+
+// noProxy returns whether the host matches an entry of the noProxy list: a
+// domain and its subdomains, an IP address or CIDR, or "*" for any host.
+func noProxy(list, host string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimSuffix(strings.TrimPrefix(entry, "."), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAuths returns the credentials of the pull secret keyed by registry or
+// repository.
+func parseAuths(pullSecret string) (map[string]string, error) {
+	var secret struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &secret); err != nil {
+		return nil, err
+	}
+	auths := make(map[string]string, len(secret.Auths))
+	for key, auth := range secret.Auths {
+		if auth.Auth == "" {
+			continue
+		}
+		key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		auths[strings.TrimSuffix(key, "/")] = auth.Auth
+	}
+	return auths, nil
+}
+
+// auth returns the credentials of the pull secret for the repository: those
+// of the longest key that is the repository, one of its parents, or its
+// registry.
+func (c *Checker) auth(domain, path string) string {
+	name := domain + "/" + path
+	for {
+		if auth, ok := c.Auths[name]; ok {
+			return auth
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return ""
+		}
+		name = name[:i]
+	}
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseChallenge returns the scheme and parameters of the challenge of a
+// WWW-Authenticate header, like
+// `Bearer realm="https://auth.example.com/token",service="registry"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	challenge = strings.TrimSpace(challenge)
+	scheme := challenge
+	if i := strings.IndexByte(challenge, ' '); i >= 0 {
+		scheme = challenge[:i]
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge[len(scheme):], -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return scheme, params
+}
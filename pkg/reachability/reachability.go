@@ -0,0 +1,164 @@
+// Package reachability checks that the endpoints the cluster needs early in
+// the installation, like its proxy and the registries of its release image,
+// can be reached with the settings of the install config.
+package reachability
+
+import (
+	"fmt"
+	"strings"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Kind is the kind of an endpoint.
+type Kind string
+
+const (
+	// KindProxy is a proxy of the cluster.
+	KindProxy Kind = "Proxy"
+	// KindRegistry is a repository in an image registry.
+	KindRegistry Kind = "Registry"
+)
+
+// Endpoint is an endpoint the cluster needs during the installation.
+type Endpoint struct {
+	// Name is where the endpoint comes from, like "proxy.httpsProxy" or
+	// "imageContentSources[0].mirrors[1]".
+	Name string
+
+	// Kind is the kind of the endpoint.
+	Kind Kind
+
+	// Address is the URL of a proxy, or the repository of a registry.
+	Address string
+
+	// Reference is the tag or digest of an image that must be in the
+	// repository of a registry. When it is empty, only the repository is
+	// checked.
+	Reference string
+
+	// Source is the source repository of the image content source of a
+	// mirror or of the source itself. The cluster can pull the images of a
+	// source as long as one of its mirrors or the source can be reached.
+	Source string
+}
+
+// Result is the result of the check of an endpoint.
+type Result struct {
+	Endpoint Endpoint
+
+	// Err is why the endpoint cannot be reached, or nil.
+	Err error
+}
+
+// Endpoints returns the endpoints the cluster of the install config needs to
+// pull the release image: its proxies, the mirrors and sources of its image
+// content sources, and the registry of the release image unless it is
+// mirrored.
+//
+// The cluster only pulls images by digest from the mirrors, so the release
+// image is only mirrored when its pull spec has a digest, and each mirror of
+// its repository is then checked for that digest.
+func Endpoints(config *types.InstallConfig, releaseImage string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	if proxy := config.Proxy; proxy != nil {
+		if proxy.HTTPProxy != "" {
+			endpoints = append(endpoints, Endpoint{Name: "proxy.httpProxy", Kind: KindProxy, Address: proxy.HTTPProxy})
+		}
+		if proxy.HTTPSProxy != "" {
+			endpoints = append(endpoints, Endpoint{Name: "proxy.httpsProxy", Kind: KindProxy, Address: proxy.HTTPSProxy})
+		}
+	}
+
+	release, err := dockerref.ParseNormalizedNamed(releaseImage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse release image %s", releaseImage)
+	}
+	var digest string
+	if canonical, ok := release.(dockerref.Canonical); ok {
+		digest = canonical.Digest().String()
+	}
+
+	mirrored := false
+	for i, source := range config.ImageContentSources {
+		suffix, matches := matchSource(source.Source, release.Name())
+		covers := matches && digest != "" && len(source.Mirrors) > 0
+		mirrored = mirrored || covers
+		for j, mirror := range source.Mirrors {
+			endpoint := Endpoint{
+				Name:    fmt.Sprintf("imageContentSources[%d].mirrors[%d]", i, j),
+				Kind:    KindRegistry,
+				Address: mirror,
+				Source:  source.Source,
+			}
+			if covers {
+				endpoint.Address = mirror + suffix
+				endpoint.Reference = digest
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		endpoint := Endpoint{
+			Name:    fmt.Sprintf("imageContentSources[%d].source", i),
+			Kind:    KindRegistry,
+			Address: source.Source,
+			Source:  source.Source,
+		}
+		if covers {
+			endpoint.Address = source.Source + suffix
+			endpoint.Reference = digest
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if !mirrored {
+		endpoint := Endpoint{
+			Name:      "release image",
+			Kind:      KindRegistry,
+			Address:   release.Name(),
+			Reference: digest,
+		}
+		if tagged, ok := release.(dockerref.Tagged); ok && digest == "" {
+			endpoint.Reference = tagged.Tag()
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// Failures splits the results of unreachable endpoints into the failures,
+// without which the cluster cannot pull its images, and the warnings. An
+// unreachable mirror or source of an image content source is only a failure
+// when none of the mirrors and the source of that repository can be reached.
+func Failures(results []Result) (failures []Result, warnings []Result) {
+	reachable := map[string]bool{}
+	for _, result := range results {
+		if result.Endpoint.Source != "" && result.Err == nil {
+			reachable[result.Endpoint.Source] = true
+		}
+	}
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+		case reachable[result.Endpoint.Source]:
+			warnings = append(warnings, result)
+		default:
+			failures = append(failures, result)
+		}
+	}
+	return failures, warnings
+}
+
+// matchSource returns whether the repository is the source or one of the
+// repositories under it, and the rest of the repository after the source.
+func matchSource(source, repository string) (string, bool) {
+	if repository == source {
+		return "", true
+	}
+	if strings.HasPrefix(repository, source+"/") {
+		return strings.TrimPrefix(repository, source), true
+	}
+	return "", false
+}
@@ -0,0 +1,346 @@
+package reachability
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const releaseDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestEndpoints(t *testing.T) {
+	cases := []struct {
+		name         string
+		config       *types.InstallConfig
+		releaseImage string
+		expected     []Endpoint
+	}{{
+		name:         "release image",
+		config:       &types.InstallConfig{},
+		releaseImage: "quay.io/openshift-release-dev/ocp-release:4.8.0-x86_64",
+		expected: []Endpoint{
+			{Name: "release image", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-release", Reference: "4.8.0-x86_64"},
+		},
+	}, {
+		name: "proxy",
+		config: &types.InstallConfig{
+			Proxy: &types.Proxy{HTTPProxy: "http://proxy.example.com:3128", HTTPSProxy: "https://proxy.example.com:3129"},
+		},
+		releaseImage: "quay.io/openshift-release-dev/ocp-release@" + releaseDigest,
+		expected: []Endpoint{
+			{Name: "proxy.httpProxy", Kind: KindProxy, Address: "http://proxy.example.com:3128"},
+			{Name: "proxy.httpsProxy", Kind: KindProxy, Address: "https://proxy.example.com:3129"},
+			{Name: "release image", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-release", Reference: releaseDigest},
+		},
+	}, {
+		name: "mirrored release image",
+		config: &types.InstallConfig{
+			ImageContentSources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev",
+				Mirrors: []string{"mirror.example.com/ocp4", "mirror2.example.com/ocp4"},
+			}, {
+				Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+				Mirrors: []string{"mirror.example.com/ocp4/art"},
+			}},
+		},
+		releaseImage: "quay.io/openshift-release-dev/ocp-release@" + releaseDigest,
+		expected: []Endpoint{
+			{Name: "imageContentSources[0].mirrors[0]", Kind: KindRegistry, Address: "mirror.example.com/ocp4/ocp-release", Reference: releaseDigest, Source: "quay.io/openshift-release-dev"},
+			{Name: "imageContentSources[0].mirrors[1]", Kind: KindRegistry, Address: "mirror2.example.com/ocp4/ocp-release", Reference: releaseDigest, Source: "quay.io/openshift-release-dev"},
+			{Name: "imageContentSources[0].source", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-release", Reference: releaseDigest, Source: "quay.io/openshift-release-dev"},
+			{Name: "imageContentSources[1].mirrors[0]", Kind: KindRegistry, Address: "mirror.example.com/ocp4/art", Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev"},
+			{Name: "imageContentSources[1].source", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-v4.0-art-dev", Source: "quay.io/openshift-release-dev/ocp-v4.0-art-dev"},
+		},
+	}, {
+		name: "release image pulled by tag is not mirrored",
+		config: &types.InstallConfig{
+			ImageContentSources: []types.ImageContentSource{{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{"mirror.example.com/ocp4/ocp-release"},
+			}},
+		},
+		releaseImage: "quay.io/openshift-release-dev/ocp-release:4.8.0-x86_64",
+		expected: []Endpoint{
+			{Name: "imageContentSources[0].mirrors[0]", Kind: KindRegistry, Address: "mirror.example.com/ocp4/ocp-release", Source: "quay.io/openshift-release-dev/ocp-release"},
+			{Name: "imageContentSources[0].source", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-release", Source: "quay.io/openshift-release-dev/ocp-release"},
+			{Name: "release image", Kind: KindRegistry, Address: "quay.io/openshift-release-dev/ocp-release", Reference: "4.8.0-x86_64"},
+		},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			endpoints, err := Endpoints(test.config, test.releaseImage)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, endpoints)
+			}
+		})
+	}
+}
+
+func TestFailures(t *testing.T) {
+	unreachable := errors.New("unreachable")
+	proxy := Endpoint{Name: "proxy.httpsProxy", Kind: KindProxy, Address: "https://proxy.example.com:3129"}
+	mirror := Endpoint{Name: "imageContentSources[0].mirrors[0]", Kind: KindRegistry, Address: "mirror.example.com/ocp4", Source: "quay.io/openshift-release-dev"}
+	source := Endpoint{Name: "imageContentSources[0].source", Kind: KindRegistry, Address: "quay.io/openshift-release-dev", Source: "quay.io/openshift-release-dev"}
+	other := Endpoint{Name: "imageContentSources[1].mirrors[0]", Kind: KindRegistry, Address: "mirror.example.com/art", Source: "quay.io/openshift-release-dev/art"}
+
+	cases := []struct {
+		name     string
+		results  []Result
+		failures []Result
+		warnings []Result
+	}{{
+		name:    "all reachable",
+		results: []Result{{Endpoint: proxy}, {Endpoint: mirror}, {Endpoint: source}},
+	}, {
+		name:     "unreachable proxy",
+		results:  []Result{{Endpoint: proxy, Err: unreachable}, {Endpoint: mirror}},
+		failures: []Result{{Endpoint: proxy, Err: unreachable}},
+	}, {
+		name:     "unreachable source with a reachable mirror",
+		results:  []Result{{Endpoint: mirror}, {Endpoint: source, Err: unreachable}},
+		warnings: []Result{{Endpoint: source, Err: unreachable}},
+	}, {
+		name:     "unreachable mirror with a reachable source",
+		results:  []Result{{Endpoint: mirror, Err: unreachable}, {Endpoint: source}},
+		warnings: []Result{{Endpoint: mirror, Err: unreachable}},
+	}, {
+		name:     "unreachable mirrors and source",
+		results:  []Result{{Endpoint: mirror, Err: unreachable}, {Endpoint: source, Err: unreachable}, {Endpoint: other}},
+		failures: []Result{{Endpoint: mirror, Err: unreachable}, {Endpoint: source, Err: unreachable}},
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			failures, warnings := Failures(test.results)
+			assert.Equal(t, test.failures, failures)
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	registry := newFakeRegistry(t, "user:password")
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(registry.Certificate())
+	credentials := map[string]string{host: base64.StdEncoding.EncodeToString([]byte("user:password"))}
+
+	cases := []struct {
+		name     string
+		roots    *x509.CertPool
+		auths    map[string]string
+		endpoint Endpoint
+		err      string
+	}{{
+		name:     "release image",
+		roots:    trusted,
+		auths:    credentials,
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release", Reference: releaseDigest},
+	}, {
+		name:     "mirror repository",
+		roots:    trusted,
+		auths:    map[string]string{host + "/ocp": credentials[host]},
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release"},
+	}, {
+		name:     "missing image",
+		roots:    trusted,
+		auths:    credentials,
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release", Reference: "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		err:      "image sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff is not in " + host + "/ocp/release",
+	}, {
+		name:     "missing repository",
+		roots:    trusted,
+		auths:    credentials,
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/missing"},
+		err:      "repository " + host + "/ocp/missing was not found",
+	}, {
+		name:     "no credentials",
+		roots:    trusted,
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release"},
+		err:      "access to " + host + "/ocp/release was denied and the pull secret has no credentials for " + host,
+	}, {
+		name:     "wrong credentials",
+		roots:    trusted,
+		auths:    map[string]string{host: base64.StdEncoding.EncodeToString([]byte("user:wrong"))},
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release"},
+		err:      "the token service " + host + " rejected the credentials of the pull secret",
+	}, {
+		name:     "untrusted registry",
+		roots:    x509.NewCertPool(),
+		auths:    credentials,
+		endpoint: Endpoint{Kind: KindRegistry, Address: host + "/ocp/release"},
+		err:      "the TLS certificate of " + host + " is not signed by a CA of the system or of additionalTrustBundle",
+	}, {
+		name:     "unresolved registry",
+		roots:    trusted,
+		endpoint: Endpoint{Kind: KindRegistry, Address: "registry.invalid/ocp/release"},
+		err:      "failed to resolve registry.invalid: no such host",
+	}, {
+		name:     "proxy",
+		roots:    trusted,
+		endpoint: Endpoint{Kind: KindProxy, Address: registry.URL},
+	}, {
+		name:     "untrusted proxy",
+		roots:    x509.NewCertPool(),
+		endpoint: Endpoint{Kind: KindProxy, Address: registry.URL},
+		err:      "the TLS certificate of " + host + " is not signed by a CA of the system or of additionalTrustBundle",
+	}, {
+		name:     "unresolved proxy",
+		roots:    trusted,
+		endpoint: Endpoint{Kind: KindProxy, Address: "http://proxy.invalid:3128"},
+		err:      "failed to resolve proxy.invalid: no such host",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			checker := &Checker{
+				RootCAs:    test.roots,
+				Auths:      test.auths,
+				Timeout:    10 * time.Second,
+				LookupHost: fakeLookupHost,
+			}
+			results := checker.Check(context.Background(), []Endpoint{test.endpoint})
+			if !assert.Len(t, results, 1) {
+				return
+			}
+			assert.Equal(t, test.endpoint, results[0].Endpoint)
+			if test.err == "" {
+				assert.NoError(t, results[0].Err)
+			} else if assert.Error(t, results[0].Err) {
+				assert.Contains(t, results[0].Err.Error(), test.err)
+			}
+		})
+	}
+}
+
+func Test_noProxy(t *testing.T) {
+	cases := []struct {
+		list     string
+		host     string
+		expected bool
+	}{{
+		list: "",
+		host: "quay.io",
+	}, {
+		list:     "*",
+		host:     "quay.io",
+		expected: true,
+	}, {
+		list:     "example.com, quay.io",
+		host:     "quay.io",
+		expected: true,
+	}, {
+		list:     ".example.com",
+		host:     "mirror.example.com",
+		expected: true,
+	}, {
+		list:     "example.com",
+		host:     "mirror.example.com",
+		expected: true,
+	}, {
+		list: "example.com",
+		host: "notexample.com",
+	}, {
+		list:     "10.0.0.0/16",
+		host:     "10.0.1.5",
+		expected: true,
+	}, {
+		list: "10.0.0.0/16",
+		host: "10.1.0.5",
+	}, {
+		list:     "192.168.1.10",
+		host:     "192.168.1.10",
+		expected: true,
+	}}
+	for _, test := range cases {
+		t.Run(fmt.Sprintf("%s %s", test.list, test.host), func(t *testing.T) {
+			assert.Equal(t, test.expected, noProxy(test.list, test.host))
+		})
+	}
+}
+
+func Test_parseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:ocp/release:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:ocp/release:pull",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm="registry"`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
+
+func Test_parseAuths(t *testing.T) {
+	auths, err := parseAuths(`{"auths":{"quay.io":{"auth":"YQ=="},"https://registry.example.com/":{"auth":"Yg=="},"mirror.example.com/ocp":{"auth":"Yw=="},"store.example.com":{"credsStore":"secret"}}}`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{
+		"quay.io":                "YQ==",
+		"registry.example.com":   "Yg==",
+		"mirror.example.com/ocp": "Yw==",
+	}, auths)
+
+	checker := &Checker{Auths: auths}
+	assert.Equal(t, "YQ==", checker.auth("quay.io", "openshift-release-dev/ocp-release"))
+	assert.Equal(t, "Yw==", checker.auth("mirror.example.com", "ocp/release"))
+	assert.Equal(t, "", checker.auth("mirror.example.com", "other/release"))
+}
+
+// newFakeRegistry returns a registry that requires a bearer token from its
+// token service for the credentials, and has the repository ocp/release
+// with the image of releaseDigest.
+func newFakeRegistry(t *testing.T, credentials string) *httptest.Server {
+	const token = "t0k3n"
+	var server *httptest.Server
+	server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, password, ok := r.BasicAuth()
+			if !ok || user+":"+password != credentials {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Regexp(t, `^repository:ocp/[a-z]+:pull$`, r.URL.Query().Get("scope"))
+			fmt.Fprintf(w, `{"token": %q}`, token)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/ocp/release/manifests/" + releaseDigest, "/v2/ocp/release/tags/list":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	// The checks of untrusted certificates fail the handshakes.
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	return server
+}
+
+func fakeLookupHost(ctx context.Context, host string) ([]string, error) {
+	if strings.HasSuffix(host, ".invalid") {
+		return nil, errors.New("no such host")
+	}
+	return []string{"127.0.0.1"}, nil
+}
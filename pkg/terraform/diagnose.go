@@ -1,91 +1,194 @@
 package terraform
 
 import (
+	"io/ioutil"
 	"regexp"
+	"sync"
 
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
 
 	"github.com/openshift/installer/pkg/diagnostics"
 )
 
-// Diagnose accepts an error from terraform runs and tries to diagnose the
-// underlying cause.
-func Diagnose(message string) error {
-	for _, cand := range conditions {
-		if cand.match.MatchString(message) {
-			return &diagnostics.Err{
-				Source:  "Infrastructure Provider",
-				Reason:  cand.reason,
-				Message: cand.message,
-			}
+const (
+	// DiagnosticsRulesEnvVar names the environment variable that points to a
+	// file with additional diagnostics rules.
+	DiagnosticsRulesEnvVar = "OPENSHIFT_INSTALL_DIAGNOSTICS_RULES"
+
+	source = "Infrastructure Provider"
+
+	docsBaseURL = "https://github.com/openshift/installer/blob/master/docs/user/"
+)
+
+// Rule matches an error from terraform and describes its underlying cause.
+type Rule struct {
+	// Platform is the platform the rule applies to. Rules without a
+	// platform apply to any platform.
+	Platform string `json:"platform,omitempty"`
+
+	// Match is the regular expression matched against the terraform error.
+	Match string `json:"match"`
+
+	// Reason is a CamelCase reason for the error.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of the error.
+	Message string `json:"message"`
+
+	// Remediation suggests how the user can fix the error.
+	Remediation string `json:"remediation,omitempty"`
+
+	// DocsURL links to documentation about the error.
+	DocsURL string `json:"docsURL,omitempty"`
+
+	match *regexp.Regexp
+}
+
+// compile validates the rule and compiles its expression.
+func (r *Rule) compile() error {
+	if r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	if r.Message == "" {
+		return errors.Errorf("rule %s: message is required", r.Reason)
+	}
+	if r.Match == "" {
+		return errors.Errorf("rule %s: match is required", r.Reason)
+	}
+	match, err := regexp.Compile(r.Match)
+	if err != nil {
+		return errors.Wrapf(err, "rule %s: invalid match", r.Reason)
+	}
+	r.match = match
+	return nil
+}
+
+// appliesTo returns true if the rule applies to the platform. All rules
+// apply when the platform is not known.
+func (r *Rule) appliesTo(platform string) bool {
+	if alias, ok := platformAliases[platform]; ok {
+		platform = alias
+	}
+	return r.Platform == "" || platform == "" || r.Platform == platform
+}
+
+// Err returns the diagnostics error described by the rule.
+func (r *Rule) Err() *diagnostics.Err {
+	return &diagnostics.Err{
+		Source:      source,
+		Reason:      r.Reason,
+		Message:     r.Message,
+		Remediation: r.Remediation,
+		DocsURL:     r.DocsURL,
+	}
+}
+
+var (
+	rulesLock sync.RWMutex
+
+	// builtinRules are the rules shipped with the installer, in the order
+	// they are matched.
+	builtinRules []Rule
+
+	// userRules are the rules loaded with LoadRules. They are matched
+	// before the builtin rules so that users can override them.
+	userRules []Rule
+)
+
+// platformAliases maps the names of the terraform platforms that share the
+// rules of an installer platform to the name of that platform.
+var platformAliases = map[string]string{
+	"azurestack": "azure",
+}
+
+func init() {
+	// Register the rules of each platform explicitly, in the order they
+	// are matched, rather than from the init functions of their files,
+	// which run in file name order.
+	for _, rules := range [][]Rule{
+		azureRules,
+		gcpRules,
+		baremetalRules,
+		awsRules,
+		openstackRules,
+		ovirtRules,
+		vsphereRules,
+	} {
+		RegisterRules(rules...)
+	}
+}
+
+// RegisterRules adds builtin rules to the registry. Specific rules should be
+// registered before generic ones. It panics for invalid rules.
+func RegisterRules(rules ...Rule) {
+	rulesLock.Lock()
+	defer rulesLock.Unlock()
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			panic(err)
 		}
+		builtinRules = append(builtinRules, rule)
 	}
+}
 
-	return errors.New("failed to complete the change")
+// LoadRules reads a YAML (or JSON) list of rules from path and adds them to
+// the registry ahead of the builtin rules.
+func LoadRules(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read diagnostics rules")
+	}
+	var rules []Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal diagnostics rules from %s", path)
+	}
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return errors.Wrapf(err, "invalid diagnostics rule %d in %s", i, path)
+		}
+	}
+
+	rulesLock.Lock()
+	defer rulesLock.Unlock()
+	userRules = append(userRules, rules...)
+	return nil
 }
 
-type condition struct {
-	match *regexp.Regexp
+// Rules returns the registered rules in the order they are matched.
+func Rules() []Rule {
+	rulesLock.RLock()
+	defer rulesLock.RUnlock()
+	rules := make([]Rule, 0, len(userRules)+len(builtinRules))
+	rules = append(rules, userRules...)
+	return append(rules, builtinRules...)
+}
+
+// Diagnose accepts an error from terraform runs on the given platform and
+// tries to diagnose the underlying cause. Rules for other platforms are
+// ignored, unless the platform is empty.
+func Diagnose(platform, message string) error {
+	for _, rule := range Rules() {
+		if rule.appliesTo(platform) && rule.match.MatchString(message) {
+			return rule.Err()
+		}
+	}
 
-	reason  string
-	message string
+	return errors.New("failed to complete the change")
 }
 
-// conditions is a list matches for the error string from terraform.
-// specific on the top, generic matches on the bottom.
-var conditions = []condition{{
-	match: regexp.MustCompile(`Error: Error creating Blob .*: Error copy/waiting`),
-
-	reason:  "Timeout",
-	message: `Copying the VHD to user environment was too slow, and timeout was reached for the success.`,
-}, {
-	match: regexp.MustCompile(`Error: Error Creating/Updating Subnet .*: network.SubnetsClient#CreateOrUpdate: .* Code="AnotherOperationInProgress" Message="Another operation on this or dependent resource is in progress`),
-
-	reason:  "AzureMultiOperationFailure",
-	message: `Creating Subnets failed because Azure could not process multiple operations.`,
-}, {
-	match: regexp.MustCompile(`Error: Error Creating/Updating Public IP .*: network.PublicIPAddressesClient#CreateOrUpdate: .* Code="PublicIPCountLimitReached" Message="Cannot create more than .* public IP addresses for this subscription in this region`),
-
-	reason:  "AzureQuotaLimitExceeded",
-	message: `Service limits exceeded for Public IPs in the the subscriptions for the region. Requesting increase in quota should fix the error.`,
-}, {
-	match: regexp.MustCompile(`Error: compute\.VirtualMachinesClient#CreateOrUpdate: .* Code="OperationNotAllowed" Message="Operation could not be completed as it results in exceeding approved Total Regional Cores quota`),
-
-	reason:  "AzureQuotaLimitExceeded",
-	message: `Service limits exceeded for Virtual Machine cores in the the subscriptions for the region. Requesting increase in quota should fix the error.`,
-}, {
-	match: regexp.MustCompile(`Error: Code="OSProvisioningTimedOut"`),
-
-	reason:  "AzureVirtualMachineFailure",
-	message: `Some virtual machines failed to provision in alloted time. Virtual machines can fail to provision if the bootstap virtual machine has failing services.`,
-}, {
-	match: regexp.MustCompile(`Status=404 Code="ResourceGroupNotFound"`),
-
-	reason:  "AzureEventualConsistencyFailure",
-	message: `Failed to find a resource that was recently created usualy caused by Azure's eventual consistency delays.`,
-}, {
-	match: regexp.MustCompile(`Error: Error applying IAM policy to project .*: Too many conflicts`),
-
-	reason:  "GCPTooManyIAMUpdatesInFlight",
-	message: `There are a lot of IAM updates to the project in flight. Failed after reaching a limit of read-modify-write on conflict backoffs.`,
-}, {
-	match: regexp.MustCompile(`Error: .*: googleapi: Error 503: .*, backendError`),
-
-	reason:  "GCPBackendInternalError",
-	message: `GCP is experiencing backend service interuptions. Please try again or contact Google Support`,
-}, {
-	match: regexp.MustCompile(`Error: Error waiting for instance to create: Internal error`),
-
-	reason:  "GCPComputeBackendTimeout",
-	message: `GCP is experiencing backend service interuptions, the compute instance failed to create in reasonable time.`,
-}, {
-	match: regexp.MustCompile(`Error: could not contact Ironic API: timeout reached`),
-
-	reason:  "BaremetalIronicAPITimeout",
-	message: `Unable to the reach provisioning service. This failure can be caused by incorrect network/proxy settings, inability to download the machine operating system images, or other misconfiguration. Please check access to the bootstrap host, and for any failing services.`,
-}, {
-	match: regexp.MustCompile(`Error: could not inspect: could not inspect node, node is currently 'inspect failed', last error was 'timeout reached while inspecting the node'`),
-
-	reason:  "BaremetalIronicInspectTimeout",
-	message: `Timed out waiting for node inspection to complete. Please check the console on the host for more details.`,
-}}
+// DiagnoseAll returns the diagnoses of every rule for the platform that
+// matches the output of terraform runs, with at most one diagnosis per
+// reason. Rules for all platforms are matched when the platform is empty.
+func DiagnoseAll(platform, message string) []*diagnostics.Err {
+	var diagnoses []*diagnostics.Err
+	seen := map[string]bool{}
+	for _, rule := range Rules() {
+		if seen[rule.Reason] || !rule.appliesTo(platform) || !rule.match.MatchString(message) {
+			continue
+		}
+		seen[rule.Reason] = true
+		diagnoses = append(diagnoses, rule.Err())
+	}
+	return diagnoses
+}
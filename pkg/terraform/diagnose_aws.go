@@ -0,0 +1,26 @@
+package terraform
+
+var awsRules = []Rule{{
+	Platform: "aws",
+	Match:    `Error: .*(Throttling: Rate exceeded|RequestLimitExceeded: Request limit exceeded)`,
+
+	Reason:      "AWSThrottling",
+	Message:     `AWS rejected requests because the request rate limit of the account was exceeded.`,
+	Remediation: `Reduce other API activity in the account and region, then destroy the cluster and retry the installation.`,
+}, {
+	Platform: "aws",
+	Match:    `Error: .*VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit`,
+
+	Reason:      "AWSQuotaLimitExceeded",
+	Message:     `Service limits exceeded for vCPUs in the account for the region.`,
+	Remediation: `Request an increase of the Running On-Demand instances vCPU limit for the region.`,
+	DocsURL:     docsBaseURL + "aws/limits.md",
+}, {
+	Platform: "aws",
+	Match:    `Error: .*AddressLimitExceeded: The maximum number of addresses has been reached`,
+
+	Reason:      "AWSQuotaLimitExceeded",
+	Message:     `Service limits exceeded for Elastic IPs in the account for the region.`,
+	Remediation: `Request an Elastic IP limit increase for the region, or release unused Elastic IPs.`,
+	DocsURL:     docsBaseURL + "aws/limits.md",
+}}
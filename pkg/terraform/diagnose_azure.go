@@ -0,0 +1,48 @@
+package terraform
+
+var azureRules = []Rule{{
+	Platform: "azure",
+	Match:    `Error: Error creating Blob .*: Error copy/waiting`,
+
+	Reason:      "Timeout",
+	Message:     `Copying the VHD to user environment was too slow, and timeout was reached for the success.`,
+	Remediation: `Retry the installation from a location with a faster connection to the Azure region.`,
+}, {
+	Platform: "azure",
+	Match:    `Error: Error Creating/Updating Subnet .*: network.SubnetsClient#CreateOrUpdate: .* Code="AnotherOperationInProgress" Message="Another operation on this or dependent resource is in progress`,
+
+	Reason:      "AzureMultiOperationFailure",
+	Message:     `Creating Subnets failed because Azure could not process multiple operations.`,
+	Remediation: `Destroy the cluster and retry the installation.`,
+}, {
+	Platform: "azure",
+	Match:    `Error: Error Creating/Updating Public IP .*: network.PublicIPAddressesClient#CreateOrUpdate: .* Code="PublicIPCountLimitReached" Message="Cannot create more than .* public IP addresses for this subscription in this region`,
+
+	Reason:      "AzureQuotaLimitExceeded",
+	Message:     `Service limits exceeded for Public IPs in the the subscriptions for the region. Requesting increase in quota should fix the error.`,
+	Remediation: `Request a Public IP quota increase for the region, or remove unused Public IPs from the subscription.`,
+	DocsURL:     docsBaseURL + "azure/limits.md",
+}, {
+	Platform: "azure",
+	Match:    `Error: compute\.VirtualMachinesClient#CreateOrUpdate: .* Code="OperationNotAllowed" Message="Operation could not be completed as it results in exceeding approved Total Regional Cores quota`,
+
+	Reason:      "AzureQuotaLimitExceeded",
+	Message:     `Service limits exceeded for Virtual Machine cores in the the subscriptions for the region. Requesting increase in quota should fix the error.`,
+	Remediation: `Request a Total Regional vCPUs quota increase for the region, or use smaller instance types.`,
+	DocsURL:     docsBaseURL + "azure/limits.md",
+}, {
+	Platform: "azure",
+	Match:    `Error: Code="OSProvisioningTimedOut"`,
+
+	Reason:      "AzureVirtualMachineFailure",
+	Message:     `Some virtual machines failed to provision in alloted time. Virtual machines can fail to provision if the bootstap virtual machine has failing services.`,
+	Remediation: `Run "openshift-install gather bootstrap" and check the services on the bootstrap virtual machine.`,
+	DocsURL:     docsBaseURL + "troubleshootingbootstrap.md",
+}, {
+	Platform: "azure",
+	Match:    `Status=404 Code="ResourceGroupNotFound"`,
+
+	Reason:      "AzureEventualConsistencyFailure",
+	Message:     `Failed to find a resource that was recently created usualy caused by Azure's eventual consistency delays.`,
+	Remediation: `Destroy the cluster and retry the installation.`,
+}}
@@ -0,0 +1,16 @@
+package terraform
+
+var baremetalRules = []Rule{{
+	Platform: "baremetal",
+	Match:    `Error: could not contact Ironic API: timeout reached`,
+
+	Reason:  "BaremetalIronicAPITimeout",
+	Message: `Unable to the reach provisioning service. This failure can be caused by incorrect network/proxy settings, inability to download the machine operating system images, or other misconfiguration. Please check access to the bootstrap host, and for any failing services.`,
+}, {
+	Platform: "baremetal",
+	Match:    `Error: could not inspect: could not inspect node, node is currently 'inspect failed', last error was 'timeout reached while inspecting the node'`,
+
+	Reason:      "BaremetalIronicInspectTimeout",
+	Message:     `Timed out waiting for node inspection to complete. Please check the console on the host for more details.`,
+	Remediation: `Check that the host can boot from the provisioning network and reach the bootstrap host.`,
+}}
@@ -0,0 +1,30 @@
+package terraform
+
+var gcpRules = []Rule{{
+	Platform: "gcp",
+	Match:    `Error: Error applying IAM policy to project .*: Too many conflicts`,
+
+	Reason:      "GCPTooManyIAMUpdatesInFlight",
+	Message:     `There are a lot of IAM updates to the project in flight. Failed after reaching a limit of read-modify-write on conflict backoffs.`,
+	Remediation: `Wait for other changes to the IAM policy of the project to finish, then destroy the cluster and retry the installation.`,
+}, {
+	Platform: "gcp",
+	Match:    `Error: .*: googleapi: Error 503: .*, backendError`,
+
+	Reason:  "GCPBackendInternalError",
+	Message: `GCP is experiencing backend service interuptions. Please try again or contact Google Support`,
+}, {
+	Platform: "gcp",
+	Match:    `Error: Error waiting for instance to create: Internal error`,
+
+	Reason:  "GCPComputeBackendTimeout",
+	Message: `GCP is experiencing backend service interuptions, the compute instance failed to create in reasonable time.`,
+}, {
+	Platform: "gcp",
+	Match:    `Error: .*: googleapi: Error 403: Quota '.*' exceeded`,
+
+	Reason:      "GCPQuotaLimitExceeded",
+	Message:     `Service limits exceeded for the project in the region.`,
+	Remediation: `Request a quota increase for the project, or remove unused resources from it.`,
+	DocsURL:     docsBaseURL + "gcp/limits.md",
+}}
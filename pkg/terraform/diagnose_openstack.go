@@ -0,0 +1,19 @@
+package terraform
+
+var openstackRules = []Rule{{
+	Platform: "openstack",
+	Match:    `Error: .*Quota exceeded for (cores|ram|instances|resources)`,
+
+	Reason:      "OpenStackQuotaLimitExceeded",
+	Message:     `The project quota is too small for the requested resources.`,
+	Remediation: `Request a quota increase for the project, or use smaller flavors.`,
+	DocsURL:     docsBaseURL + "openstack/README.md",
+}, {
+	Platform: "openstack",
+	Match:    `Error: .*Maximum number of volumes allowed \(\d+\) exceeded`,
+
+	Reason:      "OpenStackQuotaLimitExceeded",
+	Message:     `The project quota for volumes is too small for the requested resources.`,
+	Remediation: `Request a volume quota increase for the project.`,
+	DocsURL:     docsBaseURL + "openstack/README.md",
+}}
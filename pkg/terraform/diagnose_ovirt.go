@@ -0,0 +1,17 @@
+package terraform
+
+var ovirtRules = []Rule{{
+	Platform: "ovirt",
+	Match:    `Error: .*Cannot add VM\. There is no active Host in the Cluster`,
+
+	Reason:      "OvirtNoActiveHost",
+	Message:     `The oVirt cluster has no active host to run the virtual machines.`,
+	Remediation: `Activate a host in the oVirt cluster and retry the installation.`,
+}, {
+	Platform: "ovirt",
+	Match:    `Error: .*Low disk space on Storage Domain`,
+
+	Reason:      "OvirtStorageDomainLowDiskSpace",
+	Message:     `The oVirt storage domain does not have enough free space for the virtual machine disks.`,
+	Remediation: `Free space on the storage domain, or select another storage domain.`,
+}}
@@ -1,6 +1,9 @@
 package terraform
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,11 +110,42 @@ Error: could not inspect: could not inspect node, node is currently 'inspect fai
 `,
 
 		err: `error\(BaremetalIronicInspectTimeout\) from Infrastructure Provider: Timed out waiting for node inspection to complete\. Please check the console on the host for more details\.`,
+	}, {
+		input: `
+Error: error creating EC2 VPC: RequestLimitExceeded: Request limit exceeded.
+	status code: 503, request id: 3b5d2a4c-7c21-4a39-a3f1-5a7b3a0f8d1e
+`,
+
+		err: `error\(AWSThrottling\) from Infrastructure Provider: AWS rejected requests because the request rate limit of the account was exceeded\.`,
+	}, {
+		input: `
+Error: Error creating OpenStack server: Quota exceeded for cores: Requested 4, but already used 20 of 20 cores
+
+  on ../tmp/openshift-install-185521233/bootstrap/main.tf line 1, in resource "openstack_compute_instance_v2" "bootstrap":
+`,
+
+		err: `error\(OpenStackQuotaLimitExceeded\) from Infrastructure Provider: The project quota is too small for the requested resources\.`,
+	}, {
+		input: `
+Error: error cloning virtual machine: ServerFaultCode: Permission to perform this operation was denied.
+`,
+
+		err: `error\(VSpherePermissionDenied\) from Infrastructure Provider: The vCenter user is missing privileges required to create the cluster\.`,
+	}, {
+		input: `
+Error: Fault reason is "Operation Failed". Fault detail is "[Cannot add VM. There is no active Host in the Cluster.]". HTTP response code is "409". HTTP response message is "409 Conflict".
+`,
+
+		err: `error\(OvirtNoActiveHost\) from Infrastructure Provider: The oVirt cluster has no active host to run the virtual machines\.`,
+	}, {
+		input: `Error: something unexpected happened`,
+
+		err: `^failed to complete the change$`,
 	}}
 
 	for _, test := range cases {
 		t.Run("", func(t *testing.T) {
-			err := Diagnose(test.input)
+			err := Diagnose("", test.input)
 			if test.err == "" {
 				assert.NoError(t, err)
 			} else {
@@ -120,3 +154,61 @@ Error: could not inspect: could not inspect node, node is currently 'inspect fai
 		})
 	}
 }
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { userRules = nil }()
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := ioutil.WriteFile(invalid, []byte("- match: '('\n  reason: Broken\n  message: broken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	assert.Regexp(t, `^invalid diagnostics rule 0 in .*/invalid\.yaml: rule Broken: invalid match: `, LoadRules(invalid))
+
+	rules := filepath.Join(dir, "rules.yaml")
+	if err := ioutil.WriteFile(rules, []byte(`
+- platform: azure
+  match: 'Code="OSProvisioningTimedOut"'
+  reason: SlowProvisioning
+  message: The virtual machines were slow to provision.
+  remediation: Retry in another region.
+  docsURL: https://example.com/slow
+- match: 'Error: custom failure'
+  reason: CustomFailure
+  message: A custom failure.
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, LoadRules(rules))
+
+	err = Diagnose("azure", `Error: Code="OSProvisioningTimedOut" Message="OS Provisioning for VM 'xxxx-master-2' did not finish in the allotted time."`)
+	assert.Regexp(t, `^error\(SlowProvisioning\) from Infrastructure Provider: The virtual machines were slow to provision\.$`, err)
+
+	diagnoses := DiagnoseAll("", "Error: custom failure\nError: Code=\"OSProvisioningTimedOut\"")
+	reasons := []string{}
+	for _, d := range diagnoses {
+		reasons = append(reasons, d.Reason)
+	}
+	assert.Equal(t, []string{"SlowProvisioning", "CustomFailure", "AzureVirtualMachineFailure"}, reasons)
+	assert.Equal(t, "Retry in another region.", diagnoses[0].Remediation)
+	assert.Equal(t, "https://example.com/slow", diagnoses[0].DocsURL)
+}
+
+func TestDiagnosePlatform(t *testing.T) {
+	throttled := "Error: error creating EC2 VPC: RequestLimitExceeded: Request limit exceeded."
+	assert.Regexp(t, `^error\(AWSThrottling\) `, Diagnose("aws", throttled))
+	assert.Regexp(t, `^failed to complete the change$`, Diagnose("gcp", throttled))
+
+	timedOut := `Error: Code="OSProvisioningTimedOut"`
+	assert.Regexp(t, `^error\(AzureVirtualMachineFailure\) `, Diagnose("azurestack", timedOut))
+	assert.Empty(t, DiagnoseAll("aws", timedOut))
+}
+
+func TestRuleRequiresMatch(t *testing.T) {
+	rule := Rule{Reason: "Everything", Message: "Matches every error."}
+	assert.EqualError(t, rule.compile(), "rule Everything: match is required")
+}
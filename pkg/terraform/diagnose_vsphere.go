@@ -0,0 +1,11 @@
+package terraform
+
+var vsphereRules = []Rule{{
+	Platform: "vsphere",
+	Match:    `Error: .*(Permission to perform this operation was denied|NoPermission)`,
+
+	Reason:      "VSpherePermissionDenied",
+	Message:     `The vCenter user is missing privileges required to create the cluster.`,
+	Remediation: `Grant the vCenter user the privileges listed in the documentation and retry the installation.`,
+	DocsURL:     docsBaseURL + "vsphere/privileges.md",
+}}
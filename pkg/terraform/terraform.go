@@ -44,7 +44,7 @@ func Apply(dir string, platform string, stage Stage, extraArgs ...string) (path
 
 	errBuf := &bytes.Buffer{}
 	if exitCode := texec.Apply(dir, args, lpDebug, io.MultiWriter(errBuf, lpError)); exitCode != 0 {
-		return sf, errors.Wrap(Diagnose(errBuf.String()), "failed to apply Terraform")
+		return sf, errors.Wrap(Diagnose(platform, errBuf.String()), "failed to apply Terraform")
 	}
 	return sf, nil
 }
@@ -81,6 +81,7 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		Environment:                 environment,
 		ARMEndpoint:                 sources.ARMEndpoint,
 		Region:                      region,
+		ExtraTags:                   masterConfig.Tags,
 		BootstrapInstanceType:       defaults.BootstrapInstanceType(sources.CloudName, region),
 		MasterInstanceType:          masterConfig.VMSize,
 		MasterAvailabilityZones:     masterAvailabilityZones,
@@ -2,6 +2,8 @@ package ibmcloud
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/openshift/installer/pkg/tfvars/internal/cache"
 	"github.com/openshift/installer/pkg/types"
@@ -32,6 +34,7 @@ type TFVarsSources struct {
 	CISInstanceCRN    string
 	PublishStrategy   types.PublishingStrategy
 	ResourceGroupName string
+	UserTags          map[string]string
 
 	// TODO: IBM: Fetch config from masterConfig instead
 	MachineType             string
@@ -64,6 +67,7 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		CISInstanceCRN:    sources.CISInstanceCRN,
 		PublishStrategy:   string(sources.PublishStrategy),
 		ResourceGroupName: sources.ResourceGroupName,
+		ExtraTags:         extraTags(sources.UserTags),
 
 		// TODO: IBM: Fetch config from masterConfig instead
 		BootstrapInstanceType:   sources.MachineType,
@@ -73,7 +77,6 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		ImageFilePath:           cachedImage,
 
 		// TODO: IBM: Future support
-		// ExtraTags:               masterConfig.Tags,
 		// Region:                  masterConfig.Region,
 		// BootstrapInstanceType:   masterConfig.MachineType,
 		// MasterInstanceType:      masterConfig.MachineType,
@@ -82,3 +85,13 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 
 	return json.MarshalIndent(cfg, "", "  ")
 }
+
+// extraTags returns the user tags as sorted key:value tags.
+func extraTags(userTags map[string]string) []string {
+	tags := make([]string, 0, len(userTags))
+	for key, value := range userTags {
+		tags = append(tags, fmt.Sprintf("%s:%s", key, value))
+	}
+	sort.Strings(tags)
+	return tags
+}
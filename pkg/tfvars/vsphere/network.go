@@ -0,0 +1,58 @@
+package vsphere
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// networkKargs returns the kernel arguments of the static network
+// configuration of a host, which afterburn passes to the initramfs from the
+// guestinfo.afterburn.initrd.network-kargs property of the virtual machine.
+func networkKargs(device vspheretypes.NetworkDeviceSpec) string {
+	var kargs []string
+	iface := device.Interface
+	if device.Bond != nil {
+		kargs = append(kargs, fmt.Sprintf("bond=%s:%s:mode=%s", vspheretypes.BondName, strings.Join(device.Bond.Interfaces, ","), device.Bond.Mode))
+		iface = vspheretypes.BondName
+	}
+	if device.VLAN != 0 {
+		vlan := fmt.Sprintf("%s.%d", iface, device.VLAN)
+		kargs = append(kargs, fmt.Sprintf("vlan=%s:%s", vlan, iface))
+		iface = vlan
+	}
+
+	gateway := net.ParseIP(device.Gateway)
+	for _, addr := range device.IPAddrs {
+		ip, subnet, err := net.ParseCIDR(addr)
+		if err != nil {
+			continue
+		}
+		gw := ""
+		if gateway != nil && subnet.Contains(gateway) {
+			gw = kargIP(gateway)
+		}
+		netmask := net.IP(subnet.Mask).String()
+		if ip.To4() == nil {
+			ones, _ := subnet.Mask.Size()
+			netmask = strconv.Itoa(ones)
+		}
+		kargs = append(kargs, fmt.Sprintf("ip=%s::%s:%s::%s:none", kargIP(ip), gw, netmask, iface))
+	}
+	for _, nameserver := range device.Nameservers {
+		kargs = append(kargs, fmt.Sprintf("nameserver=%s", nameserver))
+	}
+	return strings.Join(kargs, " ")
+}
+
+// kargIP returns the IP address in the form of the ip kernel argument, with
+// the IPv6 addresses in brackets.
+func kargIP(ip net.IP) string {
+	if ip.To4() == nil {
+		return fmt.Sprintf("[%s]", ip)
+	}
+	return ip.String()
+}
@@ -0,0 +1,52 @@
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestNetworkKargs(t *testing.T) {
+	cases := []struct {
+		name     string
+		device   vspheretypes.NetworkDeviceSpec
+		expected string
+	}{
+		{
+			name: "IPv4",
+			device: vspheretypes.NetworkDeviceSpec{
+				Interface:   "ens192",
+				IPAddrs:     []string{"10.0.0.10/24"},
+				Gateway:     "10.0.0.1",
+				Nameservers: []string{"10.0.0.2", "10.0.0.3"},
+			},
+			expected: "ip=10.0.0.10::10.0.0.1:255.255.255.0::ens192:none nameserver=10.0.0.2 nameserver=10.0.0.3",
+		},
+		{
+			name: "dual stack",
+			device: vspheretypes.NetworkDeviceSpec{
+				Interface: "ens192",
+				IPAddrs:   []string{"10.0.0.10/24", "fd00::10/64"},
+				Gateway:   "fd00::1",
+			},
+			expected: "ip=10.0.0.10:::255.255.255.0::ens192:none ip=[fd00::10]::[fd00::1]:64::ens192:none",
+		},
+		{
+			name: "bonded VLAN",
+			device: vspheretypes.NetworkDeviceSpec{
+				Bond:    &vspheretypes.Bond{Interfaces: []string{"ens192", "ens224"}, Mode: "active-backup"},
+				VLAN:    100,
+				IPAddrs: []string{"10.0.0.10/24"},
+				Gateway: "10.0.0.1",
+			},
+			expected: "bond=bond0:ens192,ens224:mode=active-backup vlan=bond0.100:bond0 ip=10.0.0.10::10.0.0.1:255.255.255.0::bond0.100:none",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, networkKargs(tc.device))
+		})
+	}
+}
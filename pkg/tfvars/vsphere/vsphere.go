@@ -6,26 +6,36 @@ import (
 
 	vsphereapis "github.com/openshift/machine-api-operator/pkg/apis/vsphereprovider/v1beta1"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openshift/installer/pkg/tfvars/internal/cache"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
 
 type config struct {
-	VSphereURL        string `json:"vsphere_url"`
-	VSphereUsername   string `json:"vsphere_username"`
-	VSpherePassword   string `json:"vsphere_password"`
-	MemoryMiB         int64  `json:"vsphere_control_plane_memory_mib"`
-	DiskGiB           int32  `json:"vsphere_control_plane_disk_gib"`
-	NumCPUs           int32  `json:"vsphere_control_plane_num_cpus"`
-	NumCoresPerSocket int32  `json:"vsphere_control_plane_cores_per_socket"`
-	Cluster           string `json:"vsphere_cluster"`
-	Datacenter        string `json:"vsphere_datacenter"`
-	Datastore         string `json:"vsphere_datastore"`
-	Folder            string `json:"vsphere_folder"`
-	Network           string `json:"vsphere_network"`
-	Template          string `json:"vsphere_template"`
-	OvaFilePath       string `json:"vsphere_ova_filepath"`
-	PreexistingFolder bool   `json:"vsphere_preexisting_folder"`
+	VSphereURL                 string                   `json:"vsphere_url"`
+	VSphereUsername            string                   `json:"vsphere_username"`
+	VSpherePassword            string                   `json:"vsphere_password"`
+	MemoryMiB                  int64                    `json:"vsphere_control_plane_memory_mib"`
+	DiskGiB                    int32                    `json:"vsphere_control_plane_disk_gib"`
+	NumCPUs                    int32                    `json:"vsphere_control_plane_num_cpus"`
+	NumCoresPerSocket          int32                    `json:"vsphere_control_plane_cores_per_socket"`
+	FailureDomains             map[string]failureDomain `json:"vsphere_failure_domains"`
+	ControlPlaneFailureDomains []string                 `json:"vsphere_control_plane_failure_domains"`
+	OvaFilePath                string                   `json:"vsphere_ova_filepath"`
+	BootstrapNetworkKargs      string                   `json:"vsphere_bootstrap_network_kargs"`
+	ControlPlaneNetworkKargs   []string                 `json:"vsphere_control_plane_network_kargs"`
+}
+
+// failureDomain is the vSphere objects of the control-plane machines cloned
+// from the same template.
+type failureDomain struct {
+	Datacenter        string `json:"datacenter"`
+	Cluster           string `json:"cluster"`
+	Datastore         string `json:"datastore"`
+	Network           string `json:"network"`
+	Folder            string `json:"folder"`
+	PreexistingFolder bool   `json:"preexisting_folder"`
 }
 
 // TFVarsSources contains the parameters to be converted into Terraform variables
@@ -33,9 +43,14 @@ type TFVarsSources struct {
 	ControlPlaneConfigs []*vsphereapis.VSphereMachineProviderSpec
 	Username            string
 	Password            string
-	Cluster             string
 	ImageURL            string
-	PreexistingFolder   bool
+
+	// PreexistingFolders are the absolute paths of the folders of the
+	// install config, which are used instead of being created.
+	PreexistingFolders []string
+
+	// Hosts are the static network configurations of the install config.
+	Hosts []vspheretypes.Host
 }
 
 //TFVars generate vSphere-specific Terraform variables
@@ -47,27 +62,61 @@ func TFVars(sources TFVarsSources) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to use cached vsphere image")
 	}
 
-	// The vSphere provider needs the relativepath of the folder,
-	// so get the relPath from the absolute path. Absolute path is always of the form
-	// /<datacenter>/vm/<folder_path> so we can split on "vm/".
-	folderRelPath := strings.SplitAfterN(controlPlaneConfig.Workspace.Folder, "vm/", 2)[1]
+	preexistingFolders := sets.NewString(sources.PreexistingFolders...)
+	failureDomains := map[string]failureDomain{}
+	controlPlaneFailureDomains := make([]string, 0, len(sources.ControlPlaneConfigs))
+	for _, c := range sources.ControlPlaneConfigs {
+		if c.Workspace.Server != controlPlaneConfig.Workspace.Server {
+			return nil, errors.Errorf("control-plane machines must be in the vCenter %s, not %s", controlPlaneConfig.Workspace.Server, c.Workspace.Server)
+		}
+
+		// The vSphere provider needs the relativepath of the folder,
+		// so get the relPath from the absolute path. Absolute path is always of the form
+		// /<datacenter>/vm/<folder_path> so we can split on "vm/".
+		folderRelPath := strings.SplitAfterN(c.Workspace.Folder, "vm/", 2)[1]
+
+		// The resource pool is always of the form /<datacenter>/host/<cluster>/Resources.
+		cluster := strings.TrimSuffix(strings.SplitAfterN(c.Workspace.ResourcePool, "host/", 2)[1], "/Resources")
+
+		failureDomains[c.Template] = failureDomain{
+			Datacenter:        c.Workspace.Datacenter,
+			Cluster:           cluster,
+			Datastore:         c.Workspace.Datastore,
+			Network:           c.Network.Devices[0].NetworkName,
+			Folder:            folderRelPath,
+			PreexistingFolder: preexistingFolders.Has(c.Workspace.Folder),
+		}
+		controlPlaneFailureDomains = append(controlPlaneFailureDomains, c.Template)
+	}
+
+	var bootstrapNetworkKargs string
+	controlPlaneNetworkKargs := make([]string, len(sources.ControlPlaneConfigs))
+	controlPlaneIdx := 0
+	for _, host := range sources.Hosts {
+		switch host.Role {
+		case vspheretypes.BootstrapRole:
+			bootstrapNetworkKargs = networkKargs(host.NetworkDevice)
+		case vspheretypes.ControlPlaneRole:
+			if controlPlaneIdx < len(controlPlaneNetworkKargs) {
+				controlPlaneNetworkKargs[controlPlaneIdx] = networkKargs(host.NetworkDevice)
+			}
+			controlPlaneIdx++
+		}
+	}
 
 	cfg := &config{
-		VSphereURL:        controlPlaneConfig.Workspace.Server,
-		VSphereUsername:   sources.Username,
-		VSpherePassword:   sources.Password,
-		MemoryMiB:         controlPlaneConfig.MemoryMiB,
-		DiskGiB:           controlPlaneConfig.DiskGiB,
-		NumCPUs:           controlPlaneConfig.NumCPUs,
-		NumCoresPerSocket: controlPlaneConfig.NumCoresPerSocket,
-		Cluster:           sources.Cluster,
-		Datacenter:        controlPlaneConfig.Workspace.Datacenter,
-		Datastore:         controlPlaneConfig.Workspace.Datastore,
-		Folder:            folderRelPath,
-		Network:           controlPlaneConfig.Network.Devices[0].NetworkName,
-		Template:          controlPlaneConfig.Template,
-		OvaFilePath:       cachedImage,
-		PreexistingFolder: sources.PreexistingFolder,
+		VSphereURL:                 controlPlaneConfig.Workspace.Server,
+		VSphereUsername:            sources.Username,
+		VSpherePassword:            sources.Password,
+		MemoryMiB:                  controlPlaneConfig.MemoryMiB,
+		DiskGiB:                    controlPlaneConfig.DiskGiB,
+		NumCPUs:                    controlPlaneConfig.NumCPUs,
+		NumCoresPerSocket:          controlPlaneConfig.NumCoresPerSocket,
+		FailureDomains:             failureDomains,
+		ControlPlaneFailureDomains: controlPlaneFailureDomains,
+		OvaFilePath:                cachedImage,
+		BootstrapNetworkKargs:      bootstrapNetworkKargs,
+		ControlPlaneNetworkKargs:   controlPlaneNetworkKargs,
 	}
 
 	return json.MarshalIndent(cfg, "", "  ")
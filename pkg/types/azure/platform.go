@@ -81,6 +81,13 @@ type Platform struct {
 	//
 	// +optional
 	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// UserTags additional keys and values that the installer will add
+	// as tags to all resources that it creates, including the machines
+	// of the machine sets. Resources created by the cluster itself may
+	// not include these tags.
+	// +optional
+	UserTags map[string]string `json:"userTags,omitempty"`
 }
 
 // CloudEnvironment is the name of the Azure cloud environment
@@ -2,7 +2,9 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -28,6 +30,15 @@ var (
 	}()
 )
 
+// ValidateCloudName checks that the cloud name is one of the supported Azure
+// cloud environments.
+func ValidateCloudName(cloudName azure.CloudEnvironment, fldPath *field.Path) field.ErrorList {
+	if !validCloudNames[cloudName] {
+		return field.ErrorList{field.NotSupported(fldPath, cloudName, validCloudNameValues)}
+	}
+	return nil
+}
+
 // ValidatePlatform checks that the specified platform is valid.
 func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -62,9 +73,8 @@ func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPa
 			allErrs = append(allErrs, field.Required(fldPath.Child("networkResourceGroupName"), "must provide a network resource group when supplying subnets"))
 		}
 	}
-	if !validCloudNames[p.CloudName] {
-		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cloudName"), p.CloudName, validCloudNameValues))
-	}
+	allErrs = append(allErrs, ValidateCloudName(p.CloudName, fldPath.Child("cloudName"))...)
+	allErrs = append(allErrs, validateUserTags(p.UserTags, fldPath.Child("userTags"))...)
 
 	if _, ok := validOutboundTypes[p.OutboundType]; !ok {
 		allErrs = append(allErrs, field.NotSupported(fldPath.Child("outboundType"), p.OutboundType, validOutboundTypeValues))
@@ -101,6 +111,49 @@ var (
 	}()
 )
 
+const maxUserTags = 10
+
+var (
+	// tagKeyRegex is the characters of the tag keys that every Azure
+	// resource accepts, including the storage accounts.
+	tagKeyRegex = regexp.MustCompile(`^[a-zA-Z][0-9A-Za-z_.-]{0,127}$`)
+
+	// tagValueRegex is the characters of the tag values that every Azure
+	// resource accepts.
+	tagValueRegex = regexp.MustCompile(`^[0-9A-Za-z_.=+@-]{1,256}$`)
+
+	// reservedTagKeyPrefixes are the prefixes of the tag keys used by
+	// Azure and by the cluster.
+	reservedTagKeyPrefixes = []string{"microsoft", "azure", "windows", "kubernetes.io", "openshift.io"}
+)
+
+func validateUserTags(tags map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(tags) > maxUserTags {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(tags), maxUserTags))
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := tags[key]
+		if !tagKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, "key must start with a letter, have at most 128 characters and contain only letters, digits, '_', '.' and '-'"))
+		}
+		for _, prefix := range reservedTagKeyPrefixes {
+			if strings.HasPrefix(strings.ToLower(key), prefix) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, fmt.Sprintf("key must not start with the reserved prefix %s", prefix)))
+			}
+		}
+		if !tagValueRegex.MatchString(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, "value must have between 1 and 256 characters and contain only letters, digits and '_.=+-@'"))
+		}
+	}
+	return allErrs
+}
+
 func validateAzureStack(p *azure.Platform, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if p.ARMEndpoint == "" {
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -151,6 +152,53 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expected: `^test-path\.outboundType: Invalid value: "UserDefinedRouting": UserDefinedRouting is only allowed when installing to pre-existing network$`,
 		},
+		{
+			name: "valid user tags",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.UserTags = map[string]string{"cost-center": "4.8_ocp", "owner": "user@example.com"}
+				return p
+			}(),
+		},
+		{
+			name: "user tag key with invalid characters",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.UserTags = map[string]string{"cost center": "42"}
+				return p
+			}(),
+			expected: `^test-path\.userTags\[cost center\]: Invalid value: "cost center": key must start with a letter, have at most 128 characters and contain only letters, digits, '_', '\.' and '-'$`,
+		},
+		{
+			name: "user tag key with reserved prefix",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.UserTags = map[string]string{"Microsoft.Owner": "user"}
+				return p
+			}(),
+			expected: `^test-path\.userTags\[Microsoft\.Owner\]: Invalid value: "Microsoft\.Owner": key must not start with the reserved prefix microsoft$`,
+		},
+		{
+			name: "user tag with empty value",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.UserTags = map[string]string{"owner": ""}
+				return p
+			}(),
+			expected: `^test-path\.userTags\[owner\]: Invalid value: "": value must have between 1 and 256 characters and contain only letters, digits and '_\.=\+-@'$`,
+		},
+		{
+			name: "too many user tags",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.UserTags = map[string]string{}
+				for i := 0; i < 11; i++ {
+					p.UserTags[fmt.Sprintf("key%d", i)] = "value"
+				}
+				return p
+			}(),
+			expected: `^test-path\.userTags: Too many: 11: must have at most 10 items$`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
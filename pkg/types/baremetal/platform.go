@@ -137,6 +137,13 @@ type Platform struct {
 	// Hosts is the information needed to create the objects in Ironic.
 	Hosts []*Host `json:"hosts"`
 
+	// HostInventory is the path of a CSV or YAML file listing more hosts.
+	// The boot MAC address, boot mode and root device hints that the
+	// inventory leaves out are discovered from the Redfish BMCs of the
+	// hosts. The hosts are added to Hosts when the install config is loaded.
+	// +optional
+	HostInventory string `json:"hostInventory,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on bare metal for machine pools which do not define their own
 	// platform configuration.
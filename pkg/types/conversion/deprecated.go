@@ -0,0 +1,25 @@
+package conversion
+
+// DeprecatedField is an install-config field that ConvertInstallConfig
+// upconverts to its replacement.
+type DeprecatedField struct {
+	// Path is the path of the field, with [*] standing for any item of a
+	// list.
+	Path string
+	// Replacement describes what replaces the field.
+	Replacement string
+}
+
+// DeprecatedFields are the deprecated fields of the install config.
+var DeprecatedFields = []DeprecatedField{
+	{Path: "networking.clusterNetworks", Replacement: "networking.clusterNetwork"},
+	{Path: "networking.clusterNetworks[*].hostSubnetLength", Replacement: "networking.clusterNetwork[*].hostPrefix"},
+	{Path: "networking.clusterNetwork[*].hostSubnetLength", Replacement: "networking.clusterNetwork[*].hostPrefix"},
+	{Path: "networking.machineCIDR", Replacement: "networking.machineNetwork"},
+	{Path: "networking.serviceCIDR", Replacement: "networking.serviceNetwork"},
+	{Path: "networking.type", Replacement: "networking.networkType"},
+	{Path: "platform.baremetal.provisioningDHCPExternal", Replacement: "platform.baremetal.provisioningNetwork set to Unmanaged"},
+	{Path: "platform.baremetal.provisioningHostIP", Replacement: "platform.baremetal.clusterProvisioningIP"},
+	{Path: "platform.openstack.computeFlavor", Replacement: "platform.openstack.defaultMachinePlatform.type"},
+	{Path: "platform.openstack.lbFloatingIP", Replacement: "platform.openstack.apiFloatingIP"},
+}
@@ -24,6 +24,10 @@ var (
 	defaultHostPrefix     = 23
 	defaultNetworkType    = string(operv1.NetworkTypeOpenShiftSDN)
 	defaultOKDNetworkType = string(operv1.NetworkTypeOVNKubernetes)
+	defaultKeySizes       = map[types.KeyAlgorithm]int{
+		types.RSAKeyAlgorithm:   2048,
+		types.ECDSAKeyAlgorithm: 256,
+	}
 )
 
 // SetInstallConfigDefaults sets the defaults for the install config.
@@ -64,6 +68,15 @@ func SetInstallConfigDefaults(c *types.InstallConfig) {
 		c.Publish = types.ExternalPublishingStrategy
 	}
 
+	if c.PKI != nil {
+		if c.PKI.KeyAlgorithm == "" {
+			c.PKI.KeyAlgorithm = types.RSAKeyAlgorithm
+		}
+		if c.PKI.KeySize == 0 {
+			c.PKI.KeySize = defaultKeySizes[c.PKI.KeyAlgorithm]
+		}
+	}
+
 	if c.ControlPlane == nil {
 		c.ControlPlane = &types.MachinePool{}
 	}
@@ -222,6 +222,39 @@ func TestSetInstallConfigDefaults(t *testing.T) {
 				return c
 			}(),
 		},
+		{
+			name: "PKI present",
+			config: &types.InstallConfig{
+				PKI: &types.PKI{},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.RSAKeyAlgorithm, KeySize: 2048}
+				return c
+			}(),
+		},
+		{
+			name: "ECDSA key algorithm present",
+			config: &types.InstallConfig{
+				PKI: &types.PKI{KeyAlgorithm: types.ECDSAKeyAlgorithm},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.ECDSAKeyAlgorithm, KeySize: 256}
+				return c
+			}(),
+		},
+		{
+			name: "key size present",
+			config: &types.InstallConfig{
+				PKI: &types.PKI{KeyAlgorithm: types.RSAKeyAlgorithm, KeySize: 4096},
+			},
+			expected: func() *types.InstallConfig {
+				c := defaultInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.RSAKeyAlgorithm, KeySize: 4096}
+				return c
+			}(),
+		},
 		{
 			name: "AWS platform present",
 			config: &types.InstallConfig{
@@ -37,4 +37,11 @@ type Platform struct {
 	// such as the current env OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE
 	// +optional
 	Licenses []string `json:"licenses,omitempty"`
+
+	// UserLabels additional keys and values that the installer will add
+	// as labels to all resources that it creates, including the machines
+	// of the machine sets. Resources created by the cluster itself may
+	// not include these labels.
+	// +optional
+	UserLabels map[string]string `json:"userLabels,omitempty"`
 }
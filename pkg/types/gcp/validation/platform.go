@@ -1,9 +1,11 @@
 package validation
 
 import (
+	"fmt"
 	"os"
-
+	"regexp"
 	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -88,5 +90,47 @@ func ValidatePlatform(p *gcp.Platform, fldPath *field.Path) field.ErrorList {
 		}
 	}
 
+	allErrs = append(allErrs, validateUserLabels(p.UserLabels, fldPath.Child("userLabels"))...)
+
+	return allErrs
+}
+
+const maxUserLabels = 32
+
+var (
+	// labelKeyRegex is the characters of the GCP label keys.
+	labelKeyRegex = regexp.MustCompile(`^[a-z][0-9a-z_-]{0,62}$`)
+
+	// labelValueRegex is the characters of the GCP label values.
+	labelValueRegex = regexp.MustCompile(`^[0-9a-z_-]{0,63}$`)
+
+	// reservedLabelKeyPrefixes are the prefixes of the label keys used by the cluster.
+	reservedLabelKeyPrefixes = []string{"kubernetes-io", "openshift-io"}
+)
+
+func validateUserLabels(labels map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(labels) > maxUserLabels {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(labels), maxUserLabels))
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := labels[key]
+		if !labelKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, "key must start with a lowercase letter, have at most 63 characters and contain only lowercase letters, digits, '_' and '-'"))
+		}
+		for _, prefix := range reservedLabelKeyPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, fmt.Sprintf("key must not start with the reserved prefix %s", prefix)))
+			}
+		}
+		if !labelValueRegex.MatchString(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, "value must have at most 63 characters and contain only lowercase letters, digits, '_' and '-'"))
+		}
+	}
 	return allErrs
 }
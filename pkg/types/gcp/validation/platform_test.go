@@ -88,6 +88,38 @@ func TestValidatePlatform(t *testing.T) {
 			},
 			valid: true,
 		},
+		{
+			name: "valid user labels",
+			platform: &gcp.Platform{
+				Region:     "us-east1",
+				UserLabels: map[string]string{"cost-center": "ocp_42", "empty": ""},
+			},
+			valid: true,
+		},
+		{
+			name: "user label key with uppercase letters",
+			platform: &gcp.Platform{
+				Region:     "us-east1",
+				UserLabels: map[string]string{"CostCenter": "42"},
+			},
+			valid: false,
+		},
+		{
+			name: "user label key with reserved prefix",
+			platform: &gcp.Platform{
+				Region:     "us-east1",
+				UserLabels: map[string]string{"openshift-io-cluster": "owned"},
+			},
+			valid: false,
+		},
+		{
+			name: "user label value with invalid characters",
+			platform: &gcp.Platform{
+				Region:     "us-east1",
+				UserLabels: map[string]string{"owner": "user@example.com"},
+			},
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
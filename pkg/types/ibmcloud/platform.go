@@ -37,6 +37,13 @@ type Platform struct {
 	// will create new subnets in the VPC network on your behalf.
 	// +optional
 	Subnets []string `json:"subnets,omitempty"`
+
+	// UserTags additional keys and values that the installer will add
+	// as key:value tags to all resources that it creates, including the
+	// machines of the machine sets. Resources created by the cluster
+	// itself may not include these tags.
+	// +optional
+	UserTags map[string]string `json:"userTags,omitempty"`
 }
 
 // ClusterResourceGroupName returns the name of the resource group for the cluster.
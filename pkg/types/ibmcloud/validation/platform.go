@@ -1,6 +1,10 @@
 package validation
 
 import (
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/openshift/installer/pkg/types/ibmcloud"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -43,6 +47,7 @@ func ValidatePlatform(p *ibmcloud.Platform, fldPath *field.Path) field.ErrorList
 	}
 
 	allErrs = append(allErrs, validateVPCConfig(p, fldPath)...)
+	allErrs = append(allErrs, validateUserTags(p.UserTags, fldPath.Child("userTags"))...)
 
 	if p.DefaultMachinePlatform != nil {
 		allErrs = append(allErrs, ValidateMachinePool(p.DefaultMachinePlatform, fldPath.Child("defaultMachinePlatform"))...)
@@ -65,3 +70,40 @@ func validateVPCConfig(p *ibmcloud.Platform, path *field.Path) field.ErrorList {
 	}
 	return allErrs
 }
+
+// maxTagLength is the maximum length of the key:value tags.
+const maxTagLength = 128
+
+var (
+	// tagKeyRegex is the characters of the IBM Cloud tags, without the
+	// colon that separates the key from the value.
+	tagKeyRegex = regexp.MustCompile(`^[A-Za-z0-9 _.-]+$`)
+
+	// tagValueRegex is the characters of the IBM Cloud tags.
+	tagValueRegex = regexp.MustCompile(`^[A-Za-z0-9 _.:-]+$`)
+)
+
+func validateUserTags(tags map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := tags[key]
+		if !tagKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, "key must not be empty and contain only letters, digits, spaces, '_', '.' and '-'"))
+		}
+		if strings.HasPrefix(strings.ToLower(key), "kubernetes.io") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, "key must not start with the reserved prefix kubernetes.io"))
+		}
+		if !tagValueRegex.MatchString(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, "value must not be empty and contain only letters, digits, spaces, '_', '.', ':' and '-'"))
+		}
+		if len(key)+1+len(value) > maxTagLength {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, "key:value tag must have at most 128 characters"))
+		}
+	}
+	return allErrs
+}
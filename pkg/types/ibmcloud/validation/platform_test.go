@@ -148,6 +148,42 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			valid: false,
 		},
+		{
+			name: "valid user tags",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.UserTags = map[string]string{"cost center": "ocp-4.8", "owner": "team:ocp"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "user tag key with colon",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.UserTags = map[string]string{"cost:center": "42"}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "user tag with empty value",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.UserTags = map[string]string{"owner": ""}
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "user tag with reserved prefix",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.UserTags = map[string]string{"kubernetes.io/cluster": "owned"}
+				return p
+			}(),
+			valid: false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -152,6 +152,11 @@ type InstallConfig struct {
 	// BootstrapInPlace is the configuration for installing a single node
 	// with bootstrap in place installation.
 	BootstrapInPlace *BootstrapInPlace `json:"bootstrapInPlace,omitempty"`
+
+	// PKI configures the private keys of the certificates and key pairs that the
+	// installer generates for the cluster.
+	// +optional
+	PKI *PKI `json:"pki,omitempty"`
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
@@ -370,6 +375,31 @@ type BootstrapInPlace struct {
 	InstallationDisk string `json:"installationDisk"`
 }
 
+// KeyAlgorithm is the algorithm of a generated private key.
+// +kubebuilder:validation:Enum="";RSA;ECDSA
+type KeyAlgorithm string
+
+const (
+	// RSAKeyAlgorithm generates RSA keys.
+	RSAKeyAlgorithm KeyAlgorithm = "RSA"
+	// ECDSAKeyAlgorithm generates ECDSA keys.
+	ECDSAKeyAlgorithm KeyAlgorithm = "ECDSA"
+)
+
+// PKI configures the private keys generated for the cluster.
+type PKI struct {
+	// KeyAlgorithm is the algorithm of the generated private keys.
+	// +kubebuilder:default=RSA
+	// +optional
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// KeySize is the size of the generated private keys: the modulus size in bits
+	// for RSA (2048, 3072 or 4096, default 2048), or the curve size for ECDSA
+	// (256 or 384 for P-256 or P-384, default 256).
+	// +optional
+	KeySize int `json:"keySize,omitempty"`
+}
+
 // WorkerMachinePool retrieves the worker MachinePool from InstallConfig.Compute
 func (c *InstallConfig) WorkerMachinePool() *MachinePool {
 	for _, machinePool := range c.Compute {
@@ -43,7 +43,7 @@ const (
 type MachinePool struct {
 	// Name is the name of the machine pool.
 	// For the control plane machine pool, the name will always be "master".
-	// For the compute machine pools, the only valid name is "worker".
+	// For the compute machine pools, the name is a DNS label, e.g. "worker".
 	Name string `json:"name"`
 
 	// Replicas is the machine count for the machine pool.
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	operv1 "github.com/openshift/api/operator/v1"
@@ -46,6 +47,7 @@ import (
 
 const (
 	masterPoolName = "master"
+	workerPoolName = "worker"
 )
 
 // list of known plugins that require hostPrefix to be set
@@ -125,6 +127,9 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 		allErrs = append(allErrs, field.NotSupported(field.NewPath("publish"), c.Publish, validPublishingStrategyValues))
 	}
 	allErrs = append(allErrs, validateCloudCredentialsMode(c.CredentialsMode, field.NewPath("credentialsMode"), c.Platform.Name())...)
+	if c.PKI != nil {
+		allErrs = append(allErrs, validatePKI(c.PKI, field.NewPath("pki"))...)
+	}
 
 	if c.Publish == types.InternalPublishingStrategy {
 		switch platformName := c.Platform.Name(); platformName {
@@ -403,6 +408,9 @@ func validateControlPlane(platform *types.Platform, pool *types.MachinePool, fld
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), pool.Replicas, "number of control plane replicas must be positive"))
 	}
 	allErrs = append(allErrs, ValidateMachinePool(platform, pool, fldPath)...)
+	if platform.VSphere != nil {
+		allErrs = append(allErrs, vspherevalidation.ValidateControlPlaneZones(platform.VSphere, pool.Platform.VSphere, fldPath.Child("platform", "vsphere", "zones"))...)
+	}
 	return allErrs
 }
 
@@ -411,8 +419,11 @@ func validateCompute(platform *types.Platform, control *types.MachinePool, pools
 	poolNames := map[string]bool{}
 	for i, p := range pools {
 		poolFldPath := fldPath.Index(i)
-		if p.Name != "worker" {
-			allErrs = append(allErrs, field.NotSupported(poolFldPath.Child("name"), p.Name, []string{"worker"}))
+		if p.Name == masterPoolName {
+			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("name"), p.Name, "the name is reserved for the control plane"))
+		}
+		for _, msg := range utilvalidation.IsDNS1123Label(p.Name) {
+			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("name"), p.Name, msg))
 		}
 		if poolNames[p.Name] {
 			allErrs = append(allErrs, field.Duplicate(poolFldPath.Child("name"), p.Name))
@@ -422,6 +433,37 @@ func validateCompute(platform *types.Platform, control *types.MachinePool, pools
 			allErrs = append(allErrs, field.Invalid(poolFldPath.Child("architecture"), p.Architecture, "heteregeneous multi-arch is not supported; compute pool architecture must match control plane"))
 		}
 		allErrs = append(allErrs, ValidateMachinePool(platform, &p, poolFldPath)...)
+		if platform.VSphere != nil {
+			var controlPlane *vsphere.MachinePool
+			if control != nil {
+				controlPlane = control.Platform.VSphere
+			}
+			allErrs = append(allErrs, vspherevalidation.ValidateComputeZones(platform.VSphere, controlPlane, p.Platform.VSphere, poolFldPath.Child("platform", "vsphere", "zones"))...)
+		}
+	}
+	allErrs = append(allErrs, validateComputeHyperthreading(pools, fldPath)...)
+	return allErrs
+}
+
+// validateComputeHyperthreading checks that custom compute pools do not enable
+// hyperthreading when the worker pool disables it. The machine config pools of
+// custom compute pools inherit the machine configs of the worker pool, so the
+// hyperthreading of the worker pool cannot be enabled again.
+func validateComputeHyperthreading(pools []types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	workerDisabled := false
+	for _, p := range pools {
+		if p.Name == workerPoolName && p.Hyperthreading == types.HyperthreadingDisabled {
+			workerDisabled = true
+		}
+	}
+	if !workerDisabled {
+		return allErrs
+	}
+	for i, p := range pools {
+		if p.Name != workerPoolName && p.Hyperthreading == types.HyperthreadingEnabled {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("hyperthreading"), p.Hyperthreading, "hyperthreading cannot be enabled in a compute pool when it is disabled in the worker pool"))
+		}
 	}
 	return allErrs
 }
@@ -466,7 +508,17 @@ func validatePlatform(platform *types.Platform, fldPath *field.Path, network *ty
 		})
 	}
 	if platform.VSphere != nil {
-		validate(vsphere.Name, platform.VSphere, func(f *field.Path) field.ErrorList { return vspherevalidation.ValidatePlatform(platform.VSphere, f) })
+		validate(vsphere.Name, platform.VSphere, func(f *field.Path) field.ErrorList {
+			var machineNetworks []types.MachineNetworkEntry
+			if network != nil {
+				machineNetworks = network.MachineNetwork
+			}
+			var controlPlaneReplicas *int64
+			if c.ControlPlane != nil {
+				controlPlaneReplicas = c.ControlPlane.Replicas
+			}
+			return vspherevalidation.ValidatePlatform(platform.VSphere, machineNetworks, controlPlaneReplicas, f)
+		})
 	}
 	if platform.BareMetal != nil {
 		validate(baremetal.Name, platform.BareMetal, func(f *field.Path) field.ErrorList {
@@ -594,6 +646,27 @@ func validateCloudCredentialsMode(mode types.CredentialsMode, fldPath *field.Pat
 	return allErrs
 }
 
+// validKeySizes are the supported sizes of the generated private keys, by algorithm.
+var validKeySizes = map[types.KeyAlgorithm][]int{
+	types.RSAKeyAlgorithm:   {2048, 3072, 4096},
+	types.ECDSAKeyAlgorithm: {256, 384},
+}
+
+func validatePKI(pki *types.PKI, fldPath *field.Path) field.ErrorList {
+	sizes, ok := validKeySizes[pki.KeyAlgorithm]
+	if !ok {
+		return field.ErrorList{field.NotSupported(fldPath.Child("keyAlgorithm"), pki.KeyAlgorithm, []string{string(types.RSAKeyAlgorithm), string(types.ECDSAKeyAlgorithm)})}
+	}
+	validSizes := make([]string, len(sizes))
+	for i, size := range sizes {
+		if size == pki.KeySize {
+			return nil
+		}
+		validSizes[i] = strconv.Itoa(size)
+	}
+	return field.ErrorList{field.NotSupported(fldPath.Child("keySize"), pki.KeySize, validSizes)}
+}
+
 // validateURI checks if the given url is of the right format. It also checks if the scheme of the uri
 // provided is within the list of accepted schema provided as part of the input.
 func validateURI(uri string, fldPath *field.Path, schemes []string) field.ErrorList {
@@ -482,6 +482,46 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `^compute\[1\]\.name: Duplicate value: "worker"$`,
 		},
+		{
+			name: "multiple compute pools",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute = []types.MachinePool{
+					*validMachinePool("worker"),
+					*validMachinePool("infra"),
+				}
+				return c
+			}(),
+		},
+		{
+			name: "compute pool named master",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute = []types.MachinePool{*validMachinePool("master")}
+				return c
+			}(),
+			expectedError: `^compute\[0\]\.name: Invalid value: "master": the name is reserved for the control plane$`,
+		},
+		{
+			name: "invalid compute pool name",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Compute = []types.MachinePool{*validMachinePool("Infra_Nodes")}
+				return c
+			}(),
+			expectedError: `^compute\[0\]\.name: Invalid value: "Infra_Nodes": a lowercase RFC 1123 label must consist of`,
+		},
+		{
+			name: "compute pool enables hyperthreading disabled in worker pool",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				infra := *validMachinePool("infra")
+				infra.Hyperthreading = types.HyperthreadingEnabled
+				c.Compute = []types.MachinePool{*validMachinePool("worker"), infra}
+				return c
+			}(),
+			expectedError: `^compute\[1\]\.hyperthreading: Invalid value: "Enabled": hyperthreading cannot be enabled in a compute pool when it is disabled in the worker pool$`,
+		},
 		{
 			name: "no compute replicas",
 			installConfig: func() *types.InstallConfig {
@@ -1259,6 +1299,41 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `^credentialsMode: Unsupported value: "bad-mode": supported values: "Manual", "Mint", "Passthrough"$`,
 		},
+		{
+			name: "valid ECDSA keys",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.ECDSAKeyAlgorithm, KeySize: 384}
+				return c
+			}(),
+		},
+		{
+			name: "unsupported key algorithm",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: "DSA", KeySize: 2048}
+				return c
+			}(),
+			expectedError: `^pki.keyAlgorithm: Unsupported value: "DSA": supported values: "RSA", "ECDSA"$`,
+		},
+		{
+			name: "unsupported RSA key size",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.RSAKeyAlgorithm, KeySize: 1024}
+				return c
+			}(),
+			expectedError: `^pki.keySize: Unsupported value: 1024: supported values: "2048", "3072", "4096"$`,
+		},
+		{
+			name: "unsupported ECDSA key size",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.PKI = &types.PKI{KeyAlgorithm: types.ECDSAKeyAlgorithm, KeySize: 4096}
+				return c
+			}(),
+			expectedError: `^pki.keySize: Unsupported value: 4096: supported values: "256", "384"$`,
+		},
 		{
 			name: "allowed docker bridge with non-libvirt",
 			installConfig: func() *types.InstallConfig {
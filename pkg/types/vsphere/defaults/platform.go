@@ -7,4 +7,19 @@ import (
 
 // SetPlatformDefaults sets the defaults for the platform.
 func SetPlatformDefaults(p *vsphere.Platform, installConfig *types.InstallConfig) {
+	for i := range p.FailureDomains {
+		if p.FailureDomains[i].Server == "" {
+			p.FailureDomains[i].Server = p.VCenter
+		}
+	}
+	for i := range p.Hosts {
+		device := &p.Hosts[i].NetworkDevice
+		if device.Bond != nil {
+			if device.Bond.Mode == "" {
+				device.Bond.Mode = "active-backup"
+			}
+		} else if device.Interface == "" {
+			device.Interface = vsphere.DefaultInterface
+		}
+	}
 }
@@ -27,6 +27,38 @@ func TestSetPlatformDefaults(t *testing.T) {
 			platform: &vsphere.Platform{},
 			expected: defaultPlatform(),
 		},
+		{
+			name: "failure domain server",
+			platform: &vsphere.Platform{
+				VCenter: "vcenter.example.com",
+				FailureDomains: []vsphere.FailureDomain{
+					{Name: "a"},
+					{Name: "b", Server: "vcenter-b.example.com"},
+				},
+			},
+			expected: &vsphere.Platform{
+				VCenter: "vcenter.example.com",
+				FailureDomains: []vsphere.FailureDomain{
+					{Name: "a", Server: "vcenter.example.com"},
+					{Name: "b", Server: "vcenter-b.example.com"},
+				},
+			},
+		},
+		{
+			name: "host network devices",
+			platform: &vsphere.Platform{
+				Hosts: []vsphere.Host{
+					{Role: vsphere.BootstrapRole},
+					{Role: vsphere.ControlPlaneRole, NetworkDevice: vsphere.NetworkDeviceSpec{Bond: &vsphere.Bond{Interfaces: []string{"ens192", "ens224"}}}},
+				},
+			},
+			expected: &vsphere.Platform{
+				Hosts: []vsphere.Host{
+					{Role: vsphere.BootstrapRole, NetworkDevice: vsphere.NetworkDeviceSpec{Interface: "ens192"}},
+					{Role: vsphere.ControlPlaneRole, NetworkDevice: vsphere.NetworkDeviceSpec{Bond: &vsphere.Bond{Interfaces: []string{"ens192", "ens224"}, Mode: "active-backup"}}},
+				},
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -23,6 +23,12 @@ type MachinePool struct {
 	//
 	// +optional
 	OSDisk `json:"osDisk"`
+
+	// Zones are the names of the failure domains the machines of the pool
+	// are spread across. Defaults to all the failure domains.
+	//
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
 // OSDisk defines the disk for a virtual machine.
@@ -54,4 +60,8 @@ func (p *MachinePool) Set(required *MachinePool) {
 	if required.OSDisk.DiskSizeGB != 0 {
 		p.OSDisk.DiskSizeGB = required.OSDisk.DiskSizeGB
 	}
+
+	if len(required.Zones) > 0 {
+		p.Zones = required.Zones
+	}
 }
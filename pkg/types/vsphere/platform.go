@@ -47,4 +47,172 @@ type Platform struct {
 
 	// Network specifies the name of the network to be used by the cluster.
 	Network string `json:"network,omitempty"`
+
+	// VCenters are the additional vCenters of the failure domains. The
+	// vCenter of VCenter, Username and Password is not listed.
+	// +optional
+	VCenters []VCenter `json:"vcenters,omitempty"`
+
+	// FailureDomains are the regions and zones the machines are spread
+	// across. When empty, all the machines are created in Datacenter,
+	// Cluster, DefaultDatastore and Network.
+	// +optional
+	FailureDomains []FailureDomain `json:"failureDomains,omitempty"`
+
+	// Hosts are the static network configurations of the hosts. The
+	// bootstrap and control-plane hosts are assigned to the machines in
+	// order, and the compute hosts are matched by MAC address. The
+	// machines without a host get their addresses from DHCP.
+	// +optional
+	Hosts []Host `json:"hosts,omitempty"`
+}
+
+// AllVCenters returns the vCenter of VCenter, Username and Password,
+// followed by VCenters.
+func (p *Platform) AllVCenters() []VCenter {
+	return append([]VCenter{{Server: p.VCenter, Username: p.Username, Password: p.Password}}, p.VCenters...)
+}
+
+// VCenter stores the connection details of a vCenter.
+type VCenter struct {
+	// Server is the domain name or IP address of the vCenter.
+	Server string `json:"server"`
+
+	// Username is the name of the user to use to connect to the vCenter.
+	Username string `json:"username"`
+
+	// Password is the password for the user to use to connect to the vCenter.
+	Password string `json:"password"`
+}
+
+const (
+	// RegionTagCategory is the tag category of the regions of the
+	// datacenters of the failure domains.
+	RegionTagCategory = "openshift-region"
+
+	// ZoneTagCategory is the tag category of the zones of the clusters of
+	// the failure domains.
+	ZoneTagCategory = "openshift-zone"
+)
+
+// FailureDomain maps a region and a zone to the vSphere objects of the
+// machines created in them.
+type FailureDomain struct {
+	// Name is the name of the failure domain, used by the zones of the
+	// machine pools.
+	Name string `json:"name"`
+
+	// Region is the name of the region tag of the datacenter of the
+	// failure domain, in the openshift-region tag category.
+	Region string `json:"region"`
+
+	// Zone is the name of the zone tag of the cluster of the failure
+	// domain, in the openshift-zone tag category.
+	Zone string `json:"zone"`
+
+	// Server is the domain name or IP address of the vCenter of the
+	// failure domain. It is VCenter or the server of one of VCenters.
+	// Defaults to VCenter.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// Topology is the vSphere objects of the machines of the failure domain.
+	Topology Topology `json:"topology"`
+}
+
+// Topology is the vSphere objects the machines are created in.
+type Topology struct {
+	// Datacenter is the name of the datacenter.
+	Datacenter string `json:"datacenter"`
+
+	// ComputeCluster is the name of the cluster virtual machines will be
+	// cloned into.
+	ComputeCluster string `json:"computeCluster"`
+
+	// Datastore is the name of the datastore of the virtual machines.
+	Datastore string `json:"datastore"`
+
+	// Network is the name of the network of the virtual machines.
+	Network string `json:"network"`
+
+	// Folder is the absolute path of the folder that will be used and/or
+	// created for virtual machines. The absolute path is of the form
+	// /<datacenter>/vm/<folder>/<subfolder>.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+}
+
+// HostRole is the role of the machine of a host.
+// +kubebuilder:validation:Enum=bootstrap;control-plane;compute
+type HostRole string
+
+const (
+	// BootstrapRole is the role of the bootstrap machine.
+	BootstrapRole HostRole = "bootstrap"
+
+	// ControlPlaneRole is the role of the control-plane machines.
+	ControlPlaneRole HostRole = "control-plane"
+
+	// ComputeRole is the role of the compute machines.
+	ComputeRole HostRole = "compute"
+)
+
+// DefaultInterface is the name of the network interface of the virtual
+// machines of RHCOS.
+const DefaultInterface = "ens192"
+
+// Host is the static network configuration of a machine.
+type Host struct {
+	// Role is the role of the machine of the host.
+	Role HostRole `json:"role"`
+
+	// NetworkDevice is the static network configuration of the machine.
+	NetworkDevice NetworkDeviceSpec `json:"networkDevice"`
+}
+
+// NetworkDeviceSpec is the static configuration of a network device, in
+// the form of the interfaces of nmstate.
+type NetworkDeviceSpec struct {
+	// Interface is the name of the network interface. Defaults to ens192.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+
+	// MACAddress is the MAC address of the network interface. It is
+	// required by the compute hosts, to match their machines.
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+
+	// IPAddrs are the IP addresses of the device, in CIDR notation.
+	IPAddrs []string `json:"ipAddrs"`
+
+	// Gateway is the IP address of the default gateway.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// Nameservers are the IP addresses of the DNS servers.
+	// +optional
+	Nameservers []string `json:"nameservers,omitempty"`
+
+	// Bond is the bond of network interfaces of the device. When set, the
+	// addresses are assigned to the bond instead of Interface.
+	// +optional
+	Bond *Bond `json:"bond,omitempty"`
+
+	// VLAN is the ID of the VLAN of the device. When set, the addresses are
+	// assigned to the VLAN interface.
+	// +optional
+	VLAN int `json:"vlan,omitempty"`
+}
+
+// BondName is the name of the bond interface.
+const BondName = "bond0"
+
+// Bond is a bond of network interfaces.
+type Bond struct {
+	// Interfaces are the names of the network interfaces of the bond.
+	Interfaces []string `json:"interfaces"`
+
+	// Mode is the bonding mode. Defaults to active-backup.
+	// +optional
+	Mode string `json:"mode,omitempty"`
 }
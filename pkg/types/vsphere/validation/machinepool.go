@@ -7,7 +7,7 @@ import (
 )
 
 // ValidateMachinePool checks that the specified machine pool is valid.
-func ValidateMachinePool(p *vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
+func ValidateMachinePool(platform *vsphere.Platform, p *vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if p.DiskSizeGB < 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("diskSizeGB"), p.DiskSizeGB, "storage disk size must be positive"))
@@ -21,5 +21,6 @@ func ValidateMachinePool(p *vsphere.MachinePool, fldPath *field.Path) field.Erro
 	if p.NumCoresPerSocket < 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("coresPerSocket"), p.NumCoresPerSocket, "cores per socket must be positive"))
 	}
+	allErrs = append(allErrs, validateZones(platform, p.Zones, fldPath.Child("zones"))...)
 	return allErrs
 }
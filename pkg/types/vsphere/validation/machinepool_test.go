@@ -13,6 +13,7 @@ func TestValidateMachinePool(t *testing.T) {
 	cases := []struct {
 		name           string
 		pool           *vsphere.MachinePool
+		platform       *vsphere.Platform
 		expectedErrMsg string
 	}{
 		{
@@ -45,11 +46,34 @@ func TestValidateMachinePool(t *testing.T) {
 				MemoryMiB: -1,
 			},
 			expectedErrMsg: `^test-path\.memoryMB: Invalid value: -1: memory size must be positive$`,
+		}, {
+			name: "zones",
+			pool: &vsphere.MachinePool{
+				Zones: []string{"a", "b"},
+			},
+			platform: validFailureDomainsPlatform(),
+		}, {
+			name: "unknown zone",
+			pool: &vsphere.MachinePool{
+				Zones: []string{"c"},
+			},
+			platform:       validFailureDomainsPlatform(),
+			expectedErrMsg: `^test-path\.zones\[0\]: Invalid value: "c": must be the name of a failure domain$`,
+		}, {
+			name: "zones without failure domains",
+			pool: &vsphere.MachinePool{
+				Zones: []string{"a"},
+			},
+			expectedErrMsg: `^test-path\.zones: Invalid value: \[\]string{"a"}: zones require failure domains$`,
 		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := ValidateMachinePool(tc.pool, field.NewPath("test-path")).ToAggregate()
+			platform := tc.platform
+			if platform == nil {
+				platform = validPlatform()
+			}
+			err := ValidateMachinePool(platform, tc.pool, field.NewPath("test-path")).ToAggregate()
 			if tc.expectedErrMsg == "" {
 				assert.NoError(t, err)
 			} else {
@@ -2,16 +2,21 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/vsphere"
 	"github.com/openshift/installer/pkg/validate"
 )
 
-// ValidatePlatform checks that the specified platform is valid.
-func ValidatePlatform(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
+// ValidatePlatform checks that the specified platform is valid. The addresses
+// of the hosts are checked against the machine networks, and the number of
+// control-plane hosts against the control-plane replicas, when they are set.
+func ValidatePlatform(p *vsphere.Platform, machineNetworks []types.MachineNetworkEntry, controlPlaneReplicas *int64, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if len(p.VCenter) == 0 {
 		allErrs = append(allErrs, field.Required(fldPath.Child("vCenter"), "must specify the name of the vCenter"))
@@ -45,6 +50,13 @@ func ValidatePlatform(p *vsphere.Platform, fldPath *field.Path) field.ErrorList
 		allErrs = append(allErrs, validateFolder(p, fldPath)...)
 	}
 
+	allErrs = append(allErrs, validateVCenters(p, fldPath.Child("vcenters"))...)
+	allErrs = append(allErrs, validateFailureDomains(p, fldPath.Child("failureDomains"))...)
+	if p.DefaultMachinePlatform != nil {
+		allErrs = append(allErrs, validateZones(p, p.DefaultMachinePlatform.Zones, fldPath.Child("defaultMachinePlatform", "zones"))...)
+	}
+	allErrs = append(allErrs, validateHosts(p, machineNetworks, controlPlaneReplicas, fldPath.Child("hosts"))...)
+
 	return allErrs
 }
 
@@ -52,12 +64,15 @@ func ValidatePlatform(p *vsphere.Platform, fldPath *field.Path) field.ErrorList
 func ValidateForProvisioning(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if len(p.Cluster) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("cluster"), "must specify the cluster"))
-	}
+	// The failure domains replace the cluster and the network of the machines.
+	if len(p.FailureDomains) == 0 {
+		if len(p.Cluster) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("cluster"), "must specify the cluster"))
+		}
 
-	if len(p.Network) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("network"), "must specify the network"))
+		if len(p.Network) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("network"), "must specify the network"))
+		}
 	}
 
 	allErrs = append(allErrs, validateVIPs(p, fldPath)...)
@@ -104,3 +119,269 @@ func validateFolder(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
 
 	return allErrs
 }
+
+// validateVCenters checks that the additional vCenters are valid and distinct.
+func validateVCenters(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	servers := sets.NewString(p.VCenter)
+	for i, vcenter := range p.VCenters {
+		vcenterPath := fldPath.Index(i)
+		if len(vcenter.Server) == 0 {
+			allErrs = append(allErrs, field.Required(vcenterPath.Child("server"), "must specify the name of the vCenter"))
+		} else if err := validate.Host(vcenter.Server); err != nil {
+			allErrs = append(allErrs, field.Invalid(vcenterPath.Child("server"), vcenter.Server, "must be the domain name or IP address of the vCenter"))
+		} else if servers.Has(vcenter.Server) {
+			allErrs = append(allErrs, field.Duplicate(vcenterPath.Child("server"), vcenter.Server))
+		}
+		servers.Insert(vcenter.Server)
+		if len(vcenter.Username) == 0 {
+			allErrs = append(allErrs, field.Required(vcenterPath.Child("username"), "must specify the username"))
+		}
+		if len(vcenter.Password) == 0 {
+			allErrs = append(allErrs, field.Required(vcenterPath.Child("password"), "must specify the password"))
+		}
+	}
+	return allErrs
+}
+
+// validateFailureDomains checks that the failure domains are distinct, are
+// in a known vCenter and have a complete topology.
+func validateFailureDomains(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	servers := sets.NewString(p.VCenter)
+	for _, vcenter := range p.VCenters {
+		servers.Insert(vcenter.Server)
+	}
+	names := sets.NewString()
+	zones := sets.NewString()
+	for i, fd := range p.FailureDomains {
+		fdPath := fldPath.Index(i)
+		if len(fd.Name) == 0 {
+			allErrs = append(allErrs, field.Required(fdPath.Child("name"), "must specify the name of the failure domain"))
+		} else if names.Has(fd.Name) {
+			allErrs = append(allErrs, field.Duplicate(fdPath.Child("name"), fd.Name))
+		}
+		names.Insert(fd.Name)
+		if len(fd.Region) == 0 {
+			allErrs = append(allErrs, field.Required(fdPath.Child("region"), "must specify the region"))
+		}
+		if len(fd.Zone) == 0 {
+			allErrs = append(allErrs, field.Required(fdPath.Child("zone"), "must specify the zone"))
+		} else if zones.Has(fd.Region + "/" + fd.Zone) {
+			allErrs = append(allErrs, field.Duplicate(fdPath.Child("zone"), fd.Zone))
+		}
+		zones.Insert(fd.Region + "/" + fd.Zone)
+		if len(fd.Server) != 0 && !servers.Has(fd.Server) {
+			allErrs = append(allErrs, field.Invalid(fdPath.Child("server"), fd.Server, "must be the vCenter of the platform or one of the vcenters"))
+		}
+
+		topologyPath := fdPath.Child("topology")
+		if len(fd.Topology.Datacenter) == 0 {
+			allErrs = append(allErrs, field.Required(topologyPath.Child("datacenter"), "must specify the datacenter"))
+		}
+		if len(fd.Topology.ComputeCluster) == 0 {
+			allErrs = append(allErrs, field.Required(topologyPath.Child("computeCluster"), "must specify the cluster"))
+		}
+		if len(fd.Topology.Datastore) == 0 {
+			allErrs = append(allErrs, field.Required(topologyPath.Child("datastore"), "must specify the datastore"))
+		}
+		if len(fd.Topology.Network) == 0 {
+			allErrs = append(allErrs, field.Required(topologyPath.Child("network"), "must specify the network"))
+		}
+		if len(fd.Topology.Folder) != 0 && len(fd.Topology.Datacenter) != 0 {
+			expectedPrefix := fmt.Sprintf("/%s/vm/", fd.Topology.Datacenter)
+			if !strings.HasPrefix(fd.Topology.Folder, expectedPrefix) {
+				errMsg := fmt.Sprintf("folder must be absolute path: expected prefix %s", expectedPrefix)
+				allErrs = append(allErrs, field.Invalid(topologyPath.Child("folder"), fd.Topology.Folder, errMsg))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateZones checks that the zones of a machine pool are failure domains.
+func validateZones(p *vsphere.Platform, zones []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(zones) == 0 {
+		return allErrs
+	}
+	if len(p.FailureDomains) == 0 {
+		return append(allErrs, field.Invalid(fldPath, zones, "zones require failure domains"))
+	}
+	names := sets.NewString()
+	for _, fd := range p.FailureDomains {
+		names.Insert(fd.Name)
+	}
+	for i, zone := range zones {
+		if !names.Has(zone) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), zone, "must be the name of a failure domain"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateControlPlaneZones checks that the failure domains of the control
+// plane are in the vCenter of the platform, where the installer creates the
+// control-plane machines.
+func ValidateControlPlaneZones(p *vsphere.Platform, pool *vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, zone := range poolZones(p, pool) {
+		for _, fd := range p.FailureDomains {
+			if fd.Name == zone && fd.Server != "" && fd.Server != p.VCenter {
+				allErrs = append(allErrs, field.Invalid(fldPath, zone, fmt.Sprintf("control-plane machines must be in failure domains of the vCenter %s", p.VCenter)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateComputeZones checks that the failure domains of a compute pool are
+// failure domains of the control plane. The installer only imports the RHCOS
+// template of the compute machines in the failure domains of the control plane.
+func ValidateComputeZones(p *vsphere.Platform, controlPlane *vsphere.MachinePool, pool *vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	controlPlaneZones := sets.NewString(poolZones(p, controlPlane)...)
+	for _, zone := range poolZones(p, pool) {
+		if !controlPlaneZones.Has(zone) {
+			allErrs = append(allErrs, field.Invalid(fldPath, zone, "compute machines must be in failure domains of the control plane"))
+		}
+	}
+	return allErrs
+}
+
+// poolZones returns the names of the failure domains of the machine pool,
+// which default to the zones of the default machine platform and then to all
+// the failure domains.
+func poolZones(p *vsphere.Platform, pool *vsphere.MachinePool) []string {
+	switch {
+	case pool != nil && len(pool.Zones) > 0:
+		return pool.Zones
+	case p.DefaultMachinePlatform != nil && len(p.DefaultMachinePlatform.Zones) > 0:
+		return p.DefaultMachinePlatform.Zones
+	}
+	zones := []string{}
+	for _, fd := range p.FailureDomains {
+		zones = append(zones, fd.Name)
+	}
+	return zones
+}
+
+// validateHosts checks that the static network configurations of the hosts
+// are valid, that their addresses are distinct, in the machine networks and
+// not the VIPs, and that there is a host for each control-plane machine.
+func validateHosts(p *vsphere.Platform, machineNetworks []types.MachineNetworkEntry, controlPlaneReplicas *int64, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	roles := []string{string(vsphere.BootstrapRole), string(vsphere.ControlPlaneRole), string(vsphere.ComputeRole)}
+	vips := sets.NewString(p.APIVIP, p.IngressVIP)
+	addresses := sets.NewString()
+	macAddresses := sets.NewString()
+	bootstrapHosts, controlPlaneHosts := 0, 0
+	for i, host := range p.Hosts {
+		hostPath := fldPath.Index(i)
+		switch host.Role {
+		case vsphere.BootstrapRole:
+			bootstrapHosts++
+			if bootstrapHosts > 1 {
+				allErrs = append(allErrs, field.Invalid(hostPath.Child("role"), host.Role, "there can be only one bootstrap host"))
+			}
+		case vsphere.ControlPlaneRole:
+			controlPlaneHosts++
+		case vsphere.ComputeRole:
+		default:
+			allErrs = append(allErrs, field.NotSupported(hostPath.Child("role"), host.Role, roles))
+		}
+
+		device := host.NetworkDevice
+		devicePath := hostPath.Child("networkDevice")
+		if device.MACAddress != "" {
+			if err := validate.MAC(device.MACAddress); err != nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("macAddress"), device.MACAddress, err.Error()))
+			} else if macAddresses.Has(strings.ToLower(device.MACAddress)) {
+				allErrs = append(allErrs, field.Duplicate(devicePath.Child("macAddress"), device.MACAddress))
+			}
+			macAddresses.Insert(strings.ToLower(device.MACAddress))
+		}
+		if host.Role == vsphere.ComputeRole {
+			// The compute hosts are matched to their machines by MAC
+			// address, and configured with a keyfile per interface.
+			if device.MACAddress == "" {
+				allErrs = append(allErrs, field.Required(devicePath.Child("macAddress"), "must specify the MAC address of the compute hosts"))
+			}
+			if device.Bond != nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("bond"), device.Bond, "bonds are only supported on the bootstrap and control-plane hosts"))
+			}
+			if device.VLAN != 0 {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("vlan"), device.VLAN, "VLANs are only supported on the bootstrap and control-plane hosts"))
+			}
+		}
+		if device.Bond != nil {
+			if device.Interface != "" {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("interface"), device.Interface, "interface and bond cannot both be set"))
+			}
+			if len(device.Bond.Interfaces) == 0 {
+				allErrs = append(allErrs, field.Required(devicePath.Child("bond", "interfaces"), "must specify the interfaces of the bond"))
+			}
+		}
+		if device.VLAN < 0 || device.VLAN > 4094 {
+			allErrs = append(allErrs, field.Invalid(devicePath.Child("vlan"), device.VLAN, "must be between 1 and 4094"))
+		}
+
+		var subnets []*net.IPNet
+		if len(device.IPAddrs) == 0 {
+			allErrs = append(allErrs, field.Required(devicePath.Child("ipAddrs"), "must specify the IP addresses of the host"))
+		}
+		for j, addr := range device.IPAddrs {
+			addrPath := devicePath.Child("ipAddrs").Index(j)
+			ip, subnet, err := net.ParseCIDR(addr)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(addrPath, addr, "must be an IP address in CIDR notation"))
+				continue
+			}
+			subnets = append(subnets, subnet)
+			switch {
+			case addresses.Has(ip.String()):
+				allErrs = append(allErrs, field.Duplicate(addrPath, addr))
+			case vips.Has(ip.String()):
+				allErrs = append(allErrs, field.Invalid(addrPath, addr, "must not be the apiVIP or the ingressVIP"))
+			case len(machineNetworks) > 0 && !inMachineNetworks(ip, machineNetworks):
+				allErrs = append(allErrs, field.Invalid(addrPath, addr, "must be in one of the machine networks"))
+			}
+			addresses.Insert(ip.String())
+		}
+		if device.Gateway != "" {
+			if gateway := net.ParseIP(device.Gateway); gateway == nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("gateway"), device.Gateway, "must be an IP address"))
+			} else if !inSubnets(gateway, subnets) {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("gateway"), device.Gateway, "must be in the subnet of one of the ipAddrs"))
+			}
+		}
+		for j, nameserver := range device.Nameservers {
+			if err := validate.IP(nameserver); err != nil {
+				allErrs = append(allErrs, field.Invalid(devicePath.Child("nameservers").Index(j), nameserver, err.Error()))
+			}
+		}
+	}
+
+	if controlPlaneHosts > 0 && controlPlaneReplicas != nil && int64(controlPlaneHosts) != *controlPlaneReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath, controlPlaneHosts, fmt.Sprintf("the number of control-plane hosts must match the %d control-plane replicas", *controlPlaneReplicas)))
+	}
+	return allErrs
+}
+
+func inMachineNetworks(ip net.IP, machineNetworks []types.MachineNetworkEntry) bool {
+	for _, network := range machineNetworks {
+		if network.CIDR.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func inSubnets(ip net.IP, subnets []*net.IPNet) bool {
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/vsphere"
 )
 
@@ -19,6 +21,66 @@ func validPlatform() *vsphere.Platform {
 	}
 }
 
+func validFailureDomainsPlatform() *vsphere.Platform {
+	p := validPlatform()
+	p.VCenters = []vsphere.VCenter{{
+		Server:   "test-vcenter-b",
+		Username: "test-username",
+		Password: "test-password",
+	}}
+	p.FailureDomains = []vsphere.FailureDomain{
+		{
+			Name:   "a",
+			Region: "us-east",
+			Zone:   "us-east-1a",
+			Server: "test-vcenter",
+			Topology: vsphere.Topology{
+				Datacenter:     "test-datacenter",
+				ComputeCluster: "test-cluster-a",
+				Datastore:      "test-datastore-a",
+				Network:        "test-network",
+			},
+		},
+		{
+			Name:   "b",
+			Region: "us-east",
+			Zone:   "us-east-1b",
+			Server: "test-vcenter-b",
+			Topology: vsphere.Topology{
+				Datacenter:     "test-datacenter-b",
+				ComputeCluster: "test-cluster-b",
+				Datastore:      "test-datastore-b",
+				Network:        "test-network",
+				Folder:         "/test-datacenter-b/vm/test-folder",
+			},
+		},
+	}
+	return p
+}
+
+func validHostsPlatform() *vsphere.Platform {
+	p := validPlatform()
+	p.APIVIP = "10.0.0.2"
+	p.IngressVIP = "10.0.0.3"
+	device := func(addr string) vsphere.NetworkDeviceSpec {
+		return vsphere.NetworkDeviceSpec{
+			Interface:   "ens192",
+			IPAddrs:     []string{addr},
+			Gateway:     "10.0.0.1",
+			Nameservers: []string{"10.0.0.1"},
+		}
+	}
+	p.Hosts = []vsphere.Host{
+		{Role: vsphere.BootstrapRole, NetworkDevice: device("10.0.0.10/24")},
+		{Role: vsphere.ControlPlaneRole, NetworkDevice: device("10.0.0.11/24")},
+		{Role: vsphere.ControlPlaneRole, NetworkDevice: device("10.0.0.12/24")},
+		{Role: vsphere.ControlPlaneRole, NetworkDevice: device("10.0.0.13/24")},
+		{Role: vsphere.ComputeRole, NetworkDevice: device("10.0.0.20/24")},
+	}
+	p.Hosts[4].NetworkDevice.MACAddress = "00:50:56:00:00:20"
+	return p
+}
+
 func TestValidatePlatform(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -152,10 +214,301 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expectedError: `^test-path\.vCenter: Invalid value: "https://test-center": must be the domain name or IP address of the vCenter$`,
 		},
+		{
+			name:     "valid failure domains",
+			platform: validFailureDomainsPlatform(),
+		},
+		{
+			name: "duplicate vCenter",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.VCenters[0].Server = "test-vcenter"
+				p.FailureDomains = nil
+				return p
+			}(),
+			expectedError: `^test-path\.vcenters\[0\]\.server: Duplicate value: "test-vcenter"$`,
+		},
+		{
+			name: "missing vCenter password",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.VCenters[0].Password = ""
+				return p
+			}(),
+			expectedError: `^test-path\.vcenters\[0\]\.password: Required value: must specify the password$`,
+		},
+		{
+			name: "duplicate failure domain",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.FailureDomains[1].Name = "a"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\[1\]\.name: Duplicate value: "a"$`,
+		},
+		{
+			name: "failure domain in unknown vCenter",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.FailureDomains[1].Server = "test-vcenter-c"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\[1\]\.server: Invalid value: "test-vcenter-c": must be the vCenter of the platform or one of the vcenters$`,
+		},
+		{
+			name: "missing failure domain cluster",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.FailureDomains[0].Topology.ComputeCluster = ""
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\[0\]\.topology\.computeCluster: Required value: must specify the cluster$`,
+		},
+		{
+			name: "failure domain folder in another datacenter",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.FailureDomains[1].Topology.Folder = "/test-datacenter/vm/test-folder"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\[1\]\.topology\.folder: Invalid value: "/test-datacenter/vm/test-folder": folder must be absolute path: expected prefix /test-datacenter-b/vm/$`,
+		},
+		{
+			name: "unknown default zone",
+			platform: func() *vsphere.Platform {
+				p := validFailureDomainsPlatform()
+				p.DefaultMachinePlatform = &vsphere.MachinePool{Zones: []string{"c"}}
+				return p
+			}(),
+			expectedError: `^test-path\.defaultMachinePlatform\.zones\[0\]: Invalid value: "c": must be the name of a failure domain$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePlatform(tc.platform, nil, nil, field.NewPath("test-path")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateHosts(t *testing.T) {
+	machineNetworks := []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/24")}}
+	replicas := int64(3)
+	cases := []struct {
+		name          string
+		platform      *vsphere.Platform
+		expectedError string
+	}{
+		{
+			name:     "valid hosts",
+			platform: validHostsPlatform(),
+		},
+		{
+			name: "bonded VLAN host",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[1].NetworkDevice.Interface = ""
+				p.Hosts[1].NetworkDevice.Bond = &vsphere.Bond{Interfaces: []string{"ens192", "ens224"}, Mode: "active-backup"}
+				p.Hosts[1].NetworkDevice.VLAN = 100
+				return p
+			}(),
+		},
+		{
+			name: "unknown host role",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[4].Role = "worker"
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[4\]\.role: Unsupported value: "worker": supported values: "bootstrap", "control-plane", "compute"$`,
+		},
+		{
+			name: "two bootstrap hosts",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[4].Role = vsphere.BootstrapRole
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[4\]\.role: Invalid value: "bootstrap": there can be only one bootstrap host$`,
+		},
+		{
+			name: "missing control-plane host",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts = p.Hosts[:3]
+				return p
+			}(),
+			expectedError: `^test-path\.hosts: Invalid value: 2: the number of control-plane hosts must match the 3 control-plane replicas$`,
+		},
+		{
+			name: "missing IP addresses",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[1].NetworkDevice.IPAddrs = nil
+				p.Hosts[1].NetworkDevice.Gateway = ""
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[1\]\.networkDevice\.ipAddrs: Required value: must specify the IP addresses of the host$`,
+		},
+		{
+			name: "IP address without prefix",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[1].NetworkDevice.IPAddrs = []string{"10.0.0.11"}
+				p.Hosts[1].NetworkDevice.Gateway = ""
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[1\]\.networkDevice\.ipAddrs\[0\]: Invalid value: "10\.0\.0\.11": must be an IP address in CIDR notation$`,
+		},
+		{
+			name: "duplicate IP address",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[2].NetworkDevice.IPAddrs = []string{"10.0.0.11/24"}
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[2\]\.networkDevice\.ipAddrs\[0\]: Duplicate value: "10\.0\.0\.11/24"$`,
+		},
+		{
+			name: "IP address of a VIP",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[2].NetworkDevice.IPAddrs = []string{"10.0.0.2/24"}
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[2\]\.networkDevice\.ipAddrs\[0\]: Invalid value: "10\.0\.0\.2/24": must not be the apiVIP or the ingressVIP$`,
+		},
+		{
+			name: "IP address outside the machine networks",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[2].NetworkDevice.IPAddrs = []string{"10.1.0.12/16"}
+				p.Hosts[2].NetworkDevice.Gateway = "10.1.0.1"
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[2\]\.networkDevice\.ipAddrs\[0\]: Invalid value: "10\.1\.0\.12/16": must be in one of the machine networks$`,
+		},
+		{
+			name: "gateway outside the subnet",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[2].NetworkDevice.Gateway = "10.1.0.1"
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[2\]\.networkDevice\.gateway: Invalid value: "10\.1\.0\.1": must be in the subnet of one of the ipAddrs$`,
+		},
+		{
+			name: "invalid nameserver",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[2].NetworkDevice.Nameservers = []string{"dns.example.com"}
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[2\]\.networkDevice\.nameservers\[0\]: Invalid value: "dns\.example\.com": "dns\.example\.com" is not a valid IP$`,
+		},
+		{
+			name: "compute host without MAC address",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[4].NetworkDevice.MACAddress = ""
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[4\]\.networkDevice\.macAddress: Required value: must specify the MAC address of the compute hosts$`,
+		},
+		{
+			name: "compute host with VLAN",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[4].NetworkDevice.VLAN = 100
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[4\]\.networkDevice\.vlan: Invalid value: 100: VLANs are only supported on the bootstrap and control-plane hosts$`,
+		},
+		{
+			name: "bond with interface",
+			platform: func() *vsphere.Platform {
+				p := validHostsPlatform()
+				p.Hosts[1].NetworkDevice.Bond = &vsphere.Bond{Interfaces: []string{"ens192", "ens224"}}
+				return p
+			}(),
+			expectedError: `^test-path\.hosts\[1\]\.networkDevice\.interface: Invalid value: "ens192": interface and bond cannot both be set$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePlatform(tc.platform, machineNetworks, &replicas, field.NewPath("test-path")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateControlPlaneZones(t *testing.T) {
+	cases := []struct {
+		name          string
+		pool          *vsphere.MachinePool
+		expectedError string
+	}{
+		{
+			name: "zones in the vCenter",
+			pool: &vsphere.MachinePool{Zones: []string{"a"}},
+		},
+		{
+			name:          "zones in another vCenter",
+			pool:          &vsphere.MachinePool{Zones: []string{"a", "b"}},
+			expectedError: `^test-path: Invalid value: "b": control-plane machines must be in failure domains of the vCenter test-vcenter$`,
+		},
+		{
+			name:          "default zones",
+			expectedError: `^test-path: Invalid value: "b": control-plane machines must be in failure domains of the vCenter test-vcenter$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateControlPlaneZones(validFailureDomainsPlatform(), tc.pool, field.NewPath("test-path")).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateComputeZones(t *testing.T) {
+	cases := []struct {
+		name          string
+		controlPlane  *vsphere.MachinePool
+		pool          *vsphere.MachinePool
+		expectedError string
+	}{
+		{
+			name:         "zones of the control plane",
+			controlPlane: &vsphere.MachinePool{Zones: []string{"a"}},
+			pool:         &vsphere.MachinePool{Zones: []string{"a"}},
+		},
+		{
+			name:          "zones outside the control plane",
+			controlPlane:  &vsphere.MachinePool{Zones: []string{"a"}},
+			pool:          &vsphere.MachinePool{Zones: []string{"a", "b"}},
+			expectedError: `^test-path: Invalid value: "b": compute machines must be in failure domains of the control plane$`,
+		},
+		{
+			name:          "default zones",
+			controlPlane:  &vsphere.MachinePool{Zones: []string{"a"}},
+			expectedError: `^test-path: Invalid value: "b": compute machines must be in failure domains of the control plane$`,
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := ValidatePlatform(tc.platform, field.NewPath("test-path")).ToAggregate()
+			err := ValidateComputeZones(validFailureDomainsPlatform(), tc.controlPlane, tc.pool, field.NewPath("test-path")).ToAggregate()
 			if tc.expectedError == "" {
 				assert.NoError(t, err)
 			} else {